@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	google_grpc "google.golang.org/grpc"
@@ -48,11 +50,91 @@ func main() {
 	repo := repository.NewMariaDBRepository(db)
 
 	// UseCases
-	transferUC := usecase.NewTransferUseCase(repo, repo, repo)
-	accountUC := usecase.NewAccountUseCase(repo, repo)
+	intraTreePolicy, err := usecase.ParseIntraTreeTransferPolicy(os.Getenv("INTRA_TREE_TRANSFERS"))
+	if err != nil {
+		log.Fatalf("invalid INTRA_TREE_TRANSFERS: %v", err)
+	}
+	defaultLimits, err := usecase.ParseVelocityLimits(
+		os.Getenv("DEFAULT_MAX_PER_TRANSFER"),
+		os.Getenv("DEFAULT_MAX_OUTGOING_PER_DAY"),
+		os.Getenv("DEFAULT_MAX_TRANSFERS_PER_HOUR"),
+	)
+	if err != nil {
+		log.Fatalf("invalid default velocity limits: %v", err)
+	}
+	lotUC := usecase.NewPointLotUseCase(repo, repo, repo, repo, repo)
+	transferUC := usecase.NewTransferUseCase(repo, repo, repo,
+		usecase.WithIntraTreeTransferPolicy(intraTreePolicy),
+		usecase.WithFeeRules(repo),
+		usecase.WithVelocityLimits(repo, defaultLimits),
+		usecase.WithPointLots(lotUC),
+	)
+	accountUC := usecase.NewAccountUseCase(repo, repo, repo, repo)
+	assetUC := usecase.NewAssetUseCase(repo, repo, repo)
+	issuanceUC := usecase.NewIssuanceUseCase(repo, repo, repo, repo, lotUC)
+	holdUC := usecase.NewHoldUseCase(repo, repo, repo, repo, lotUC, usecase.WithCaptureVelocityLimits(transferUC))
+	scheduleUC := usecase.NewScheduledTransferUseCase(transferUC, repo, repo, repo)
+	standingUC := usecase.NewStandingOrderUseCase(transferUC, repo, repo, repo)
+	escrowUC := usecase.NewEscrowUseCase(repo, repo, repo, repo, repo, lotUC, usecase.WithEscrowVelocityLimits(transferUC))
+	feeUC := usecase.NewFeeUseCase(repo, repo)
+	paymentUC := usecase.NewPaymentRequestUseCase(transferUC, repo, repo, repo)
+
+	// Background jobs
+	go runPeriodically("hold expiry", time.Minute, func(ctx context.Context) error {
+		n, err := holdUC.ExpireHolds(ctx, time.Now())
+		if n > 0 {
+			log.Printf("released %d expired hold(s)", n)
+		}
+		return err
+	})
+	go runPeriodically("scheduled transfers", time.Minute, func(ctx context.Context) error {
+		n, err := scheduleUC.ExecuteDue(ctx, time.Now())
+		if n > 0 {
+			log.Printf("settled %d scheduled transfer(s)", n)
+		}
+		return err
+	})
+	go runPeriodically("standing orders", time.Minute, func(ctx context.Context) error {
+		n, err := standingUC.RunDue(ctx, time.Now())
+		if n > 0 {
+			log.Printf("ran %d standing order(s)", n)
+		}
+		return err
+	})
+	go runPeriodically("point expiry", time.Minute, func(ctx context.Context) error {
+		n, err := lotUC.ExpireLots(ctx, time.Now())
+		if n > 0 {
+			log.Printf("expired %d point lot(s)", n)
+		}
+		return err
+	})
+	go runPeriodically("escrow refunds", time.Minute, func(ctx context.Context) error {
+		n, err := escrowUC.RefundExpired(ctx, time.Now())
+		if n > 0 {
+			log.Printf("refunded %d expired escrow(s)", n)
+		}
+		return err
+	})
+	go runPeriodically("payment request expiry", time.Minute, func(ctx context.Context) error {
+		n, err := paymentUC.ExpireDue(ctx, time.Now())
+		if n > 0 {
+			log.Printf("expired %d payment request(s)", n)
+		}
+		return err
+	})
 
 	// Handlers
-	h := grpc.NewCornucopiaHandler(transferUC, accountUC)
+	h := grpc.NewCornucopiaHandler(transferUC, accountUC,
+		grpc.WithAssets(assetUC),
+		grpc.WithIssuance(issuanceUC),
+		grpc.WithHolds(holdUC),
+		grpc.WithScheduledTransfers(scheduleUC),
+		grpc.WithStandingOrders(standingUC),
+		grpc.WithEscrows(escrowUC),
+		grpc.WithFeeRules(feeUC),
+		grpc.WithPointLots(lotUC),
+		grpc.WithPaymentRequests(paymentUC),
+	)
 
 	// API Key Authentication
 	var apiKeys []string
@@ -80,3 +162,14 @@ func main() {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
+
+// runPeriodically calls fn every interval for the lifetime of the process, logging failures.
+func runPeriodically(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := fn(context.Background()); err != nil {
+			log.Printf("%s: %v", name, err)
+		}
+	}
+}
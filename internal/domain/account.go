@@ -14,43 +14,227 @@ func (id AccountID) String() string {
 	return uuid.UUID(id).String()
 }
 
+// AccountStatus represents the lifecycle state of an account.
+type AccountStatus string
+
+const (
+	// AccountStatusActive accounts can send and receive points.
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusFrozen accounts can receive but not send points.
+	AccountStatusFrozen AccountStatus = "frozen"
+	// AccountStatusClosed accounts can neither send nor receive points. Closing is final.
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+// MaxOwnerIDLength is the maximum allowed length of an owner reference.
+const MaxOwnerIDLength = 255
+
+// UnlimitedCreditLimit is the credit limit of accounts that may overdraw without bound.
+const UnlimitedCreditLimit int64 = math.MaxInt64
+
 // Account represents a points account.
 type Account struct {
-	ID           AccountID
-	Balance      int64
-	CanOverdraft bool
+	ID AccountID
+	// Asset is the point type held by the account.
+	Asset   AssetCode
+	Balance int64
+	// HeldBalance is the sum of the account's authorized holds. Held points stay in
+	// Balance but cannot be withdrawn until the hold is captured or released.
+	HeldBalance int64
+	// CreditLimit is how far below zero the balance may go.
+	CreditLimit int64
+	// MaxBalance caps the balance. Nil means no cap besides int64 overflow.
+	MaxBalance *int64
+	Status     AccountStatus
+	// Labels are arbitrary key/value metadata, e.g. "kind": "event-pool".
+	Labels map[string]string
+	// OwnerID is an optional external reference to the owner, e.g. a traQ user ID.
+	// An owner may have many accounts. Empty means no owner.
+	OwnerID string
+	// Alias is an optional unique, changeable name referenced as "@alias". Empty means no alias.
+	Alias string
+	// ParentID is the account this sub-account belongs to. Nil for top-level accounts.
+	// It is set at creation and never changes.
+	ParentID *AccountID
+	// Version counts the saves of the account, so that clients can detect any change
+	// between reading the account and acting on it. The repository increments it.
+	Version int64
+	// System is set on the issuer and escrow accounts of assets. Only the entry types that
+	// belong to them may move their points, and their settings cannot be changed.
+	System bool
 }
 
-// NewAccount creates a new account with 0 balance.
-func NewAccount(id AccountID, canOverdraft bool) *Account {
+// NewAccount creates a new active account with 0 balance.
+func NewAccount(id AccountID, creditLimit int64) *Account {
 	return &Account{
-		ID:           id,
-		Balance:      0,
-		CanOverdraft: canOverdraft,
+		ID:          id,
+		Balance:     0,
+		CreditLimit: creditLimit,
+		Status:      AccountStatusActive,
 	}
 }
 
+// CanOverdraft reports whether the balance may go below zero.
+func (a *Account) CanOverdraft() bool {
+	return a.CreditLimit > 0
+}
+
+// spendable returns the largest amount that can be withdrawn or held, net of existing holds.
+// It saturates at math.MaxInt64 and math.MinInt64.
+func (a *Account) spendable() int64 {
+	limit := a.Balance + a.CreditLimit
+	if a.Balance > 0 && a.CreditLimit > math.MaxInt64-a.Balance {
+		limit = math.MaxInt64
+	}
+	if limit < math.MinInt64+a.HeldBalance {
+		return math.MinInt64
+	}
+	return limit - a.HeldBalance
+}
+
 // Deposit adds amount to the balance with overflow protection.
 func (a *Account) Deposit(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
 	// Check for overflow before addition
 	if a.Balance > math.MaxInt64-amount {
 		return ErrBalanceOverflow
 	}
+	if a.MaxBalance != nil && a.Balance+amount > *a.MaxBalance {
+		return ErrMaxBalanceExceeded
+	}
 	a.Balance += amount
 	return nil
 }
 
-// Withdraw subtracts amount from balance. Returns error if insufficient and overdraft not allowed.
+// Headroom returns how much more the account can receive, saturating at math.MaxInt64.
+func (a *Account) Headroom() int64 {
+	limit := int64(math.MaxInt64)
+	if a.MaxBalance != nil {
+		limit = *a.MaxBalance
+	}
+	if a.Balance >= limit {
+		return 0
+	}
+	if a.Balance < 0 && limit > math.MaxInt64+a.Balance {
+		return math.MaxInt64
+	}
+	return limit - a.Balance
+}
+
+// Withdraw subtracts amount from balance. Returns error if the balance would go below the credit limit
+// or the withdrawal would spend held points.
 func (a *Account) Withdraw(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
-	if !a.CanOverdraft && a.Balance < amount {
+	switch a.Status {
+	case AccountStatusClosed:
+		return ErrAccountClosed
+	case AccountStatusFrozen:
+		return ErrAccountFrozen
+	}
+	if amount > a.spendable() {
 		return ErrInsufficientBalance
 	}
 	a.Balance -= amount
 	return nil
 }
+
+// SetCreditLimit changes the credit limit.
+// Lowering the limit below the current debt is refused unless force is set.
+func (a *Account) SetCreditLimit(creditLimit int64, force bool) error {
+	if creditLimit < 0 {
+		return ErrInvalidCreditLimit
+	}
+	if a.System {
+		return ErrSystemAccount
+	}
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	if creditLimit < a.CreditLimit && a.Balance < -creditLimit && !force {
+		return ErrCreditLimitBelowDebt
+	}
+	a.CreditLimit = creditLimit
+	return nil
+}
+
+// SetMaxBalance changes the balance cap. A nil maxBalance removes the cap.
+// Setting a cap below the current balance is refused unless force is set.
+func (a *Account) SetMaxBalance(maxBalance *int64, force bool) error {
+	if maxBalance != nil && *maxBalance < 0 {
+		return ErrInvalidMaxBalance
+	}
+	if a.System {
+		return ErrSystemAccount
+	}
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	if maxBalance != nil && a.Balance > *maxBalance && !force {
+		return ErrMaxBalanceBelowBalance
+	}
+	a.MaxBalance = maxBalance
+	return nil
+}
+
+// SetOwnerID changes the owner reference. An empty ownerID removes the owner.
+func (a *Account) SetOwnerID(ownerID string) error {
+	if err := ValidateOwnerID(ownerID); err != nil {
+		return err
+	}
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	a.OwnerID = ownerID
+	return nil
+}
+
+// ValidateOwnerID checks the length of an owner reference. Empty is valid.
+func ValidateOwnerID(ownerID string) error {
+	if len(ownerID) > MaxOwnerIDLength {
+		return ErrInvalidOwnerID
+	}
+	return nil
+}
+
+// Freeze stops the account from sending points. Freezing a frozen account is a no-op.
+func (a *Account) Freeze() error {
+	if a.System {
+		return ErrSystemAccount
+	}
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	a.Status = AccountStatusFrozen
+	return nil
+}
+
+// Unfreeze makes a frozen account active again. Unfreezing an active account is a no-op.
+func (a *Account) Unfreeze() error {
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	a.Status = AccountStatusActive
+	return nil
+}
+
+// Close permanently closes the account. Only accounts with zero balance can be closed.
+func (a *Account) Close() error {
+	if a.Status == AccountStatusClosed {
+		return nil
+	}
+	if a.System {
+		return ErrSystemAccount
+	}
+	if a.Balance != 0 || a.HeldBalance != 0 {
+		return ErrAccountNotEmpty
+	}
+	a.Status = AccountStatusClosed
+	return nil
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountChangeID identifies an account change record.
+type AccountChangeID uuid.UUID
+
+// String returns the string representation of AccountChangeID.
+func (id AccountChangeID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// Account setting names recorded in AccountChange.Field.
+const (
+	AccountFieldCreditLimit = "credit_limit"
+	AccountFieldMaxBalance  = "max_balance"
+	AccountFieldStatus      = "status"
+	AccountFieldOwnerID     = "owner_id"
+	AccountFieldAlias       = "alias"
+	// AccountFieldLabelPrefix is followed by the label key, e.g. "label:kind".
+	AccountFieldLabelPrefix = "label:"
+)
+
+// AccountChange records a single modification of an account setting.
+type AccountChange struct {
+	ID        AccountChangeID
+	AccountID AccountID
+	Field     string
+	OldValue  string
+	NewValue  string
+	ChangedBy string
+	ChangedAt time.Time
+}
+
+// DiffAccounts returns the settings that differ between before and after.
+// Balance is not a setting and is never reported.
+// The returned changes only have Field, OldValue and NewValue set.
+func DiffAccounts(before, after *Account) []AccountChange {
+	var changes []AccountChange
+	if before.CreditLimit != after.CreditLimit {
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldCreditLimit,
+			OldValue: strconv.FormatInt(before.CreditLimit, 10),
+			NewValue: strconv.FormatInt(after.CreditLimit, 10),
+		})
+	}
+	if formatOptionalInt64(before.MaxBalance) != formatOptionalInt64(after.MaxBalance) {
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldMaxBalance,
+			OldValue: formatOptionalInt64(before.MaxBalance),
+			NewValue: formatOptionalInt64(after.MaxBalance),
+		})
+	}
+	if before.Status != after.Status {
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldStatus,
+			OldValue: string(before.Status),
+			NewValue: string(after.Status),
+		})
+	}
+	if before.OwnerID != after.OwnerID {
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldOwnerID,
+			OldValue: before.OwnerID,
+			NewValue: after.OwnerID,
+		})
+	}
+	if before.Alias != after.Alias {
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldAlias,
+			OldValue: before.Alias,
+			NewValue: after.Alias,
+		})
+	}
+	keys := slices.Sorted(maps.Keys(before.Labels))
+	for k := range after.Labels {
+		if _, ok := before.Labels[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		oldValue, hadOld := before.Labels[k]
+		newValue, hasNew := after.Labels[k]
+		if hadOld == hasNew && oldValue == newValue {
+			continue
+		}
+		changes = append(changes, AccountChange{
+			Field:    AccountFieldLabelPrefix + k,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return changes
+}
+
+// formatOptionalInt64 formats v, or returns an empty string when v is nil.
+func formatOptionalInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math"
 	"testing"
 
 	"github.com/google/uuid"
@@ -12,7 +13,7 @@ func mustUUID(s string) uuid.UUID {
 
 func TestNewAccount(t *testing.T) {
 	id := AccountID(mustUUID("acc-1"))
-	acc := NewAccount(id, false)
+	acc := NewAccount(id, 0)
 	if acc.ID != id {
 		t.Errorf("expected ID %s, got %s", id, acc.ID)
 	}
@@ -23,7 +24,7 @@ func TestNewAccount(t *testing.T) {
 
 func TestAccount_Deposit(t *testing.T) {
 	id := AccountID(mustUUID("acc-1"))
-	acc := NewAccount(id, false)
+	acc := NewAccount(id, 0)
 
 	acc.Deposit(100)
 	if acc.Balance != 100 {
@@ -37,7 +38,7 @@ func TestAccount_Deposit(t *testing.T) {
 
 func TestAccount_Withdraw(t *testing.T) {
 	id := AccountID(mustUUID("acc-1"))
-	acc := NewAccount(id, false)
+	acc := NewAccount(id, 0)
 
 	acc.Deposit(100)
 
@@ -62,7 +63,7 @@ func TestAccount_Withdraw(t *testing.T) {
 
 func TestAccount_Withdraw_Overdraft(t *testing.T) {
 	id := AccountID(mustUUID("acc-od"))
-	acc := NewAccount(id, true)
+	acc := NewAccount(id, UnlimitedCreditLimit)
 
 	acc.Deposit(50)
 
@@ -75,3 +76,141 @@ func TestAccount_Withdraw_Overdraft(t *testing.T) {
 		t.Errorf("expected Balance -50, got %d", acc.Balance)
 	}
 }
+
+func TestAccount_Status(t *testing.T) {
+	id := AccountID(mustUUID("acc-status"))
+	acc := NewAccount(id, 0)
+	if acc.Status != AccountStatusActive {
+		t.Errorf("expected status active, got %s", acc.Status)
+	}
+
+	acc.Deposit(100)
+
+	// Frozen accounts can receive but not send
+	if err := acc.Freeze(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := acc.Withdraw(10); err != ErrAccountFrozen {
+		t.Errorf("expected ErrAccountFrozen, got %v", err)
+	}
+	if err := acc.Deposit(10); err != nil {
+		t.Errorf("unexpected error on deposit to frozen account: %v", err)
+	}
+
+	// Closing requires zero balance
+	if err := acc.Close(); err != ErrAccountNotEmpty {
+		t.Errorf("expected ErrAccountNotEmpty, got %v", err)
+	}
+
+	if err := acc.Unfreeze(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := acc.Withdraw(110); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := acc.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Closed accounts reject all movement and state changes
+	if err := acc.Deposit(10); err != ErrAccountClosed {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+	if err := acc.Withdraw(10); err != ErrAccountClosed {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+	if err := acc.Unfreeze(); err != ErrAccountClosed {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+}
+
+func TestAccount_Withdraw_CreditLimit(t *testing.T) {
+	id := AccountID(mustUUID("acc-credit"))
+	acc := NewAccount(id, 100)
+
+	acc.Deposit(50)
+
+	// Withdraw down to the credit limit
+	if err := acc.Withdraw(150); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Balance != -100 {
+		t.Errorf("expected Balance -100, got %d", acc.Balance)
+	}
+
+	// Beyond the credit limit
+	if err := acc.Withdraw(1); err != ErrInsufficientBalance {
+		t.Errorf("expected ErrInsufficientBalance, got %v", err)
+	}
+
+	// Unlimited credit with a large positive balance must not overflow
+	unlimited := NewAccount(AccountID(mustUUID("acc-unlimited")), UnlimitedCreditLimit)
+	unlimited.Balance = math.MaxInt64 - 1
+	if err := unlimited.Withdraw(math.MaxInt64); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if unlimited.Balance != -1 {
+		t.Errorf("expected Balance -1, got %d", unlimited.Balance)
+	}
+}
+
+func TestAccount_SetCreditLimit(t *testing.T) {
+	acc := NewAccount(AccountID(mustUUID("acc-set-credit")), 100)
+	acc.Balance = -80
+
+	if err := acc.SetCreditLimit(-1, false); err != ErrInvalidCreditLimit {
+		t.Errorf("expected ErrInvalidCreditLimit, got %v", err)
+	}
+	if err := acc.SetCreditLimit(50, false); err != ErrCreditLimitBelowDebt {
+		t.Errorf("expected ErrCreditLimitBelowDebt, got %v", err)
+	}
+	if err := acc.SetCreditLimit(50, true); err != nil {
+		t.Errorf("unexpected error on forced change: %v", err)
+	}
+	if acc.CreditLimit != 50 {
+		t.Errorf("expected CreditLimit 50, got %d", acc.CreditLimit)
+	}
+}
+
+func TestAccount_Deposit_MaxBalance(t *testing.T) {
+	acc := NewAccount(AccountID(mustUUID("acc-capped")), 0)
+	maxBalance := int64(100)
+	if err := acc.SetMaxBalance(&maxBalance, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := acc.Deposit(80); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Headroom() != 20 {
+		t.Errorf("expected Headroom 20, got %d", acc.Headroom())
+	}
+
+	// Deposit beyond the cap
+	if err := acc.Deposit(21); err != ErrMaxBalanceExceeded {
+		t.Errorf("expected ErrMaxBalanceExceeded, got %v", err)
+	}
+	if acc.Balance != 80 {
+		t.Errorf("balance should not change on error, got %d", acc.Balance)
+	}
+
+	// Cap below the current balance requires force
+	lower := int64(50)
+	if err := acc.SetMaxBalance(&lower, false); err != ErrMaxBalanceBelowBalance {
+		t.Errorf("expected ErrMaxBalanceBelowBalance, got %v", err)
+	}
+	if err := acc.SetMaxBalance(&lower, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Headroom() != 0 {
+		t.Errorf("expected Headroom 0, got %d", acc.Headroom())
+	}
+
+	// Removing the cap
+	if err := acc.SetMaxBalance(nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Headroom() != math.MaxInt64-80 {
+		t.Errorf("expected Headroom %d, got %d", int64(math.MaxInt64-80), acc.Headroom())
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "math"
+
+// MaxAccountTreeDepth is the maximum number of levels in an account tree, including the root.
+const MaxAccountTreeDepth = 8
+
+// AccountTreeNode is an account together with its sub-accounts.
+type AccountTreeNode struct {
+	Account  *Account
+	Children []*AccountTreeNode
+	// RolledUpBalance is the account's own balance plus the rolled-up balances of its sub-accounts.
+	RolledUpBalance int64
+}
+
+// BuildAccountTree arranges descendants under root and computes the rolled-up balances.
+// Accounts that are not reachable from root are ignored.
+func BuildAccountTree(root *Account, descendants []*Account) (*AccountTreeNode, error) {
+	children := make(map[AccountID][]*Account)
+	for _, acc := range descendants {
+		if acc.ParentID != nil {
+			children[*acc.ParentID] = append(children[*acc.ParentID], acc)
+		}
+	}
+	return buildAccountTreeNode(root, children, 1)
+}
+
+func buildAccountTreeNode(acc *Account, children map[AccountID][]*Account, depth int) (*AccountTreeNode, error) {
+	node := &AccountTreeNode{
+		Account:         acc,
+		RolledUpBalance: acc.Balance,
+	}
+	if depth >= MaxAccountTreeDepth {
+		return node, nil
+	}
+	for _, child := range children[acc.ID] {
+		childNode, err := buildAccountTreeNode(child, children, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if !canAdd(node.RolledUpBalance, childNode.RolledUpBalance) {
+			return nil, ErrBalanceOverflow
+		}
+		node.RolledUpBalance += childNode.RolledUpBalance
+		node.Children = append(node.Children, childNode)
+	}
+	return node, nil
+}
+
+// canAdd reports whether a+b fits in an int64.
+func canAdd(a, b int64) bool {
+	if b > 0 {
+		return a <= math.MaxInt64-b
+	}
+	return a >= math.MinInt64-b
+}
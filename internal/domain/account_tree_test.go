@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildAccountTree(t *testing.T) {
+	newAcc := func(balance int64, parent *Account) *Account {
+		acc := NewAccount(AccountID(uuid.New()), 0)
+		acc.Balance = balance
+		if parent != nil {
+			acc.ParentID = &parent.ID
+		}
+		return acc
+	}
+
+	root := newAcc(100, nil)
+	event := newAcc(50, root)
+	team := newAcc(20, root)
+	stage := newAcc(-5, event)
+	stranger := newAcc(1000, nil)
+
+	tree, err := BuildAccountTree(root, []*Account{event, team, stage, stranger})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tree.RolledUpBalance != 165 {
+		t.Errorf("expected root rolled-up balance 165, got %d", tree.RolledUpBalance)
+	}
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	}
+	for _, child := range tree.Children {
+		if child.Account == event && child.RolledUpBalance != 45 {
+			t.Errorf("expected event rolled-up balance 45, got %d", child.RolledUpBalance)
+		}
+	}
+
+	big := newAcc(math.MaxInt64, root)
+	if _, err := BuildAccountTree(root, []*Account{big}); err != ErrBalanceOverflow {
+		t.Errorf("expected ErrBalanceOverflow, got %v", err)
+	}
+}
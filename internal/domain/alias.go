@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AliasPrefix marks an account reference as an alias rather than an account ID, e.g. "@event-2026-pool".
+const AliasPrefix = "@"
+
+// MaxAliasLength is the maximum allowed length of an alias, excluding AliasPrefix.
+const MaxAliasLength = 64
+
+var aliasPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+// ValidateAlias checks that alias is a valid alias. The empty string means no alias and is valid.
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return nil
+	}
+	if len(alias) > MaxAliasLength || !aliasPattern.MatchString(alias) {
+		return ErrInvalidAlias
+	}
+	return nil
+}
+
+// ParseAliasReference returns the alias of an "@alias" reference and whether ref is one.
+func ParseAliasReference(ref string) (string, bool) {
+	return strings.CutPrefix(ref, AliasPrefix)
+}
+
+// SetAlias replaces the alias of the account. An empty alias removes it,
+// which is also allowed for closed accounts to free the alias.
+// Uniqueness is checked by the caller.
+func (a *Account) SetAlias(alias string) error {
+	if err := ValidateAlias(alias); err != nil {
+		return err
+	}
+	if alias != "" && a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	a.Alias = alias
+	return nil
+}
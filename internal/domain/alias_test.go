@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"", "pool", "event-2026-pool", "team.sysad_2"} {
+		if err := ValidateAlias(alias); err != nil {
+			t.Errorf("alias %q: unexpected error: %v", alias, err)
+		}
+	}
+	for _, alias := range []string{"@pool", "Pool", "-pool", "pool-", "has space", strings.Repeat("a", MaxAliasLength+1)} {
+		if err := ValidateAlias(alias); err != ErrInvalidAlias {
+			t.Errorf("alias %q: expected ErrInvalidAlias, got %v", alias, err)
+		}
+	}
+}
+
+func TestAccount_SetAlias_Closed(t *testing.T) {
+	acc := &Account{Alias: "pool", Status: AccountStatusClosed}
+	if err := acc.SetAlias("other"); err != ErrAccountClosed {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+	if err := acc.SetAlias(""); err != nil {
+		t.Errorf("expected clearing the alias of a closed account to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AssetCode identifies a point type, e.g. "point" or "event-ticket".
+type AssetCode string
+
+// DefaultAssetCode is the asset of accounts that do not specify one.
+const DefaultAssetCode AssetCode = "point"
+
+const (
+	// MaxAssetNameLength is the maximum allowed asset name length.
+	MaxAssetNameLength = 255
+	// MaxAssetPrecision is the maximum number of display decimal places.
+	MaxAssetPrecision = 18
+	// MaxPointTTLDays is the longest points of an asset may be set to live.
+	MaxPointTTLDays = 3650
+)
+
+var assetCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// Asset is an independent point type. Points can only move between accounts of the same asset.
+type Asset struct {
+	Code AssetCode
+	Name string
+	// Precision is the number of decimal places clients use to display amounts.
+	// Amounts are always stored as integers of the smallest unit.
+	Precision int
+	// IssuerAccountID is the account debited by Mint and credited by Burn. Nil until designated.
+	IssuerAccountID *AccountID
+	// EscrowAccountID is the system account holding escrowed points. Nil until the first escrow.
+	EscrowAccountID *AccountID
+	// Supply is the circulating supply: points minted minus points burned or expired.
+	Supply int64
+	// PointTTLDays is how many days credited points live before they expire. Zero means they never expire.
+	PointTTLDays int
+	CreatedAt    time.Time
+}
+
+// NewAsset validates and creates a new asset.
+func NewAsset(code AssetCode, name string, precision int) (*Asset, error) {
+	if err := ValidateAssetCode(code); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" || len(name) > MaxAssetNameLength {
+		return nil, ErrInvalidAsset
+	}
+	if precision < 0 || precision > MaxAssetPrecision {
+		return nil, ErrInvalidAsset
+	}
+	return &Asset{
+		Code:      code,
+		Name:      name,
+		Precision: precision,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ValidateAssetCode checks that code is a well-formed asset code.
+func ValidateAssetCode(code AssetCode) error {
+	if !assetCodePattern.MatchString(string(code)) {
+		return ErrInvalidAsset
+	}
+	return nil
+}
+
+// SetPointTTL makes points credited from now on expire after days. Zero turns expiry off
+// for new credits; points already tracked keep their expiry.
+func (a *Asset) SetPointTTL(days int) error {
+	if days < 0 || days > MaxPointTTLDays {
+		return ErrInvalidPointTTL
+	}
+	if days > 0 && a.IssuerAccountID == nil {
+		return ErrIssuerNotConfigured
+	}
+	a.PointTTLDays = days
+	return nil
+}
+
+// PointExpiry returns when points credited at t expire, or the zero time if they do not.
+func (a *Asset) PointExpiry(t time.Time) time.Time {
+	if a.PointTTLDays == 0 {
+		return time.Time{}
+	}
+	return t.AddDate(0, 0, a.PointTTLDays)
+}
+
+// IsSystemAccount reports whether id is the issuer or escrow account of the asset.
+func (a *Asset) IsSystemAccount(id AccountID) bool {
+	return (a.IssuerAccountID != nil && *a.IssuerAccountID == id) ||
+		(a.EscrowAccountID != nil && *a.EscrowAccountID == id)
+}
+
+// AddSupply records amount newly minted points.
+func (a *Asset) AddSupply(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if a.Supply > math.MaxInt64-amount {
+		return ErrBalanceOverflow
+	}
+	a.Supply += amount
+	return nil
+}
+
+// RemoveSupply records amount burned points. The supply cannot go below zero.
+func (a *Asset) RemoveSupply(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if amount > a.Supply {
+		return ErrInsufficientSupply
+	}
+	a.Supply -= amount
+	return nil
+}
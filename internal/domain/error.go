@@ -20,8 +20,8 @@ var (
 	// ErrBalanceOverflow indicates that the operation would cause a balance overflow.
 	ErrBalanceOverflow = errors.New("balance would overflow")
 
-	// ErrInvalidIdempotencyKey indicates that the idempotency key is invalid or empty.
-	ErrInvalidIdempotencyKey = errors.New("idempotency key must not be empty")
+	// ErrInvalidIdempotencyKey indicates that the idempotency key is empty, too long or contains the reserved '#'.
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be between 1 and 255 characters and must not contain '#'")
 
 	// ErrAmountTooLarge indicates that the transfer amount exceeds the maximum allowed.
 	ErrAmountTooLarge = errors.New("amount exceeds maximum allowed value")
@@ -31,6 +31,200 @@ var (
 
 	// ErrDescriptionTooLong indicates that the description exceeds the maximum length.
 	ErrDescriptionTooLong = errors.New("description is too long")
+
+	// ErrInvalidCreditLimit indicates that the credit limit is negative.
+	ErrInvalidCreditLimit = errors.New("credit limit must not be negative")
+
+	// ErrCreditLimitBelowDebt indicates that the credit limit cannot be lowered below the current debt.
+	ErrCreditLimitBelowDebt = errors.New("credit limit would be below current debt")
+
+	// ErrInvalidMaxBalance indicates that the max balance is negative.
+	ErrInvalidMaxBalance = errors.New("max balance must not be negative")
+
+	// ErrMaxBalanceExceeded indicates that the deposit would exceed the account's max balance.
+	ErrMaxBalanceExceeded = errors.New("balance would exceed max balance")
+
+	// ErrMaxBalanceBelowBalance indicates that the max balance cannot be set below the current balance.
+	ErrMaxBalanceBelowBalance = errors.New("max balance would be below current balance")
+
+	// ErrAccountFrozen indicates that the account is frozen and cannot send points.
+	ErrAccountFrozen = errors.New("account is frozen")
+
+	// ErrAccountClosed indicates that the account is closed and cannot be used.
+	ErrAccountClosed = errors.New("account is closed")
+
+	// ErrAccountNotEmpty indicates that the account cannot be closed because its balance is not zero.
+	ErrAccountNotEmpty = errors.New("account balance must be zero to close")
+
+	// ErrInvalidLabel indicates that a label key or value is malformed.
+	ErrInvalidLabel = errors.New("invalid label key or value")
+
+	// ErrTooManyLabels indicates that the account would have too many labels.
+	ErrTooManyLabels = errors.New("too many labels")
+
+	// ErrInvalidLabelSelector indicates that a label selector is malformed.
+	ErrInvalidLabelSelector = errors.New("invalid label selector")
+
+	// ErrInvalidOwnerID indicates that the owner reference is too long or missing where required.
+	ErrInvalidOwnerID = errors.New("invalid owner_id")
+
+	// ErrAssetNotFound indicates that the requested asset was not found.
+	ErrAssetNotFound = errors.New("asset not found")
+
+	// ErrAssetAlreadyExists indicates that an asset with the same code already exists.
+	ErrAssetAlreadyExists = errors.New("asset already exists")
+
+	// ErrInvalidAsset indicates that the asset code, name or precision is invalid.
+	ErrInvalidAsset = errors.New("invalid asset")
+
+	// ErrAssetMismatch indicates that the accounts hold different assets.
+	ErrAssetMismatch = errors.New("accounts hold different assets")
+
+	// ErrIssuerNotConfigured indicates that the asset has no designated issuer account.
+	ErrIssuerNotConfigured = errors.New("asset has no issuer account")
+
+	// ErrInsufficientSupply indicates that more points would be burned than are in circulation.
+	ErrInsufficientSupply = errors.New("amount exceeds circulating supply")
+
+	// ErrSystemAccount indicates that an issuer or escrow account was used outside the operations it belongs to.
+	ErrSystemAccount = errors.New("system accounts can only be used by issuance, expiry and escrow")
+
+	// ErrIssuerInUse indicates that the issuer cannot change while points of the asset are in circulation.
+	ErrIssuerInUse = errors.New("issuer cannot change while points are in circulation")
+
+	// ErrAccountTreeTooDeep indicates that a sub-account would exceed MaxAccountTreeDepth.
+	ErrAccountTreeTooDeep = errors.New("account tree is too deep")
+
+	// ErrIntraTreeTransfer indicates that the transfer policy forbids moving points within an account tree.
+	ErrIntraTreeTransfer = errors.New("transfers within this account tree are not allowed")
+
+	// ErrInvalidAlias indicates that the alias is too long or contains invalid characters.
+	ErrInvalidAlias = errors.New("invalid alias")
+
+	// ErrAliasTaken indicates that another account already uses the alias.
+	ErrAliasTaken = errors.New("alias is already taken")
+
+	// ErrInvalidBatchSize indicates that a batch has no legs or too many legs.
+	ErrInvalidBatchSize = errors.New("invalid number of legs in batch")
+
+	// ErrJournalEntryNotFound indicates that the requested journal entry was not found.
+	ErrJournalEntryNotFound = errors.New("journal entry not found")
+
+	// ErrJournalEntryNotReversible indicates that the entry is a reversal or not a transfer.
+	ErrJournalEntryNotReversible = errors.New("journal entry cannot be reversed")
+
+	// ErrJournalEntryAlreadyReversed indicates that the whole amount of the entry is already reversed.
+	ErrJournalEntryAlreadyReversed = errors.New("journal entry is already reversed")
+
+	// ErrReversalExceedsOriginal indicates that a reversal amount is larger than the part of the original amount not reversed yet.
+	ErrReversalExceedsOriginal = errors.New("reversal amount exceeds the unreversed original amount")
+
+	// ErrHoldNotFound indicates that the requested hold was not found.
+	ErrHoldNotFound = errors.New("hold not found")
+
+	// ErrHoldNotAuthorized indicates that the hold was already captured, voided or expired.
+	ErrHoldNotAuthorized = errors.New("hold is no longer authorized")
+
+	// ErrHoldExpired indicates that the hold expired before it was captured.
+	ErrHoldExpired = errors.New("hold has expired")
+
+	// ErrCaptureExceedsHold indicates that the capture amount is larger than the held amount.
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds held amount")
+
+	// ErrInvalidHoldExpiry indicates that the hold expiry is in the past or too far in the future.
+	ErrInvalidHoldExpiry = errors.New("invalid hold expiry")
+
+	// ErrScheduledTransferNotFound indicates that the requested scheduled transfer was not found.
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+
+	// ErrScheduledTransferNotPending indicates that the scheduled transfer was already executed, failed or canceled.
+	ErrScheduledTransferNotPending = errors.New("scheduled transfer is no longer pending")
+
+	// ErrInvalidExecuteAt indicates that the execution time of a scheduled transfer is not in the future.
+	ErrInvalidExecuteAt = errors.New("execute_at must be in the future")
+
+	// ErrInvalidSchedule indicates that a recurring schedule is neither a valid interval nor a valid cron expression.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// ErrInvalidStandingOrderEnd indicates that the end date of a standing order is before its first run.
+	ErrInvalidStandingOrderEnd = errors.New("end_at must not be before the first run")
+
+	// ErrStandingOrderNotFound indicates that the requested standing order was not found.
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+
+	// ErrStandingOrderNotActive indicates that the standing order is not active.
+	ErrStandingOrderNotActive = errors.New("standing order is not active")
+
+	// ErrStandingOrderNotPaused indicates that the standing order is not paused.
+	ErrStandingOrderNotPaused = errors.New("standing order is not paused")
+
+	// ErrStandingOrderFinished indicates that the standing order was already canceled or completed.
+	ErrStandingOrderFinished = errors.New("standing order is canceled or completed")
+
+	// ErrEscrowNotFound indicates that the requested escrow was not found.
+	ErrEscrowNotFound = errors.New("escrow not found")
+
+	// ErrEscrowNotHeld indicates that the escrow was already released or refunded.
+	ErrEscrowNotHeld = errors.New("escrow is no longer held")
+
+	// ErrEscrowExpired indicates that the escrow deadline passed, so it can only be refunded.
+	ErrEscrowExpired = errors.New("escrow deadline has passed")
+
+	// ErrInvalidEscrowDeadline indicates that the escrow deadline is not in the future or too far away.
+	ErrInvalidEscrowDeadline = errors.New("invalid escrow deadline")
+
+	// ErrInvalidFeeRule indicates that a fee rule has a bad name, negative amounts or min above max.
+	ErrInvalidFeeRule = errors.New("invalid fee rule")
+
+	// ErrFeeRuleNotFound indicates that the requested fee rule was not found.
+	ErrFeeRuleNotFound = errors.New("fee rule not found")
+
+	// ErrInvalidVelocityLimits indicates that a velocity limit is negative.
+	ErrInvalidVelocityLimits = errors.New("velocity limits must not be negative")
+
+	// ErrVelocityLimitExceeded indicates that a transfer would break a velocity limit of the sender.
+	// The returned error is a *VelocityLimitError telling which limit and when it resets.
+	ErrVelocityLimitExceeded = errors.New("velocity limit exceeded")
+
+	// ErrVelocityLimitsNotConfigured indicates that velocity limits are set while they are not enforced.
+	ErrVelocityLimitsNotConfigured = errors.New("velocity limits are not configured")
+
+	// ErrInvalidPointTTL indicates that a point lifetime is negative or too long.
+	ErrInvalidPointTTL = errors.New("invalid point ttl")
+
+	// ErrPreconditionFailed indicates that an account changed since the caller read it.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrPaymentRequestNotFound indicates that the requested payment request was not found.
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+
+	// ErrPaymentRequestNotPending indicates that the payment request was already accepted, declined or expired.
+	ErrPaymentRequestNotPending = errors.New("payment request is not pending")
+
+	// ErrPaymentRequestExpired indicates that the payment request expired, so it can no longer be accepted.
+	ErrPaymentRequestExpired = errors.New("payment request has expired")
+
+	// ErrInvalidPaymentRequestExpiry indicates that the payment request expiry is not in the future or too far away.
+	ErrInvalidPaymentRequestExpiry = errors.New("invalid payment request expiry")
+
+	// ErrInvalidSplit indicates that split weights are missing, not positive or too large in total.
+	ErrInvalidSplit = errors.New("split weights must be positive and their sum must fit in 64 bits")
+
+	// ErrDuplicateSplitRecipient indicates that an account appears more than once among the recipients of a split.
+	ErrDuplicateSplitRecipient = errors.New("duplicate split recipient")
+
+	// ErrInvalidMetadata indicates that journal entry metadata has a malformed key, a too long value or too many entries.
+	ErrInvalidMetadata = errors.New("invalid metadata")
+
+	// ErrInvalidJournalEntryFilter indicates that a journal entry query selects neither an account nor metadata, or is malformed.
+	ErrInvalidJournalEntryFilter = errors.New("invalid journal entry filter")
+
+	// ErrIdempotencyKeyReused indicates that an idempotency key was replayed with a different request.
+	// The returned error is an *IdempotencyKeyReusedError telling which key, and entry if any, it was.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+	// ErrInvalidChangedBy indicates that the actor of a change is missing or too long.
+	ErrInvalidChangedBy = errors.New("changed_by must be between 1 and 255 characters")
 )
 
 // Sentinel Error Wrapping helpers (optional, but keep simple for now)
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscrowID identifies an escrow. The journal entries funding and settling it carry the same ID.
+type EscrowID uuid.UUID
+
+// String returns the string representation of EscrowID.
+func (id EscrowID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// EscrowStatus represents the lifecycle state of an escrow.
+type EscrowStatus string
+
+const (
+	// EscrowStatusHeld escrows keep the points in the escrow account until settled.
+	EscrowStatusHeld EscrowStatus = "held"
+	// EscrowStatusReleased escrows paid the points to the beneficiary.
+	EscrowStatusReleased EscrowStatus = "released"
+	// EscrowStatusRefunded escrows paid the points back to the payer.
+	EscrowStatusRefunded EscrowStatus = "refunded"
+)
+
+// Escrow locks points of the payer in the asset's escrow account until they are
+// released to the beneficiary or refunded to the payer.
+type Escrow struct {
+	ID                   EscrowID
+	PayerAccountID       AccountID
+	BeneficiaryAccountID AccountID
+	// EscrowAccountID is the system account holding the points while the escrow is held.
+	EscrowAccountID AccountID
+	Amount          int64
+	Description     string
+	Status          EscrowStatus
+	// Deadline is when a held escrow is refunded automatically. It can no longer be released after it.
+	Deadline       time.Time
+	IdempotencyKey string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ReleaseIdempotencyKey is the idempotency key of the journal entry releasing the escrow.
+func (e *Escrow) ReleaseIdempotencyKey() string {
+	return "escrow:" + e.ID.String() + ":release"
+}
+
+// RefundIdempotencyKey is the idempotency key of the journal entry refunding the escrow.
+func (e *Escrow) RefundIdempotencyKey() string {
+	return "escrow:" + e.ID.String() + ":refund"
+}
+
+// Release marks the held escrow as released to the beneficiary.
+func (e *Escrow) Release(now time.Time) error {
+	if e.Status != EscrowStatusHeld {
+		return ErrEscrowNotHeld
+	}
+	if !now.Before(e.Deadline) {
+		return ErrEscrowExpired
+	}
+	e.Status = EscrowStatusReleased
+	e.UpdatedAt = now
+	return nil
+}
+
+// Refund marks the held escrow as refunded to the payer. Refunds are allowed at any time.
+func (e *Escrow) Refund(now time.Time) error {
+	if e.Status != EscrowStatusHeld {
+		return ErrEscrowNotHeld
+	}
+	e.Status = EscrowStatusRefunded
+	e.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxFeeRuleNameLength is the maximum allowed fee rule name length.
+	MaxFeeRuleNameLength = 255
+	// MaxFeeRateBasisPoints is the highest fee rate, 100%.
+	MaxFeeRateBasisPoints = 10_000
+)
+
+// FeeRuleID identifies a fee rule.
+type FeeRuleID uuid.UUID
+
+// String returns the string representation of FeeRuleID.
+func (id FeeRuleID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// FeeRule charges the sender of matching transfers a fee, paid to the collector account
+// on top of the transferred amount.
+type FeeRule struct {
+	ID   FeeRuleID
+	Name string
+	// Asset restricts the rule to one asset. Empty matches every asset; rules created through
+	// the API always carry the asset of their collector.
+	Asset AssetCode
+	// SourceSelector matches the labels of the sending account. Nil matches every sender.
+	SourceSelector *LabelSelector
+	// DestinationAccountID matches the receiving account. Nil matches every recipient.
+	DestinationAccountID *AccountID
+	// FlatFee is added to the percentage part.
+	FlatFee int64
+	// RateBasisPoints is the percentage part in hundredths of a percent, rounded down.
+	RateBasisPoints int64
+	MinFee          int64
+	// MaxFee caps the fee. Nil means no cap.
+	MaxFee             *int64
+	CollectorAccountID AccountID
+	// Priority decides between matching rules; the highest wins.
+	Priority  int
+	CreatedAt time.Time
+}
+
+// Validate checks the amounts and the name of the rule.
+func (r *FeeRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" || len(r.Name) > MaxFeeRuleNameLength {
+		return ErrInvalidFeeRule
+	}
+	if r.FlatFee < 0 || r.MinFee < 0 || r.RateBasisPoints < 0 || r.RateBasisPoints > MaxFeeRateBasisPoints {
+		return ErrInvalidFeeRule
+	}
+	if r.MaxFee != nil && *r.MaxFee < r.MinFee {
+		return ErrInvalidFeeRule
+	}
+	if r.Asset != "" {
+		if err := ValidateAssetCode(r.Asset); err != nil {
+			return ErrInvalidFeeRule
+		}
+	}
+	if r.SourceSelector != nil {
+		if err := r.SourceSelector.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a transfer between the accounts.
+// Transfers sent by the collector itself are never charged.
+func (r *FeeRule) Matches(from, to *Account) bool {
+	if from.ID == r.CollectorAccountID {
+		return false
+	}
+	if r.Asset != "" && r.Asset != from.Asset {
+		return false
+	}
+	if r.SourceSelector != nil && !r.SourceSelector.Matches(from.Labels) {
+		return false
+	}
+	if r.DestinationAccountID != nil && *r.DestinationAccountID != to.ID {
+		return false
+	}
+	return true
+}
+
+// Fee returns the fee for transferring amount. amount must not exceed 10^14,
+// so that the percentage part cannot overflow.
+func (r *FeeRule) Fee(amount int64) int64 {
+	fee := r.FlatFee + amount*r.RateBasisPoints/MaxFeeRateBasisPoints
+	fee = max(fee, r.MinFee)
+	if r.MaxFee != nil {
+		fee = min(fee, *r.MaxFee)
+	}
+	return fee
+}
+
+// SelectFeeRule returns the matching rule with the highest priority, or nil if none matches.
+// Ties go to the rule created first.
+func SelectFeeRule(rules []*FeeRule, from, to *Account) *FeeRule {
+	var selected *FeeRule
+	for _, r := range rules {
+		if !r.Matches(from, to) {
+			continue
+		}
+		if selected == nil || r.Priority > selected.Priority ||
+			(r.Priority == selected.Priority && r.CreatedAt.Before(selected.CreatedAt)) {
+			selected = r
+		}
+	}
+	return selected
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFeeRule_Fee(t *testing.T) {
+	maxFee := int64(500)
+	tests := []struct {
+		name   string
+		rule   FeeRule
+		amount int64
+		want   int64
+	}{
+		{"flat only", FeeRule{FlatFee: 10}, 1000, 10},
+		{"rate rounds down", FeeRule{RateBasisPoints: 250}, 999, 24},
+		{"flat plus rate", FeeRule{FlatFee: 5, RateBasisPoints: 100}, 1000, 15},
+		{"raised to min", FeeRule{RateBasisPoints: 100, MinFee: 20}, 1000, 20},
+		{"capped at max", FeeRule{RateBasisPoints: 1000, MaxFee: &maxFee}, 100_000, 500},
+		{"zero", FeeRule{}, 1000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Fee(tt.amount); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFeeRule_Validate(t *testing.T) {
+	maxFee := int64(5)
+	tests := []struct {
+		name string
+		rule FeeRule
+		want error
+	}{
+		{"valid", FeeRule{Name: "market", FlatFee: 1, RateBasisPoints: 100}, nil},
+		{"empty name", FeeRule{Name: " "}, ErrInvalidFeeRule},
+		{"negative flat fee", FeeRule{Name: "market", FlatFee: -1}, ErrInvalidFeeRule},
+		{"rate above 100%", FeeRule{Name: "market", RateBasisPoints: 10_001}, ErrInvalidFeeRule},
+		{"max below min", FeeRule{Name: "market", MinFee: 10, MaxFee: &maxFee}, ErrInvalidFeeRule},
+		{"bad selector", FeeRule{Name: "market", SourceSelector: &LabelSelector{Key: "tier"}}, ErrInvalidLabelSelector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	collector := AccountID(uuid.New())
+	from := &Account{ID: AccountID(uuid.New()), Asset: "point", Labels: map[string]string{"tier": "shop"}}
+	to := &Account{ID: AccountID(uuid.New()), Asset: "point"}
+	now := time.Now()
+
+	general := &FeeRule{Name: "general", CollectorAccountID: collector, CreatedAt: now}
+	shops := &FeeRule{
+		Name:               "shops",
+		SourceSelector:     &LabelSelector{Key: "tier", Operator: LabelOpEquals, Values: []string{"shop"}},
+		CollectorAccountID: collector,
+		Priority:           10,
+		CreatedAt:          now,
+	}
+	olderShops := &FeeRule{Name: "older shops", SourceSelector: shops.SourceSelector, CollectorAccountID: collector, Priority: 10, CreatedAt: now.Add(-time.Hour)}
+	otherDestination := &FeeRule{Name: "other", DestinationAccountID: &collector, CollectorAccountID: collector, Priority: 100, CreatedAt: now}
+	otherAsset := &FeeRule{Name: "gold", Asset: "gold", CollectorAccountID: collector, Priority: 100, CreatedAt: now}
+
+	if got := SelectFeeRule([]*FeeRule{general, otherDestination, otherAsset}, from, to); got != general {
+		t.Errorf("expected the general rule, got %v", got)
+	}
+	if got := SelectFeeRule([]*FeeRule{general, shops}, from, to); got != shops {
+		t.Errorf("expected the higher priority rule, got %v", got)
+	}
+	if got := SelectFeeRule([]*FeeRule{shops, olderShops}, from, to); got != olderShops {
+		t.Errorf("expected the older rule on a tie, got %v", got)
+	}
+	if got := SelectFeeRule([]*FeeRule{general}, &Account{ID: collector, Asset: "point"}, to); got != nil {
+		t.Errorf("expected no fee on transfers from the collector, got %v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HoldID identifies a hold.
+type HoldID uuid.UUID
+
+// String returns the string representation of HoldID.
+func (id HoldID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// HoldStatus represents the lifecycle state of a hold.
+type HoldStatus string
+
+const (
+	// HoldStatusAuthorized holds reserve points that can still be captured.
+	HoldStatusAuthorized HoldStatus = "authorized"
+	// HoldStatusCaptured holds were settled by a journal entry.
+	HoldStatusCaptured HoldStatus = "captured"
+	// HoldStatusVoided holds were released without moving points.
+	HoldStatusVoided HoldStatus = "voided"
+	// HoldStatusExpired holds were released because they were not captured in time.
+	HoldStatusExpired HoldStatus = "expired"
+)
+
+// Hold reserves points on an account for a later transfer to a fixed recipient.
+type Hold struct {
+	ID          HoldID
+	AccountID   AccountID
+	ToAccountID AccountID
+	Amount      int64
+	Description string
+	Status      HoldStatus
+	// CapturedAmount is the amount actually transferred. The rest was released on capture.
+	CapturedAmount int64
+	IdempotencyKey string
+	// RequestFingerprint identifies the request that authorized the hold, see RequestFingerprint.
+	// Empty for rows written before migration 00026.
+	RequestFingerprint string
+	ExpiresAt          time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// CheckCapturable returns an error unless amount of the hold can be captured at now.
+func (h *Hold) CheckCapturable(amount int64, now time.Time) error {
+	if h.Status != HoldStatusAuthorized {
+		return ErrHoldNotAuthorized
+	}
+	if !now.Before(h.ExpiresAt) {
+		return ErrHoldExpired
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if amount > h.Amount {
+		return ErrCaptureExceedsHold
+	}
+	return nil
+}
+
+// Settle marks an authorized hold as captured, voided or expired.
+func (h *Hold) Settle(status HoldStatus, now time.Time) error {
+	if h.Status != HoldStatusAuthorized {
+		return ErrHoldNotAuthorized
+	}
+	h.Status = status
+	h.UpdatedAt = now
+	return nil
+}
+
+// Hold reserves amount so that it cannot be withdrawn.
+// Like Withdraw, it needs an active account and enough spendable points.
+func (a *Account) Hold(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	switch a.Status {
+	case AccountStatusClosed:
+		return ErrAccountClosed
+	case AccountStatusFrozen:
+		return ErrAccountFrozen
+	}
+	if amount > a.spendable() {
+		return ErrInsufficientBalance
+	}
+	a.HeldBalance += amount
+	return nil
+}
+
+// Release returns amount of held points to the spendable balance.
+func (a *Account) Release(amount int64) error {
+	if amount <= 0 || amount > a.HeldBalance {
+		return ErrInvalidAmount
+	}
+	a.HeldBalance -= amount
+	return nil
+}
+
+// AvailableBalance is the balance not reserved by holds, saturating at math.MinInt64.
+func (a *Account) AvailableBalance() int64 {
+	if a.Balance < math.MinInt64+a.HeldBalance {
+		return math.MinInt64
+	}
+	return a.Balance - a.HeldBalance
+}
+
+// CaptureIdempotencyKey is the idempotency key of the journal entry posted when the hold is captured.
+// A hold is captured at most once, so retried captures resolve to the same entry.
+func (h *Hold) CaptureIdempotencyKey() string {
+	return "hold:" + h.ID.String() + ":capture"
+}
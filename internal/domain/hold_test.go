@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccount_Hold(t *testing.T) {
+	acc := &Account{Balance: 1000, Status: AccountStatusActive}
+	if err := acc.Hold(600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Balance != 1000 || acc.AvailableBalance() != 400 {
+		t.Errorf("expected ledger 1000 and available 400, got %d/%d", acc.Balance, acc.AvailableBalance())
+	}
+	if err := acc.Hold(401); err != ErrInsufficientBalance {
+		t.Errorf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if err := acc.Withdraw(401); err != ErrInsufficientBalance {
+		t.Errorf("expected withdraw to respect held points, got %v", err)
+	}
+	if err := acc.Close(); err != ErrAccountNotEmpty {
+		t.Errorf("expected ErrAccountNotEmpty, got %v", err)
+	}
+
+	if err := acc.Release(601); err != ErrInvalidAmount {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+	if err := acc.Release(600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := acc.Withdraw(1000); err != nil {
+		t.Errorf("unexpected error after release: %v", err)
+	}
+}
+
+func TestHold_CheckCapturable(t *testing.T) {
+	now := time.Now()
+	hold := &Hold{Amount: 500, Status: HoldStatusAuthorized, ExpiresAt: now.Add(time.Hour)}
+
+	if err := hold.CheckCapturable(500, now); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := hold.CheckCapturable(501, now); err != ErrCaptureExceedsHold {
+		t.Errorf("expected ErrCaptureExceedsHold, got %v", err)
+	}
+	if err := hold.CheckCapturable(0, now); err != ErrInvalidAmount {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+	if err := hold.CheckCapturable(500, hold.ExpiresAt); err != ErrHoldExpired {
+		t.Errorf("expected ErrHoldExpired, got %v", err)
+	}
+
+	if err := hold.Settle(HoldStatusVoided, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := hold.CheckCapturable(500, now); err != ErrHoldNotAuthorized {
+		t.Errorf("expected ErrHoldNotAuthorized, got %v", err)
+	}
+	if err := hold.Settle(HoldStatusExpired, now); err != ErrHoldNotAuthorized {
+		t.Errorf("expected ErrHoldNotAuthorized, got %v", err)
+	}
+}
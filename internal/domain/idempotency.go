@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestFingerprint identifies the payload of a request using an idempotency key. It is stored with
+// the key so that a replay of the key can be checked against the request that first used it.
+// The operation is part of the fingerprint: a key first used by another kind of request never matches.
+func RequestFingerprint(operation string, fields ...any) string {
+	var b strings.Builder
+	b.WriteString(operation)
+	for _, field := range fields {
+		b.WriteByte(':')
+		b.WriteString(fingerprintField(field))
+	}
+	hash := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+func fingerprintField(field any) string {
+	switch v := field.(type) {
+	case string:
+		return strconv.Quote(v)
+	case map[string]string:
+		return canonicalMetadata(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// IdempotencyKeyReusedError reports a request replaying an idempotency key that was first used
+// with a different payload. It matches ErrIdempotencyKeyReused with errors.Is.
+type IdempotencyKeyReusedError struct {
+	Key string
+	// JournalEntryID is the entry posted by the request that first used the key.
+	// Zero if that request posted no entry, e.g. a hold or a scheduled transfer.
+	JournalEntryID JournalEntryID
+}
+
+func (e *IdempotencyKeyReusedError) Error() string {
+	if e.JournalEntryID == (JournalEntryID{}) {
+		return fmt.Sprintf("%v: key %q", ErrIdempotencyKeyReused, e.Key)
+	}
+	return fmt.Sprintf("%v: key %q was used for journal entry %s", ErrIdempotencyKeyReused, e.Key, e.JournalEntryID)
+}
+
+func (e *IdempotencyKeyReusedError) Unwrap() error {
+	return ErrIdempotencyKeyReused
+}
@@ -17,14 +17,62 @@ func (id JournalEntryID) String() string {
 	return uuid.UUID(id).String()
 }
 
+// EntryType classifies why points moved.
+type EntryType string
+
+const (
+	// EntryTypeTransfer moves existing points between accounts.
+	EntryTypeTransfer EntryType = "transfer"
+	// EntryTypeMint creates points by debiting the asset's issuer account.
+	EntryTypeMint EntryType = "mint"
+	// EntryTypeBurn destroys points by crediting the asset's issuer account.
+	EntryTypeBurn EntryType = "burn"
+	// EntryTypeReversal moves points of an earlier transfer back, fully or partially.
+	EntryTypeReversal EntryType = "reversal"
+	// EntryTypeEscrow moves points into or out of an escrow account.
+	EntryTypeEscrow EntryType = "escrow"
+	// EntryTypeFee charges a transfer fee to the sender. It is posted in the same group as the transfer.
+	EntryTypeFee EntryType = "fee"
+	// EntryTypeExpiry returns expired points to the issuer account.
+	EntryTypeExpiry EntryType = "expiry"
+)
+
+// MovesSystemAccounts reports whether entries of the type may debit or credit
+// the issuer and escrow accounts of an asset.
+func (t EntryType) MovesSystemAccounts() bool {
+	switch t {
+	case EntryTypeMint, EntryTypeBurn, EntryTypeExpiry, EntryTypeEscrow:
+		return true
+	default:
+		return false
+	}
+}
+
 // JournalEntry represents an immutable record of money movement.
 type JournalEntry struct {
 	ID             JournalEntryID
+	Type           EntryType
 	FromAccountID  AccountID
 	ToAccountID    AccountID
 	Amount         int64
 	Description    string
 	IdempotencyKey string
+	// GroupID links the entry to the other legs posted with it. Nil for standalone entries.
+	GroupID *JournalGroupID
+	// ReversesID is the entry this reversal undoes. Nil for other entries.
+	ReversesID *JournalEntryID
+	// ReversedByIDs are the reversals of this entry, oldest first, and ReversedAmount is
+	// their total. Both are derived from the reversals' ReversesID and not part of the hash.
+	ReversedByIDs  []JournalEntryID
+	ReversedAmount int64
+	// EscrowID links the entries funding and settling an escrow. Nil for other entries.
+	EscrowID *EscrowID
+	// Metadata holds structured key/value data supplied by the client, e.g. an order ID.
+	Metadata map[string]string
+	// RequestFingerprint identifies the request that posted the entry, see RequestFingerprint.
+	// Every posting records one; only entries written before migration 00026 have none.
+	// It is not part of the hash.
+	RequestFingerprint string
 
 	// Integrity
 	PreviousHash string
@@ -33,7 +81,9 @@ type JournalEntry struct {
 }
 
 // ComputeHash calculates the hash of the journal entry including the previous hash.
-// Hash = SHA256(PrevHash + ID + From + To + Amount + Timestamp + Idempotency)
+// Hash = SHA256(PrevHash + ID + From + To + Amount + Timestamp + Idempotency [+ Type] [+ GroupID] [+ ReversesID] [+ EscrowID] [+ Metadata])
+// Type, GroupID, ReversesID, EscrowID and Metadata are only included when they differ from a plain transfer
+// so that hashes of existing entries stay valid. Metadata is included sorted by key.
 func (t *JournalEntry) ComputeHash() string {
 	payload := fmt.Sprintf("%s:%s:%s:%s:%d:%d:%s",
 		t.PreviousHash,
@@ -44,6 +94,21 @@ func (t *JournalEntry) ComputeHash() string {
 		t.Timestamp.UnixNano(),
 		t.IdempotencyKey,
 	)
+	if t.Type != "" && t.Type != EntryTypeTransfer {
+		payload += ":type=" + string(t.Type)
+	}
+	if t.GroupID != nil {
+		payload += ":group=" + t.GroupID.String()
+	}
+	if t.ReversesID != nil {
+		payload += ":reverses=" + t.ReversesID.String()
+	}
+	if t.EscrowID != nil {
+		payload += ":escrow=" + t.EscrowID.String()
+	}
+	if len(t.Metadata) > 0 {
+		payload += ":metadata=" + canonicalMetadata(t.Metadata)
+	}
 	hash := sha256.Sum256([]byte(payload))
 	return hex.EncodeToString(hash[:])
 }
@@ -52,3 +117,18 @@ func (t *JournalEntry) ComputeHash() string {
 func (t *JournalEntry) ValidateHash() bool {
 	return t.Hash == t.ComputeHash()
 }
+
+// CheckReversible returns an error unless a reversal of amount may be posted for the entry.
+// Only transfers can be reversed, in parts until their whole amount is reversed.
+func (t *JournalEntry) CheckReversible(amount int64) error {
+	if t.Type != "" && t.Type != EntryTypeTransfer {
+		return ErrJournalEntryNotReversible
+	}
+	if t.ReversedAmount >= t.Amount {
+		return ErrJournalEntryAlreadyReversed
+	}
+	if amount > t.Amount-t.ReversedAmount {
+		return ErrReversalExceedsOriginal
+	}
+	return nil
+}
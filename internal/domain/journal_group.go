@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalGroupID identifies a group of journal entries posted together.
+type JournalGroupID uuid.UUID
+
+// String returns the string representation of JournalGroupID.
+func (id JournalGroupID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// JournalGroup links journal entries that were applied atomically as one posting,
+// e.g. the legs of a batch transfer.
+type JournalGroup struct {
+	ID             JournalGroupID
+	IdempotencyKey string
+	// RequestFingerprint identifies the request that posted the group.
+	// Only groups written before migration 00026 have none.
+	RequestFingerprint string
+	Description        string
+	CreatedAt          time.Time
+	// Entries are the legs of the group in posting order.
+	Entries []*JournalEntry
+}
+
+// LegIdempotencyKey derives the idempotency key of the i-th leg of a group.
+// Client keys cannot contain '#', so leg keys never collide with them.
+func LegIdempotencyKey(groupKey string, i int) string {
+	return groupKey + "#" + strconv.Itoa(i)
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+)
+
+const (
+	// MaxLabelKeyLength is the maximum allowed label key length.
+	MaxLabelKeyLength = 63
+	// MaxLabelValueLength is the maximum allowed label value length.
+	MaxLabelValueLength = 255
+	// MaxLabelsPerAccount is the maximum number of labels on a single account.
+	MaxLabelsPerAccount = 64
+)
+
+// labelKeyPattern allows lowercase alphanumerics with '.', '_', '-' and '/' inside, e.g. "kind" or "event/id".
+var labelKeyPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]*[a-z0-9])?$`)
+
+// ValidateLabelKey checks that key is a well-formed label key.
+func ValidateLabelKey(key string) error {
+	if len(key) > MaxLabelKeyLength || !labelKeyPattern.MatchString(key) {
+		return ErrInvalidLabel
+	}
+	return nil
+}
+
+// ValidateLabels checks every key and value and the number of labels.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxLabelsPerAccount {
+		return ErrTooManyLabels
+	}
+	for k, v := range labels {
+		if err := ValidateLabelKey(k); err != nil {
+			return err
+		}
+		if len(v) > MaxLabelValueLength {
+			return ErrInvalidLabel
+		}
+	}
+	return nil
+}
+
+// LabelOperator is the matching operator of a LabelSelector.
+type LabelOperator string
+
+const (
+	// LabelOpEquals matches when the label equals the single value.
+	LabelOpEquals LabelOperator = "equals"
+	// LabelOpIn matches when the label equals any of the values.
+	LabelOpIn LabelOperator = "in"
+	// LabelOpExists matches when the label is present, whatever its value.
+	LabelOpExists LabelOperator = "exists"
+)
+
+// LabelSelector matches accounts by one label.
+type LabelSelector struct {
+	Key      string
+	Operator LabelOperator
+	Values   []string
+}
+
+// Validate checks that the selector is well-formed for its operator.
+func (s LabelSelector) Validate() error {
+	if err := ValidateLabelKey(s.Key); err != nil {
+		return ErrInvalidLabelSelector
+	}
+	switch s.Operator {
+	case LabelOpEquals:
+		if len(s.Values) != 1 {
+			return ErrInvalidLabelSelector
+		}
+	case LabelOpIn:
+		if len(s.Values) == 0 {
+			return ErrInvalidLabelSelector
+		}
+	case LabelOpExists:
+		if len(s.Values) != 0 {
+			return ErrInvalidLabelSelector
+		}
+	default:
+		return ErrInvalidLabelSelector
+	}
+	return nil
+}
+
+// Matches reports whether labels satisfy the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	v, ok := labels[s.Key]
+	if !ok {
+		return false
+	}
+	switch s.Operator {
+	case LabelOpEquals, LabelOpIn:
+		return slices.Contains(s.Values, v)
+	case LabelOpExists:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetLabels upserts set and then deletes the keys in remove.
+// The label map is replaced rather than modified in place.
+func (a *Account) SetLabels(set map[string]string, remove []string) error {
+	if a.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+	for _, k := range remove {
+		if err := ValidateLabelKey(k); err != nil {
+			return err
+		}
+	}
+
+	labels := maps.Clone(a.Labels)
+	if labels == nil {
+		labels = make(map[string]string, len(set))
+	}
+	maps.Copy(labels, set)
+	for _, k := range remove {
+		delete(labels, k)
+	}
+
+	if err := ValidateLabels(labels); err != nil {
+		return err
+	}
+	a.Labels = labels
+	return nil
+}
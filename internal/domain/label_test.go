@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestValidateLabels(t *testing.T) {
+	valid := map[string]string{"kind": "wallet", "event/id": "2026-pool", "a": ""}
+	if err := ValidateLabels(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"", "Kind", "-kind", "kind-", "has space"} {
+		if err := ValidateLabels(map[string]string{key: "x"}); err != ErrInvalidLabel {
+			t.Errorf("key %q: expected ErrInvalidLabel, got %v", key, err)
+		}
+	}
+}
+
+func TestLabelSelector_Matches(t *testing.T) {
+	labels := map[string]string{"kind": "wallet", "team": "sysad"}
+
+	tests := []struct {
+		name string
+		sel  LabelSelector
+		want bool
+	}{
+		{"equals hit", LabelSelector{Key: "kind", Operator: LabelOpEquals, Values: []string{"wallet"}}, true},
+		{"equals miss", LabelSelector{Key: "kind", Operator: LabelOpEquals, Values: []string{"shop"}}, false},
+		{"in hit", LabelSelector{Key: "team", Operator: LabelOpIn, Values: []string{"game", "sysad"}}, true},
+		{"in missing key", LabelSelector{Key: "event", Operator: LabelOpIn, Values: []string{"x"}}, false},
+		{"exists hit", LabelSelector{Key: "team", Operator: LabelOpExists}, true},
+		{"exists miss", LabelSelector{Key: "event", Operator: LabelOpExists}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.sel.Matches(labels); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxMetadataEntries is the maximum number of metadata entries on a journal entry.
+	MaxMetadataEntries = 16
+	// MaxMetadataValueLength is the maximum allowed metadata value length.
+	MaxMetadataValueLength = 255
+)
+
+// ValidateMetadata checks the number of entries and every key and value.
+// Keys follow the same format as label keys.
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataEntries {
+		return ErrInvalidMetadata
+	}
+	for k, v := range metadata {
+		if err := ValidateLabelKey(k); err != nil {
+			return ErrInvalidMetadata
+		}
+		if len(v) > MaxMetadataValueLength {
+			return ErrInvalidMetadata
+		}
+	}
+	return nil
+}
+
+// canonicalMetadata encodes the metadata unambiguously, sorted by key, for hashing.
+func canonicalMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for _, k := range slices.Sorted(maps.Keys(metadata)) {
+		pairs = append(pairs, k+"="+strconv.Quote(metadata[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// JournalEntryFilter selects journal entries. Set fields must all match.
+type JournalEntryFilter struct {
+	// AccountID matches entries from or to the account.
+	AccountID *AccountID
+	// Metadata matches entries having every key with the given value.
+	Metadata map[string]string
+}
+
+// Validate checks that the filter selects by the account or by metadata.
+func (f JournalEntryFilter) Validate() error {
+	if f.AccountID == nil && len(f.Metadata) == 0 {
+		return ErrInvalidJournalEntryFilter
+	}
+	if err := ValidateMetadata(f.Metadata); err != nil {
+		return ErrInvalidJournalEntryFilter
+	}
+	return nil
+}
+
+// Matches reports whether the entry satisfies the filter.
+func (f JournalEntryFilter) Matches(e *JournalEntry) bool {
+	if f.AccountID != nil && e.FromAccountID != *f.AccountID && e.ToAccountID != *f.AccountID {
+		return false
+	}
+	for k, v := range f.Metadata {
+		if got, ok := e.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
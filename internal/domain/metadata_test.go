@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := range MaxMetadataEntries + 1 {
+		tooMany[string(rune('a'+i))] = "x"
+	}
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     error
+	}{
+		{"empty", nil, nil},
+		{"valid", map[string]string{"order_id": "123", "category": "food"}, nil},
+		{"bad key", map[string]string{"Order ID": "123"}, ErrInvalidMetadata},
+		{"long value", map[string]string{"note": strings.Repeat("x", MaxMetadataValueLength+1)}, ErrInvalidMetadata},
+		{"too many", tooMany, ErrInvalidMetadata},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMetadata(tt.metadata); err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestJournalEntry_ComputeHash_Metadata(t *testing.T) {
+	entry := &JournalEntry{
+		ID:             JournalEntryID(uuid.New()),
+		FromAccountID:  AccountID(uuid.New()),
+		ToAccountID:    AccountID(uuid.New()),
+		Amount:         100,
+		IdempotencyKey: "key",
+		Timestamp:      time.Now(),
+	}
+	plain := entry.ComputeHash()
+
+	entry.Metadata = map[string]string{}
+	if entry.ComputeHash() != plain {
+		t.Errorf("empty metadata must not change the hash")
+	}
+
+	entry.Metadata = map[string]string{"order_id": "123", "category": "food"}
+	withMetadata := entry.ComputeHash()
+	if withMetadata == plain {
+		t.Errorf("metadata must be part of the hash")
+	}
+	// Values are quoted, so they cannot be confused with another key.
+	entry.Metadata = map[string]string{"order_id": `123",category="food`}
+	if entry.ComputeHash() == withMetadata {
+		t.Errorf("distinct metadata must hash differently")
+	}
+}
+
+func TestJournalEntryFilter(t *testing.T) {
+	accID := AccountID(uuid.New())
+	entry := &JournalEntry{ToAccountID: accID, Metadata: map[string]string{"order_id": "123"}}
+
+	tests := []struct {
+		name    string
+		filter  JournalEntryFilter
+		valid   bool
+		matches bool
+	}{
+		{"account", JournalEntryFilter{AccountID: &accID}, true, true},
+		{"metadata", JournalEntryFilter{Metadata: map[string]string{"order_id": "123"}}, true, true},
+		{"other value", JournalEntryFilter{Metadata: map[string]string{"order_id": "124"}}, true, false},
+		{"missing key", JournalEntryFilter{AccountID: &accID, Metadata: map[string]string{"category": ""}}, true, false},
+		{"empty", JournalEntryFilter{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); (err == nil) != tt.valid {
+				t.Errorf("unexpected validation result %v", err)
+			}
+			if got := tt.filter.Matches(entry); got != tt.matches {
+				t.Errorf("expected match %v, got %v", tt.matches, got)
+			}
+		})
+	}
+}
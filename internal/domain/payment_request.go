@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentRequestID identifies a payment request.
+type PaymentRequestID uuid.UUID
+
+// String returns the string representation of PaymentRequestID.
+func (id PaymentRequestID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// PaymentRequestStatus represents the lifecycle state of a payment request.
+type PaymentRequestStatus string
+
+const (
+	// PaymentRequestStatusPending requests wait for the payer to accept or decline them.
+	PaymentRequestStatusPending PaymentRequestStatus = "pending"
+	// PaymentRequestStatusAccepted requests were paid by the payer.
+	PaymentRequestStatusAccepted PaymentRequestStatus = "accepted"
+	// PaymentRequestStatusDeclined requests were refused by the payer.
+	PaymentRequestStatusDeclined PaymentRequestStatus = "declined"
+	// PaymentRequestStatusExpired requests were neither accepted nor declined before their expiry.
+	PaymentRequestStatusExpired PaymentRequestStatus = "expired"
+)
+
+// PaymentRequest asks the payer to transfer an amount to the payee.
+// The payee creates it; the payer accepts or declines it.
+type PaymentRequest struct {
+	ID             PaymentRequestID
+	PayerAccountID AccountID
+	PayeeAccountID AccountID
+	Amount         int64
+	Description    string
+	Status         PaymentRequestStatus
+	// ExpiresAt is when a pending request expires. It can no longer be accepted after it.
+	ExpiresAt time.Time
+	// JournalEntryID is set once the request is accepted.
+	JournalEntryID *JournalEntryID
+	IdempotencyKey string
+	// RequestFingerprint identifies the request that created the payment request, see RequestFingerprint.
+	// Empty for rows written before migration 00026.
+	RequestFingerprint string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// TransferIdempotencyKey is the idempotency key of the transfer paying the request: the request ID.
+// Accepting the same request twice therefore pays at most once.
+func (r *PaymentRequest) TransferIdempotencyKey() string {
+	return r.ID.String()
+}
+
+// Accept records the journal entry that paid the pending request.
+func (r *PaymentRequest) Accept(entryID JournalEntryID, now time.Time) error {
+	if err := r.CheckAcceptable(now); err != nil {
+		return err
+	}
+	r.Status = PaymentRequestStatusAccepted
+	r.JournalEntryID = &entryID
+	r.UpdatedAt = now
+	return nil
+}
+
+// CheckAcceptable returns an error unless the request is pending and not expired at now.
+func (r *PaymentRequest) CheckAcceptable(now time.Time) error {
+	if r.Status != PaymentRequestStatusPending {
+		return ErrPaymentRequestNotPending
+	}
+	if !now.Before(r.ExpiresAt) {
+		return ErrPaymentRequestExpired
+	}
+	return nil
+}
+
+// Decline marks the pending request as declined by the payer.
+func (r *PaymentRequest) Decline(now time.Time) error {
+	return r.settle(PaymentRequestStatusDeclined, now)
+}
+
+// Expire marks the pending request as expired.
+func (r *PaymentRequest) Expire(now time.Time) error {
+	return r.settle(PaymentRequestStatusExpired, now)
+}
+
+func (r *PaymentRequest) settle(status PaymentRequestStatus, now time.Time) error {
+	if r.Status != PaymentRequestStatusPending {
+		return ErrPaymentRequestNotPending
+	}
+	r.Status = status
+	r.UpdatedAt = now
+	return nil
+}
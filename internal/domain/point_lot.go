@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PointLotID identifies a point lot.
+type PointLotID uuid.UUID
+
+// String returns the string representation of PointLotID.
+func (id PointLotID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// PointLot tracks the points of an asset with an expiry that one journal entry credited to an account.
+// Debits consume the lots that expire first.
+type PointLot struct {
+	ID        PointLotID
+	AccountID AccountID
+	Asset     AssetCode
+	// Amount is the credited amount and Remaining the part not yet spent or expired.
+	Amount         int64
+	Remaining      int64
+	ExpiresAt      time.Time
+	JournalEntryID JournalEntryID
+	CreatedAt      time.Time
+}
+
+// ExpiryIdempotencyKey is the idempotency key of the entry expiring the lot.
+func (l *PointLot) ExpiryIdempotencyKey() string {
+	return "expiry:" + l.ID.String()
+}
+
+// ConsumeLots takes amount from lots in order until it is covered or the lots run out,
+// and returns the lots that changed. lots must be ordered by expiry, soonest first.
+func ConsumeLots(lots []*PointLot, amount int64) []*PointLot {
+	var changed []*PointLot
+	for _, lot := range lots {
+		if amount <= 0 {
+			break
+		}
+		if lot.Remaining <= 0 {
+			continue
+		}
+		n := min(lot.Remaining, amount)
+		lot.Remaining -= n
+		amount -= n
+		changed = append(changed, lot)
+	}
+	return changed
+}
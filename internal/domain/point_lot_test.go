@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestConsumeLots(t *testing.T) {
+	lots := []*PointLot{{Remaining: 100}, {Remaining: 0}, {Remaining: 50}, {Remaining: 70}}
+
+	changed := ConsumeLots(lots, 120)
+	if len(changed) != 2 || changed[0] != lots[0] || changed[1] != lots[2] {
+		t.Fatalf("expected the first and third lots to change, got %v", changed)
+	}
+	if lots[0].Remaining != 0 || lots[2].Remaining != 30 || lots[3].Remaining != 70 {
+		t.Errorf("unexpected remaining %d/%d/%d", lots[0].Remaining, lots[2].Remaining, lots[3].Remaining)
+	}
+
+	// Debits beyond the tracked points leave the lots empty
+	ConsumeLots(lots, 1000)
+	for i, lot := range lots {
+		if lot.Remaining != 0 {
+			t.Errorf("lot %d: expected empty, got %d", i, lot.Remaining)
+		}
+	}
+}
+
+func TestAsset_SetPointTTL(t *testing.T) {
+	asset := &Asset{Code: DefaultAssetCode}
+	if err := asset.SetPointTTL(30); err != ErrIssuerNotConfigured {
+		t.Errorf("expected ErrIssuerNotConfigured, got %v", err)
+	}
+	asset.IssuerAccountID = &AccountID{}
+	if err := asset.SetPointTTL(MaxPointTTLDays + 1); err != ErrInvalidPointTTL {
+		t.Errorf("expected ErrInvalidPointTTL, got %v", err)
+	}
+	if err := asset.SetPointTTL(30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.PointExpiry(asset.CreatedAt).IsZero() {
+		t.Error("expected credited points to expire")
+	}
+	if err := asset.SetPointTTL(0); err != nil || !asset.PointExpiry(asset.CreatedAt).IsZero() {
+		t.Errorf("expected expiry to be off, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // SortField represents the field to sort by.
 type SortField string
@@ -20,9 +23,16 @@ const (
 
 // AccountFilter represents query filters for listing accounts.
 type AccountFilter struct {
-	MinBalance   *int64
-	MaxBalance   *int64
-	CanOverdraft *bool
+	MinBalance *int64
+	MaxBalance *int64
+	// CanOverdraft matches accounts with (true) or without (false) a positive credit limit.
+	CanOverdraft   *bool
+	MinCreditLimit *int64
+	MaxCreditLimit *int64
+	// LabelSelectors must all match.
+	LabelSelectors []LabelSelector
+	OwnerID        *string
+	Asset          *AssetCode
 }
 
 // AccountSort represents sorting options for listing accounts.
@@ -33,15 +43,133 @@ type AccountSort struct {
 
 // AccountRepository manages Account persistence.
 type AccountRepository interface {
+	// SaveAccount returns ErrAliasTaken if another account already uses the alias.
 	SaveAccount(ctx context.Context, account *Account) error
 	FindAccountByID(ctx context.Context, id AccountID) (*Account, error)
 	FindAccountsByIDs(ctx context.Context, ids []AccountID) ([]*Account, error)
 	GetAccountForUpdate(ctx context.Context, id AccountID) (*Account, error)
+	// FindAccountByAlias returns nil if no account has the alias.
+	FindAccountByAlias(ctx context.Context, alias string) (*Account, error)
+	// FindAccountsByParentIDs returns the direct sub-accounts of the given accounts.
+	FindAccountsByParentIDs(ctx context.Context, parentIDs []AccountID) ([]*Account, error)
+	// SaveAccountLabels replaces all labels of the account.
+	SaveAccountLabels(ctx context.Context, id AccountID, labels map[string]string) error
 	// ListAccounts returns accounts matching the filter with pagination and sorting.
 	// Returns (accounts, total_count, error).
 	ListAccounts(ctx context.Context, filter AccountFilter, sort AccountSort, limit, offset int) ([]*Account, int, error)
 }
 
+// AccountChangeRepository manages the history of account setting changes.
+type AccountChangeRepository interface {
+	SaveAccountChange(ctx context.Context, change *AccountChange) error
+}
+
+// AssetRepository manages the asset registry.
+type AssetRepository interface {
+	// CreateAsset inserts a new asset. It returns ErrAssetAlreadyExists if the code is taken.
+	CreateAsset(ctx context.Context, asset *Asset) error
+	SaveAsset(ctx context.Context, asset *Asset) error
+	// FindAssetByCode returns nil if the asset does not exist.
+	FindAssetByCode(ctx context.Context, code AssetCode) (*Asset, error)
+	GetAssetForUpdate(ctx context.Context, code AssetCode) (*Asset, error)
+	ListAssets(ctx context.Context) ([]*Asset, error)
+}
+
+// HoldRepository manages Hold persistence.
+type HoldRepository interface {
+	SaveHold(ctx context.Context, hold *Hold) error
+	// FindHoldByID returns nil if the hold does not exist.
+	FindHoldByID(ctx context.Context, id HoldID) (*Hold, error)
+	GetHoldForUpdate(ctx context.Context, id HoldID) (*Hold, error)
+	FindHoldByIdempotencyKey(ctx context.Context, key string) (*Hold, error)
+	// FindExpiredHolds returns up to limit authorized holds whose expiry is not after now.
+	FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*Hold, error)
+}
+
+// ScheduledTransferRepository manages ScheduledTransfer persistence.
+type ScheduledTransferRepository interface {
+	SaveScheduledTransfer(ctx context.Context, st *ScheduledTransfer) error
+	// FindScheduledTransferByID returns nil if the scheduled transfer does not exist.
+	FindScheduledTransferByID(ctx context.Context, id ScheduledTransferID) (*ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id ScheduledTransferID) (*ScheduledTransfer, error)
+	FindScheduledTransferByIdempotencyKey(ctx context.Context, key string) (*ScheduledTransfer, error)
+	// FindScheduledTransfersByAccountID returns scheduled transfers from or to the account.
+	FindScheduledTransfersByAccountID(ctx context.Context, accountID AccountID, limit, offset int) ([]*ScheduledTransfer, error)
+	// FindDueScheduledTransfers returns up to limit pending transfers whose execution time is not after now.
+	FindDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]*ScheduledTransfer, error)
+}
+
+// StandingOrderRepository manages StandingOrder persistence and run history.
+type StandingOrderRepository interface {
+	SaveStandingOrder(ctx context.Context, order *StandingOrder) error
+	// FindStandingOrderByID returns nil if the standing order does not exist.
+	FindStandingOrderByID(ctx context.Context, id StandingOrderID) (*StandingOrder, error)
+	GetStandingOrderForUpdate(ctx context.Context, id StandingOrderID) (*StandingOrder, error)
+	FindStandingOrderByIdempotencyKey(ctx context.Context, key string) (*StandingOrder, error)
+	// FindStandingOrdersByAccountID returns standing orders from or to the account.
+	FindStandingOrdersByAccountID(ctx context.Context, accountID AccountID, limit, offset int) ([]*StandingOrder, error)
+	// FindDueStandingOrders returns up to limit active orders whose next run is not after now.
+	FindDueStandingOrders(ctx context.Context, now time.Time, limit int) ([]*StandingOrder, error)
+
+	SaveStandingOrderRun(ctx context.Context, run *StandingOrderRun) error
+	// FindStandingOrderRuns returns the runs of the order, newest first.
+	FindStandingOrderRuns(ctx context.Context, orderID StandingOrderID, limit, offset int) ([]*StandingOrderRun, error)
+}
+
+// EscrowRepository manages Escrow persistence.
+type EscrowRepository interface {
+	SaveEscrow(ctx context.Context, escrow *Escrow) error
+	// FindEscrowByID returns nil if the escrow does not exist.
+	FindEscrowByID(ctx context.Context, id EscrowID) (*Escrow, error)
+	GetEscrowForUpdate(ctx context.Context, id EscrowID) (*Escrow, error)
+	FindEscrowByIdempotencyKey(ctx context.Context, key string) (*Escrow, error)
+	// FindExpiredEscrows returns up to limit held escrows whose deadline is not after now.
+	FindExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]*Escrow, error)
+}
+
+// PaymentRequestRepository manages PaymentRequest persistence.
+type PaymentRequestRepository interface {
+	SavePaymentRequest(ctx context.Context, req *PaymentRequest) error
+	// FindPaymentRequestByID returns nil if the payment request does not exist.
+	FindPaymentRequestByID(ctx context.Context, id PaymentRequestID) (*PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id PaymentRequestID) (*PaymentRequest, error)
+	FindPaymentRequestByIdempotencyKey(ctx context.Context, key string) (*PaymentRequest, error)
+	// FindPaymentRequestsByAccountID returns the payment requests to or from the account, most recently
+	// changed first. An empty status matches every status.
+	FindPaymentRequestsByAccountID(ctx context.Context, accountID AccountID, status PaymentRequestStatus, limit, offset int) ([]*PaymentRequest, error)
+	// FindExpiredPaymentRequests returns up to limit pending requests whose expiry is not after now.
+	FindExpiredPaymentRequests(ctx context.Context, now time.Time, limit int) ([]*PaymentRequest, error)
+}
+
+// FeeRuleRepository manages fee rules.
+type FeeRuleRepository interface {
+	SaveFeeRule(ctx context.Context, rule *FeeRule) error
+	ListFeeRules(ctx context.Context) ([]*FeeRule, error)
+	// DeleteFeeRule returns ErrFeeRuleNotFound if the rule does not exist.
+	DeleteFeeRule(ctx context.Context, id FeeRuleID) error
+}
+
+// VelocityLimitRepository manages per-account velocity limits and the aggregates they are checked against.
+type VelocityLimitRepository interface {
+	// FindVelocityLimits returns nil if the account has no limits of its own.
+	FindVelocityLimits(ctx context.Context, accountID AccountID) (*VelocityLimits, error)
+	SaveVelocityLimits(ctx context.Context, accountID AccountID, limits *VelocityLimits) error
+	// GetOutgoingTransferStats aggregates the debits the account initiated at or after since:
+	// transfers, their fees and escrow fundings. Fees add to the total but are not counted as transfers.
+	GetOutgoingTransferStats(ctx context.Context, accountID AccountID, since time.Time) (*OutgoingStats, error)
+}
+
+// PointLotRepository manages the lots of expiring points.
+type PointLotRepository interface {
+	SavePointLot(ctx context.Context, lot *PointLot) error
+	GetPointLot(ctx context.Context, id PointLotID) (*PointLot, error)
+	// FindOpenPointLots returns the lots of the account with points remaining, soonest expiry first.
+	FindOpenPointLots(ctx context.Context, accountID AccountID) ([]*PointLot, error)
+	// FindExpiredPointLots returns lots with points remaining that expired at or before now, soonest expiry first.
+	// Lots of frozen accounts are left out until the account is unfrozen.
+	FindExpiredPointLots(ctx context.Context, now time.Time, limit int) ([]*PointLot, error)
+}
+
 // JournalEntryRepository manages JournalEntry persistence.
 type JournalEntryRepository interface {
 	SaveJournalEntry(ctx context.Context, tx *JournalEntry) error
@@ -53,6 +181,13 @@ type JournalEntryRepository interface {
 	GetLatestJournalEntry(ctx context.Context) (*JournalEntry, error)
 
 	FindByAccountID(ctx context.Context, accountID AccountID, limit, offset int) ([]*JournalEntry, error)
+	// FindJournalEntries returns the entries matching the filter, newest first.
+	FindJournalEntries(ctx context.Context, filter JournalEntryFilter, limit, offset int) ([]*JournalEntry, error)
+
+	// SaveJournalGroup saves the group record. Its entries are saved with SaveJournalEntry.
+	SaveJournalGroup(ctx context.Context, group *JournalGroup) error
+	// FindJournalGroupByIdempotencyKey returns the group with its entries, or nil if none exists.
+	FindJournalGroupByIdempotencyKey(ctx context.Context, key string) (*JournalGroup, error)
 }
 
 // TransactionManager handles database transactions.
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinScheduleInterval is the shortest interval a recurring schedule may use.
+const MinScheduleInterval = time.Minute
+
+// cronSearchLimit bounds the search for the next match of a cron expression, e.g. for "0 0 30 2 *".
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule computes the run times of a recurring transfer.
+type Schedule interface {
+	// Next returns the first run time strictly after t, or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule returns the schedule for exactly one of interval and cronExpr.
+// Intervals must be whole seconds of at least MinScheduleInterval.
+func ParseSchedule(interval time.Duration, cronExpr string) (Schedule, error) {
+	switch {
+	case interval != 0 && cronExpr != "":
+		return nil, ErrInvalidSchedule
+	case cronExpr != "":
+		return ParseCron(cronExpr)
+	case interval < MinScheduleInterval || interval%time.Second != 0:
+		return nil, ErrInvalidSchedule
+	default:
+		return IntervalSchedule(interval), nil
+	}
+}
+
+// IntervalSchedule runs at a fixed interval.
+type IntervalSchedule time.Duration
+
+// Next returns t plus the interval.
+func (s IntervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// CronSchedule runs at the times matching a standard five-field cron expression
+// (minute, hour, day of month, month, day of week), evaluated in the server's local time zone.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny tell whether the day fields are "*". As in cron, when both are restricted
+	// a day matches if either field does.
+	domAny, dowAny bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are Sunday
+}
+
+// ParseCron parses a five-field cron expression. Fields accept "*", numbers, ranges "a-b",
+// steps "*/n" and "a-b/n", and comma-separated lists of those.
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, ErrInvalidSchedule
+	}
+
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	// Fold Sunday written as 7 into 0.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	s := &CronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+	if s.Next(time.Now()).IsZero() {
+		return nil, ErrInvalidSchedule
+	}
+	return s, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, ErrInvalidSchedule
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loPart); err != nil {
+				return 0, ErrInvalidSchedule
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiPart); err != nil {
+					return 0, ErrInvalidSchedule
+				}
+			} else if hasStep {
+				// "a/n" means from a to the end of the range
+				hi = f.max
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, ErrInvalidSchedule
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first matching minute strictly after t, or the zero time if none matches
+// within five years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := time.Local
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if s.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<t.Day()) != 0
+	dowMatch := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	if _, err := ParseSchedule(time.Hour, ""); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := ParseSchedule(0, "0 9 1 * *"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, tc := range []struct {
+		interval time.Duration
+		cron     string
+	}{
+		{0, ""},
+		{time.Second, ""},
+		{time.Hour, "0 9 1 * *"},
+		{0, "0 9 1 *"},
+		{0, "60 * * * *"},
+		{0, "0 0 30 2 *"},
+		{0, "*/0 * * * *"},
+	} {
+		if _, err := ParseSchedule(tc.interval, tc.cron); err != ErrInvalidSchedule {
+			t.Errorf("%v %q: expected ErrInvalidSchedule, got %v", tc.interval, tc.cron, err)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.Local)
+	}
+	for _, tc := range []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 9 1 * *", at(1, 15, 12, 0), at(2, 1, 9, 0)},
+		{"0 9 1 * *", at(2, 1, 9, 0), at(3, 1, 9, 0)},
+		{"*/15 * * * *", at(5, 5, 10, 7), at(5, 5, 10, 15)},
+		{"30 18 * * 1-5", at(5, 8, 19, 0), at(5, 11, 18, 30)}, // Friday evening to Monday
+		{"0 0 * * 7", at(5, 5, 0, 0), at(5, 10, 0, 0)},        // Sunday written as 7
+		{"0 0 13 * 5", at(3, 1, 0, 0), at(3, 6, 0, 0)},        // day of month or day of week
+	} {
+		s, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.expr, err)
+		}
+		if got := s.Next(tc.after); !got.Equal(tc.want) {
+			t.Errorf("%q after %v: expected %v, got %v", tc.expr, tc.after, tc.want, got)
+		}
+	}
+}
+
+func TestStandingOrder_Lifecycle(t *testing.T) {
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	endAt := start.Add(2 * time.Hour)
+	order := &StandingOrder{Interval: time.Hour, NextRunAt: start, EndAt: &endAt, Status: StandingOrderStatusActive}
+
+	if err := order.Advance(start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !order.NextRunAt.Equal(start.Add(time.Hour)) || order.Status != StandingOrderStatusActive {
+		t.Errorf("unexpected order after first run: %+v", order)
+	}
+
+	if err := order.Resume(start); err != ErrStandingOrderNotPaused {
+		t.Errorf("expected ErrStandingOrderNotPaused, got %v", err)
+	}
+	if err := order.Pause(start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Resuming after the next run skips it
+	if err := order.Resume(start.Add(90 * time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !order.NextRunAt.Equal(endAt) || order.Status != StandingOrderStatusActive {
+		t.Errorf("unexpected order after resume: %+v", order)
+	}
+
+	if err := order.Advance(endAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != StandingOrderStatusCompleted {
+		t.Errorf("expected order to complete after its end date, got %s", order.Status)
+	}
+	if err := order.Cancel(endAt); err != ErrStandingOrderFinished {
+		t.Errorf("expected ErrStandingOrderFinished, got %v", err)
+	}
+}
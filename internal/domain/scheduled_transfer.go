@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledTransferID identifies a scheduled transfer.
+type ScheduledTransferID uuid.UUID
+
+// String returns the string representation of ScheduledTransferID.
+func (id ScheduledTransferID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// ScheduledTransferStatus represents the lifecycle state of a scheduled transfer.
+type ScheduledTransferStatus string
+
+const (
+	// ScheduledTransferStatusPending transfers wait for their execution time.
+	ScheduledTransferStatusPending ScheduledTransferStatus = "pending"
+	// ScheduledTransferStatusExecuted transfers were posted to the journal.
+	ScheduledTransferStatusExecuted ScheduledTransferStatus = "executed"
+	// ScheduledTransferStatusFailed transfers were rejected when executed. FailureReason tells why.
+	ScheduledTransferStatusFailed ScheduledTransferStatus = "failed"
+	// ScheduledTransferStatusCanceled transfers were canceled before execution.
+	ScheduledTransferStatusCanceled ScheduledTransferStatus = "canceled"
+)
+
+// ScheduledTransfer is a transfer queued for execution at ExecuteAt.
+type ScheduledTransfer struct {
+	ID             ScheduledTransferID
+	FromAccountID  AccountID
+	ToAccountID    AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+	// RequestFingerprint identifies the request that scheduled the transfer, see RequestFingerprint.
+	// Empty for rows written before migration 00026.
+	RequestFingerprint string
+	ExecuteAt          time.Time
+	Status             ScheduledTransferStatus
+	// JournalEntryID is set once the transfer is executed.
+	JournalEntryID *JournalEntryID
+	FailureReason  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TransferIdempotencyKey is the idempotency key of the transfer executing the schedule.
+// Executing the same schedule twice therefore posts at most one journal entry.
+func (s *ScheduledTransfer) TransferIdempotencyKey() string {
+	return "scheduled:" + s.ID.String()
+}
+
+// MarkExecuted records the journal entry that executed the pending transfer.
+func (s *ScheduledTransfer) MarkExecuted(entryID JournalEntryID, now time.Time) error {
+	if err := s.settle(ScheduledTransferStatusExecuted, now); err != nil {
+		return err
+	}
+	s.JournalEntryID = &entryID
+	return nil
+}
+
+// MarkFailed records why the pending transfer could not be executed.
+func (s *ScheduledTransfer) MarkFailed(reason string, now time.Time) error {
+	if err := s.settle(ScheduledTransferStatusFailed, now); err != nil {
+		return err
+	}
+	s.FailureReason = reason
+	return nil
+}
+
+// Cancel cancels the pending transfer.
+func (s *ScheduledTransfer) Cancel(now time.Time) error {
+	return s.settle(ScheduledTransferStatusCanceled, now)
+}
+
+func (s *ScheduledTransfer) settle(status ScheduledTransferStatus, now time.Time) error {
+	if s.Status != ScheduledTransferStatusPending {
+		return ErrScheduledTransferNotPending
+	}
+	s.Status = status
+	s.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// SplitByWeight splits the non-negative total into integer shares proportional to the weights.
+// Each share is first rounded down; the points left over go one each to the shares with the
+// largest remainders, ties going to the earlier weight. The shares always sum to total.
+// Weights must be positive and their sum must fit in an int64.
+func SplitByWeight(total int64, weights []int64) ([]int64, error) {
+	if total < 0 || len(weights) == 0 {
+		return nil, ErrInvalidSplit
+	}
+	var sum int64
+	for _, w := range weights {
+		if w <= 0 || w > math.MaxInt64-sum {
+			return nil, ErrInvalidSplit
+		}
+		sum += w
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]uint64, len(weights))
+	left := total
+	for i, w := range weights {
+		// total*w/sum computed in 128 bits. The quotient is at most total, so it fits.
+		hi, lo := bits.Mul64(uint64(total), uint64(w))
+		q, r := bits.Div64(hi, lo, uint64(sum))
+		shares[i] = int64(q)
+		remainders[i] = r
+		left -= shares[i]
+	}
+
+	// left is less than len(weights), as each share lost less than one point to rounding.
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case remainders[a] > remainders[b]:
+			return -1
+		case remainders[a] < remainders[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+	for _, i := range order[:left] {
+		shares[i]++
+	}
+	return shares, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSplitByWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{"exact", 100, []int64{1, 1, 2}, []int64{25, 25, 50}},
+		{"largest remainder wins", 10, []int64{1, 2, 3}, []int64{2, 3, 5}},
+		{"ties go to the earlier recipient", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"share rounds down to zero", 2, []int64{1, 1, 1}, []int64{1, 1, 0}},
+		{"zero total", 0, []int64{3, 4}, []int64{0, 0}},
+		{"no overflow", 100_000_000_000, []int64{math.MaxInt64 / 2, math.MaxInt64 / 2}, []int64{50_000_000_000, 50_000_000_000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitByWeight(tt.total, tt.weights)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitByWeight_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []int64
+	}{
+		{"no weights", 10, nil},
+		{"zero weight", 10, []int64{1, 0}},
+		{"negative weight", 10, []int64{1, -1}},
+		{"weights overflow", 10, []int64{math.MaxInt64, 1}},
+		{"negative total", -1, []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SplitByWeight(tt.total, tt.weights); err != ErrInvalidSplit {
+				t.Errorf("expected ErrInvalidSplit, got %v", err)
+			}
+		})
+	}
+}
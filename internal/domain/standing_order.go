@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StandingOrderID identifies a standing order.
+type StandingOrderID uuid.UUID
+
+// String returns the string representation of StandingOrderID.
+func (id StandingOrderID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// StandingOrderStatus represents the lifecycle state of a standing order.
+type StandingOrderStatus string
+
+const (
+	// StandingOrderStatusActive orders run on their schedule.
+	StandingOrderStatusActive StandingOrderStatus = "active"
+	// StandingOrderStatusPaused orders skip their runs until resumed.
+	StandingOrderStatusPaused StandingOrderStatus = "paused"
+	// StandingOrderStatusCanceled orders were stopped for good.
+	StandingOrderStatusCanceled StandingOrderStatus = "canceled"
+	// StandingOrderStatusCompleted orders have no runs left before their end date.
+	StandingOrderStatusCompleted StandingOrderStatus = "completed"
+)
+
+// StandingOrder transfers a fixed amount on a recurring schedule.
+type StandingOrder struct {
+	ID            StandingOrderID
+	FromAccountID AccountID
+	ToAccountID   AccountID
+	Amount        int64
+	Description   string
+	// Exactly one of Interval and CronExpression is set.
+	Interval       time.Duration
+	CronExpression string
+	// EndAt is the last time a run may be scheduled at. Nil runs forever.
+	EndAt          *time.Time
+	NextRunAt      time.Time
+	Status         StandingOrderStatus
+	IdempotencyKey string
+	// RequestFingerprint identifies the request that created the order, see RequestFingerprint.
+	// Empty for rows written before migration 00026.
+	RequestFingerprint string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// Schedule returns the schedule of the order.
+func (o *StandingOrder) Schedule() (Schedule, error) {
+	return ParseSchedule(o.Interval, o.CronExpression)
+}
+
+// RunIdempotencyKey is the idempotency key of the transfer of the run scheduled at scheduledAt.
+// Each run posts at most one journal entry, however many workers attempt it.
+func (o *StandingOrder) RunIdempotencyKey(scheduledAt time.Time) string {
+	return "standing:" + o.ID.String() + ":" + strconv.FormatInt(scheduledAt.Unix(), 10)
+}
+
+// Advance moves NextRunAt to the run after the current one and completes the order
+// when that run would be past EndAt.
+func (o *StandingOrder) Advance(now time.Time) error {
+	schedule, err := o.Schedule()
+	if err != nil {
+		return err
+	}
+	o.setNextRunAt(schedule.Next(o.NextRunAt))
+	o.UpdatedAt = now
+	return nil
+}
+
+// Pause stops the active order from running until it is resumed.
+func (o *StandingOrder) Pause(now time.Time) error {
+	if o.Status != StandingOrderStatusActive {
+		return ErrStandingOrderNotActive
+	}
+	o.Status = StandingOrderStatusPaused
+	o.UpdatedAt = now
+	return nil
+}
+
+// Resume reactivates the paused order. Runs missed while paused are skipped.
+func (o *StandingOrder) Resume(now time.Time) error {
+	if o.Status != StandingOrderStatusPaused {
+		return ErrStandingOrderNotPaused
+	}
+	schedule, err := o.Schedule()
+	if err != nil {
+		return err
+	}
+	o.Status = StandingOrderStatusActive
+	for !o.NextRunAt.IsZero() && !o.NextRunAt.After(now) && o.Status == StandingOrderStatusActive {
+		o.setNextRunAt(schedule.Next(o.NextRunAt))
+	}
+	o.UpdatedAt = now
+	return nil
+}
+
+// Cancel stops the order for good.
+func (o *StandingOrder) Cancel(now time.Time) error {
+	if o.Status != StandingOrderStatusActive && o.Status != StandingOrderStatusPaused {
+		return ErrStandingOrderFinished
+	}
+	o.Status = StandingOrderStatusCanceled
+	o.UpdatedAt = now
+	return nil
+}
+
+func (o *StandingOrder) setNextRunAt(next time.Time) {
+	o.NextRunAt = next
+	if next.IsZero() || (o.EndAt != nil && next.After(*o.EndAt)) {
+		o.Status = StandingOrderStatusCompleted
+	}
+}
+
+// StandingOrderRunID identifies a run of a standing order.
+type StandingOrderRunID uuid.UUID
+
+// String returns the string representation of StandingOrderRunID.
+func (id StandingOrderRunID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// StandingOrderRunStatus is the outcome of a run.
+type StandingOrderRunStatus string
+
+const (
+	// StandingOrderRunStatusSucceeded runs posted their transfer.
+	StandingOrderRunStatusSucceeded StandingOrderRunStatus = "succeeded"
+	// StandingOrderRunStatusFailed runs were rejected. FailureReason tells why.
+	StandingOrderRunStatusFailed StandingOrderRunStatus = "failed"
+)
+
+// StandingOrderRun records one execution of a standing order.
+type StandingOrderRun struct {
+	ID              StandingOrderRunID
+	StandingOrderID StandingOrderID
+	ScheduledAt     time.Time
+	Status          StandingOrderRunStatus
+	// JournalEntryID is set for succeeded runs.
+	JournalEntryID *JournalEntryID
+	FailureReason  string
+	CreatedAt      time.Time
+}
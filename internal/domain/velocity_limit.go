@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// VelocityDailyWindow is the rolling window of VelocityLimits.MaxOutgoingPerDay.
+	VelocityDailyWindow = 24 * time.Hour
+	// VelocityHourlyWindow is the rolling window of VelocityLimits.MaxTransfersPerHour.
+	VelocityHourlyWindow = time.Hour
+)
+
+// VelocityLimit names one of the velocity limits.
+type VelocityLimit string
+
+const (
+	// VelocityLimitPerTransfer caps the amount of a single transfer.
+	VelocityLimitPerTransfer VelocityLimit = "max_per_transfer"
+	// VelocityLimitOutgoingPerDay caps the total sent in the last 24 hours.
+	VelocityLimitOutgoingPerDay VelocityLimit = "max_outgoing_per_day"
+	// VelocityLimitTransfersPerHour caps the number of transfers sent in the last hour.
+	VelocityLimitTransfersPerHour VelocityLimit = "max_transfers_per_hour"
+)
+
+// VelocityLimits restricts the outgoing transfers of an account. Nil fields are not limited.
+type VelocityLimits struct {
+	MaxPerTransfer      *int64
+	MaxOutgoingPerDay   *int64
+	MaxTransfersPerHour *int64
+}
+
+// Validate checks that no limit is negative.
+func (l VelocityLimits) Validate() error {
+	for _, v := range []*int64{l.MaxPerTransfer, l.MaxOutgoingPerDay, l.MaxTransfersPerHour} {
+		if v != nil && *v < 0 {
+			return ErrInvalidVelocityLimits
+		}
+	}
+	return nil
+}
+
+// Or returns the limits with the unset fields taken from defaults.
+func (l VelocityLimits) Or(defaults VelocityLimits) VelocityLimits {
+	if l.MaxPerTransfer == nil {
+		l.MaxPerTransfer = defaults.MaxPerTransfer
+	}
+	if l.MaxOutgoingPerDay == nil {
+		l.MaxOutgoingPerDay = defaults.MaxOutgoingPerDay
+	}
+	if l.MaxTransfersPerHour == nil {
+		l.MaxTransfersPerHour = defaults.MaxTransfersPerHour
+	}
+	return l
+}
+
+// IsZero reports whether no limit is set.
+func (l VelocityLimits) IsZero() bool {
+	return l.MaxPerTransfer == nil && l.MaxOutgoingPerDay == nil && l.MaxTransfersPerHour == nil
+}
+
+// OutgoingStats aggregates the transfers an account sent within a window.
+type OutgoingStats struct {
+	Total int64
+	Count int64
+	// Oldest is the time of the earliest transfer in the window, zero if there is none.
+	Oldest time.Time
+}
+
+// Check returns a *VelocityLimitError if sending amount would break a limit, given the
+// transfers sent in the last day and in the last hour.
+func (l VelocityLimits) Check(amount int64, day, hour OutgoingStats) error {
+	if l.MaxPerTransfer != nil && amount > *l.MaxPerTransfer {
+		return &VelocityLimitError{Limit: VelocityLimitPerTransfer, Max: *l.MaxPerTransfer}
+	}
+	if l.MaxOutgoingPerDay != nil && day.Total+amount > *l.MaxOutgoingPerDay {
+		return &VelocityLimitError{Limit: VelocityLimitOutgoingPerDay, Max: *l.MaxOutgoingPerDay, ResetsAt: resetsAt(day, VelocityDailyWindow)}
+	}
+	if l.MaxTransfersPerHour != nil && hour.Count+1 > *l.MaxTransfersPerHour {
+		return &VelocityLimitError{Limit: VelocityLimitTransfersPerHour, Max: *l.MaxTransfersPerHour, ResetsAt: resetsAt(hour, VelocityHourlyWindow)}
+	}
+	return nil
+}
+
+// resetsAt is when the oldest transfer leaves the window.
+func resetsAt(stats OutgoingStats, window time.Duration) time.Time {
+	if stats.Oldest.IsZero() {
+		return time.Time{}
+	}
+	return stats.Oldest.Add(window)
+}
+
+// VelocityLimitError reports which velocity limit a transfer broke.
+// It matches ErrVelocityLimitExceeded with errors.Is.
+type VelocityLimitError struct {
+	Limit VelocityLimit
+	Max   int64
+	// ResetsAt is when the oldest transfer counted against the limit leaves its window
+	// and frees capacity. It is zero when waiting does not help, e.g. for the per-transfer limit.
+	ResetsAt time.Time
+}
+
+func (e *VelocityLimitError) Error() string {
+	if e.ResetsAt.IsZero() {
+		return fmt.Sprintf("%v: %s of %d", ErrVelocityLimitExceeded, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("%v: %s of %d, resets at %s", ErrVelocityLimitExceeded, e.Limit, e.Max, e.ResetsAt.UTC().Format(time.RFC3339))
+}
+
+func (e *VelocityLimitError) Unwrap() error {
+	return ErrVelocityLimitExceeded
+}
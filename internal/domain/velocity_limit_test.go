@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVelocityLimits_Check(t *testing.T) {
+	perTransfer, perDay, perHour := int64(100), int64(300), int64(3)
+	limits := VelocityLimits{MaxPerTransfer: &perTransfer, MaxOutgoingPerDay: &perDay, MaxTransfersPerHour: &perHour}
+	oldest := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		amount    int64
+		day, hour OutgoingStats
+		wantLimit VelocityLimit
+		wantReset time.Time
+	}{
+		{"within limits", 100, OutgoingStats{Total: 200, Count: 2, Oldest: oldest}, OutgoingStats{Count: 2, Oldest: oldest}, "", time.Time{}},
+		{"per transfer", 101, OutgoingStats{}, OutgoingStats{}, VelocityLimitPerTransfer, time.Time{}},
+		{"per day", 50, OutgoingStats{Total: 260, Count: 3, Oldest: oldest}, OutgoingStats{}, VelocityLimitOutgoingPerDay, oldest.Add(24 * time.Hour)},
+		{"per hour", 10, OutgoingStats{Total: 30, Count: 3, Oldest: oldest}, OutgoingStats{Total: 30, Count: 3, Oldest: oldest}, VelocityLimitTransfersPerHour, oldest.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Check(tt.amount, tt.day, tt.hour)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var limitErr *VelocityLimitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrVelocityLimitExceeded) {
+				t.Fatalf("expected a VelocityLimitError, got %v", err)
+			}
+			if limitErr.Limit != tt.wantLimit || !limitErr.ResetsAt.Equal(tt.wantReset) {
+				t.Errorf("expected %s resetting at %v, got %s at %v", tt.wantLimit, tt.wantReset, limitErr.Limit, limitErr.ResetsAt)
+			}
+		})
+	}
+}
+
+func TestVelocityLimits_Or(t *testing.T) {
+	own, def := int64(10), int64(20)
+	limits := VelocityLimits{MaxPerTransfer: &own}.Or(VelocityLimits{MaxPerTransfer: &def, MaxOutgoingPerDay: &def})
+	if *limits.MaxPerTransfer != 10 || *limits.MaxOutgoingPerDay != 20 || limits.MaxTransfersPerHour != nil {
+		t.Errorf("unexpected limits %+v", limits)
+	}
+}
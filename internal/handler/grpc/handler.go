@@ -3,8 +3,11 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -18,16 +21,102 @@ type CornucopiaHandler struct {
 	pb.UnimplementedCornucopiaServiceServer
 	transferUC *usecase.TransferUseCase
 	accountUC  *usecase.AccountUseCase
+	assetUC    *usecase.AssetUseCase
+	issuanceUC *usecase.IssuanceUseCase
+	holdUC     *usecase.HoldUseCase
+	scheduleUC *usecase.ScheduledTransferUseCase
+	standingUC *usecase.StandingOrderUseCase
+	escrowUC   *usecase.EscrowUseCase
+	feeUC      *usecase.FeeUseCase
+	lotUC      *usecase.PointLotUseCase
+	paymentUC  *usecase.PaymentRequestUseCase
+}
+
+// HandlerOption wires an optional use case into a CornucopiaHandler.
+// The RPCs of use cases that are not wired fail with Unimplemented.
+type HandlerOption func(*CornucopiaHandler)
+
+// WithAssets serves the asset RPCs.
+func WithAssets(uc *usecase.AssetUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.assetUC = uc
+	}
+}
+
+// WithIssuance serves Mint, Burn and GetSupply.
+func WithIssuance(uc *usecase.IssuanceUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.issuanceUC = uc
+	}
+}
+
+// WithHolds serves the hold RPCs.
+func WithHolds(uc *usecase.HoldUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.holdUC = uc
+	}
+}
+
+// WithScheduledTransfers serves the scheduled transfer RPCs.
+func WithScheduledTransfers(uc *usecase.ScheduledTransferUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.scheduleUC = uc
+	}
+}
+
+// WithStandingOrders serves the standing order RPCs.
+func WithStandingOrders(uc *usecase.StandingOrderUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.standingUC = uc
+	}
+}
+
+// WithEscrows serves the escrow RPCs.
+func WithEscrows(uc *usecase.EscrowUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.escrowUC = uc
+	}
+}
+
+// WithFeeRules serves the fee rule RPCs.
+func WithFeeRules(uc *usecase.FeeUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.feeUC = uc
+	}
+}
+
+// WithPointLots reports upcoming point expirations in GetAccount.
+func WithPointLots(uc *usecase.PointLotUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.lotUC = uc
+	}
+}
+
+// WithPaymentRequests serves the payment request RPCs.
+func WithPaymentRequests(uc *usecase.PaymentRequestUseCase) HandlerOption {
+	return func(h *CornucopiaHandler) {
+		h.paymentUC = uc
+	}
 }
 
 func NewCornucopiaHandler(
 	transferUC *usecase.TransferUseCase,
 	accountUC *usecase.AccountUseCase,
+	opts ...HandlerOption,
 ) *CornucopiaHandler {
-	return &CornucopiaHandler{
+	h := &CornucopiaHandler{
 		transferUC: transferUC,
 		accountUC:  accountUC,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// notConfigured is returned by the RPCs of an optional use case the handler was built without.
+func notConfigured(feature string) error {
+	return status.Errorf(codes.Unimplemented, "%s not configured", feature)
 }
 
 func parseAccountID(s string) (domain.AccountID, error) {
@@ -38,22 +127,691 @@ func parseAccountID(s string) (domain.AccountID, error) {
 	return domain.AccountID(id), nil
 }
 
+// resolveAccountID accepts either an account ID or an "@alias" reference.
+// field names the request field in the InvalidArgument error.
+func (h *CornucopiaHandler) resolveAccountID(ctx context.Context, ref, field string) (domain.AccountID, error) {
+	if alias, ok := domain.ParseAliasReference(ref); ok {
+		acc, err := h.accountUC.ResolveAlias(ctx, alias)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidAlias) {
+				return domain.AccountID{}, status.Error(codes.InvalidArgument, "invalid "+field)
+			}
+			return domain.AccountID{}, toStatusError(err)
+		}
+		return acc.ID, nil
+	}
+
+	id, err := parseAccountID(ref)
+	if err != nil {
+		return domain.AccountID{}, status.Error(codes.InvalidArgument, "invalid "+field)
+	}
+	return id, nil
+}
+
+// trimAliasPrefix lets clients pass aliases with or without the leading "@".
+func trimAliasPrefix(alias *string) *string {
+	if alias == nil {
+		return nil
+	}
+	trimmed, _ := domain.ParseAliasReference(*alias)
+	return &trimmed
+}
+
+func toPBAccountStatus(s domain.AccountStatus) pb.AccountStatus {
+	switch s {
+	case domain.AccountStatusActive:
+		return pb.AccountStatus_ACCOUNT_STATUS_ACTIVE
+	case domain.AccountStatusFrozen:
+		return pb.AccountStatus_ACCOUNT_STATUS_FROZEN
+	case domain.AccountStatusClosed:
+		return pb.AccountStatus_ACCOUNT_STATUS_CLOSED
+	default:
+		return pb.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+	}
+}
+
+func toPBAccount(acc *domain.Account) *pb.Account {
+	return &pb.Account{
+		AccountId:        acc.ID.String(),
+		Asset:            string(acc.Asset),
+		Balance:          acc.Balance,
+		HeldBalance:      acc.HeldBalance,
+		AvailableBalance: acc.AvailableBalance(),
+		CanOverdraft:     acc.CanOverdraft(),
+		CreditLimit:      acc.CreditLimit,
+		MaxBalance:       acc.MaxBalance,
+		Headroom:         acc.Headroom(),
+		Status:           toPBAccountStatus(acc.Status),
+		Labels:           acc.Labels,
+		OwnerId:          acc.OwnerID,
+		Alias:            acc.Alias,
+		ParentAccountId:  optionalAccountIDString(acc.ParentID),
+		Version:          acc.Version,
+	}
+}
+
+func toPBAccountTreeNode(node *domain.AccountTreeNode) *pb.AccountTreeNode {
+	children := make([]*pb.AccountTreeNode, len(node.Children))
+	for i, child := range node.Children {
+		children[i] = toPBAccountTreeNode(child)
+	}
+	return &pb.AccountTreeNode{
+		Account:         toPBAccount(node.Account),
+		Balance:         node.Account.Balance,
+		RolledUpBalance: node.RolledUpBalance,
+		Children:        children,
+	}
+}
+
+func toPBAsset(asset *domain.Asset) *pb.Asset {
+	return &pb.Asset{
+		Code:            string(asset.Code),
+		Name:            asset.Name,
+		Precision:       int32(asset.Precision),
+		IssuerAccountId: optionalAccountIDString(asset.IssuerAccountID),
+		EscrowAccountId: optionalAccountIDString(asset.EscrowAccountID),
+		Supply:          asset.Supply,
+		PointTtlDays:    int32(asset.PointTTLDays),
+		CreatedAt:       timestamppb.New(asset.CreatedAt),
+	}
+}
+
+func toPBHoldStatus(s domain.HoldStatus) pb.HoldStatus {
+	switch s {
+	case domain.HoldStatusAuthorized:
+		return pb.HoldStatus_HOLD_STATUS_AUTHORIZED
+	case domain.HoldStatusCaptured:
+		return pb.HoldStatus_HOLD_STATUS_CAPTURED
+	case domain.HoldStatusVoided:
+		return pb.HoldStatus_HOLD_STATUS_VOIDED
+	case domain.HoldStatusExpired:
+		return pb.HoldStatus_HOLD_STATUS_EXPIRED
+	default:
+		return pb.HoldStatus_HOLD_STATUS_UNSPECIFIED
+	}
+}
+
+func toPBHold(hold *domain.Hold) *pb.Hold {
+	return &pb.Hold{
+		HoldId:         hold.ID.String(),
+		AccountId:      hold.AccountID.String(),
+		ToAccountId:    hold.ToAccountID.String(),
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Description:    hold.Description,
+		Status:         toPBHoldStatus(hold.Status),
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt),
+		CreatedAt:      timestamppb.New(hold.CreatedAt),
+		UpdatedAt:      timestamppb.New(hold.UpdatedAt),
+	}
+}
+
+func toPBScheduledTransferStatus(s domain.ScheduledTransferStatus) pb.ScheduledTransferStatus {
+	switch s {
+	case domain.ScheduledTransferStatusPending:
+		return pb.ScheduledTransferStatus_SCHEDULED_TRANSFER_STATUS_PENDING
+	case domain.ScheduledTransferStatusExecuted:
+		return pb.ScheduledTransferStatus_SCHEDULED_TRANSFER_STATUS_EXECUTED
+	case domain.ScheduledTransferStatusFailed:
+		return pb.ScheduledTransferStatus_SCHEDULED_TRANSFER_STATUS_FAILED
+	case domain.ScheduledTransferStatusCanceled:
+		return pb.ScheduledTransferStatus_SCHEDULED_TRANSFER_STATUS_CANCELED
+	default:
+		return pb.ScheduledTransferStatus_SCHEDULED_TRANSFER_STATUS_UNSPECIFIED
+	}
+}
+
+func toPBScheduledTransfer(st *domain.ScheduledTransfer) *pb.ScheduledTransfer {
+	return &pb.ScheduledTransfer{
+		ScheduledTransferId: st.ID.String(),
+		FromAccountId:       st.FromAccountID.String(),
+		ToAccountId:         st.ToAccountID.String(),
+		Amount:              st.Amount,
+		Description:         st.Description,
+		ExecuteAt:           timestamppb.New(st.ExecuteAt),
+		Status:              toPBScheduledTransferStatus(st.Status),
+		JournalEntryId:      optionalJournalEntryIDString(st.JournalEntryID),
+		FailureReason:       st.FailureReason,
+		CreatedAt:           timestamppb.New(st.CreatedAt),
+		UpdatedAt:           timestamppb.New(st.UpdatedAt),
+	}
+}
+
+func toPBStandingOrderStatus(s domain.StandingOrderStatus) pb.StandingOrderStatus {
+	switch s {
+	case domain.StandingOrderStatusActive:
+		return pb.StandingOrderStatus_STANDING_ORDER_STATUS_ACTIVE
+	case domain.StandingOrderStatusPaused:
+		return pb.StandingOrderStatus_STANDING_ORDER_STATUS_PAUSED
+	case domain.StandingOrderStatusCanceled:
+		return pb.StandingOrderStatus_STANDING_ORDER_STATUS_CANCELED
+	case domain.StandingOrderStatusCompleted:
+		return pb.StandingOrderStatus_STANDING_ORDER_STATUS_COMPLETED
+	default:
+		return pb.StandingOrderStatus_STANDING_ORDER_STATUS_UNSPECIFIED
+	}
+}
+
+func toPBStandingOrder(order *domain.StandingOrder) *pb.StandingOrder {
+	out := &pb.StandingOrder{
+		StandingOrderId: order.ID.String(),
+		FromAccountId:   order.FromAccountID.String(),
+		ToAccountId:     order.ToAccountID.String(),
+		Amount:          order.Amount,
+		Description:     order.Description,
+		NextRunAt:       timestamppb.New(order.NextRunAt),
+		Status:          toPBStandingOrderStatus(order.Status),
+		CreatedAt:       timestamppb.New(order.CreatedAt),
+		UpdatedAt:       timestamppb.New(order.UpdatedAt),
+	}
+	if order.Interval != 0 {
+		seconds := int64(order.Interval / time.Second)
+		out.IntervalSeconds = &seconds
+	}
+	if order.CronExpression != "" {
+		out.CronExpression = &order.CronExpression
+	}
+	if order.EndAt != nil {
+		out.EndAt = timestamppb.New(*order.EndAt)
+	}
+	return out
+}
+
+func toPBStandingOrderRun(run *domain.StandingOrderRun) *pb.StandingOrderRun {
+	runStatus := pb.StandingOrderRunStatus_STANDING_ORDER_RUN_STATUS_UNSPECIFIED
+	switch run.Status {
+	case domain.StandingOrderRunStatusSucceeded:
+		runStatus = pb.StandingOrderRunStatus_STANDING_ORDER_RUN_STATUS_SUCCEEDED
+	case domain.StandingOrderRunStatusFailed:
+		runStatus = pb.StandingOrderRunStatus_STANDING_ORDER_RUN_STATUS_FAILED
+	}
+	return &pb.StandingOrderRun{
+		RunId:          run.ID.String(),
+		ScheduledAt:    timestamppb.New(run.ScheduledAt),
+		Status:         runStatus,
+		JournalEntryId: optionalJournalEntryIDString(run.JournalEntryID),
+		FailureReason:  run.FailureReason,
+		CreatedAt:      timestamppb.New(run.CreatedAt),
+	}
+}
+
+func parseStandingOrderID(s string) (domain.StandingOrderID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return domain.StandingOrderID{}, status.Error(codes.InvalidArgument, "invalid standing_order_id")
+	}
+	return domain.StandingOrderID(id), nil
+}
+
+func toPBEscrow(escrow *domain.Escrow) *pb.Escrow {
+	escrowStatus := pb.EscrowStatus_ESCROW_STATUS_UNSPECIFIED
+	switch escrow.Status {
+	case domain.EscrowStatusHeld:
+		escrowStatus = pb.EscrowStatus_ESCROW_STATUS_HELD
+	case domain.EscrowStatusReleased:
+		escrowStatus = pb.EscrowStatus_ESCROW_STATUS_RELEASED
+	case domain.EscrowStatusRefunded:
+		escrowStatus = pb.EscrowStatus_ESCROW_STATUS_REFUNDED
+	}
+	return &pb.Escrow{
+		EscrowId:             escrow.ID.String(),
+		PayerAccountId:       escrow.PayerAccountID.String(),
+		BeneficiaryAccountId: escrow.BeneficiaryAccountID.String(),
+		EscrowAccountId:      escrow.EscrowAccountID.String(),
+		Amount:               escrow.Amount,
+		Description:          escrow.Description,
+		Status:               escrowStatus,
+		Deadline:             timestamppb.New(escrow.Deadline),
+		CreatedAt:            timestamppb.New(escrow.CreatedAt),
+		UpdatedAt:            timestamppb.New(escrow.UpdatedAt),
+	}
+}
+
+func parseEscrowID(s string) (domain.EscrowID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return domain.EscrowID{}, status.Error(codes.InvalidArgument, "invalid escrow_id")
+	}
+	return domain.EscrowID(id), nil
+}
+
+func toPBPaymentRequestStatus(s domain.PaymentRequestStatus) pb.PaymentRequestStatus {
+	switch s {
+	case domain.PaymentRequestStatusPending:
+		return pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_PENDING
+	case domain.PaymentRequestStatusAccepted:
+		return pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_ACCEPTED
+	case domain.PaymentRequestStatusDeclined:
+		return pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_DECLINED
+	case domain.PaymentRequestStatusExpired:
+		return pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_EXPIRED
+	default:
+		return pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+// toDomainPaymentRequestStatus converts a status filter. Unspecified matches every status.
+func toDomainPaymentRequestStatus(s pb.PaymentRequestStatus) (domain.PaymentRequestStatus, error) {
+	switch s {
+	case pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_UNSPECIFIED:
+		return "", nil
+	case pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_PENDING:
+		return domain.PaymentRequestStatusPending, nil
+	case pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_ACCEPTED:
+		return domain.PaymentRequestStatusAccepted, nil
+	case pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_DECLINED:
+		return domain.PaymentRequestStatusDeclined, nil
+	case pb.PaymentRequestStatus_PAYMENT_REQUEST_STATUS_EXPIRED:
+		return domain.PaymentRequestStatusExpired, nil
+	default:
+		return "", status.Error(codes.InvalidArgument, "invalid status")
+	}
+}
+
+func toPBPaymentRequest(req *domain.PaymentRequest) *pb.PaymentRequest {
+	return &pb.PaymentRequest{
+		PaymentRequestId: req.ID.String(),
+		PayerAccountId:   req.PayerAccountID.String(),
+		PayeeAccountId:   req.PayeeAccountID.String(),
+		Amount:           req.Amount,
+		Description:      req.Description,
+		Status:           toPBPaymentRequestStatus(req.Status),
+		ExpiresAt:        timestamppb.New(req.ExpiresAt),
+		JournalEntryId:   optionalJournalEntryIDString(req.JournalEntryID),
+		CreatedAt:        timestamppb.New(req.CreatedAt),
+		UpdatedAt:        timestamppb.New(req.UpdatedAt),
+	}
+}
+
+func parsePaymentRequestID(s string) (domain.PaymentRequestID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return domain.PaymentRequestID{}, status.Error(codes.InvalidArgument, "invalid payment_request_id")
+	}
+	return domain.PaymentRequestID(id), nil
+}
+
+func optionalEscrowIDString(id *domain.EscrowID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func toPBFeeRule(rule *domain.FeeRule) *pb.FeeRule {
+	out := &pb.FeeRule{
+		FeeRuleId:            rule.ID.String(),
+		Name:                 rule.Name,
+		AssetCode:            string(rule.Asset),
+		DestinationAccountId: optionalAccountIDString(rule.DestinationAccountID),
+		FlatFee:              rule.FlatFee,
+		RateBasisPoints:      rule.RateBasisPoints,
+		MinFee:               rule.MinFee,
+		MaxFee:               rule.MaxFee,
+		CollectorAccountId:   rule.CollectorAccountID.String(),
+		Priority:             int32(rule.Priority),
+		CreatedAt:            timestamppb.New(rule.CreatedAt),
+	}
+	if rule.SourceSelector != nil {
+		out.SourceSelector = toPBLabelSelector(*rule.SourceSelector)
+	}
+	return out
+}
+
+func parseFeeRuleID(s string) (domain.FeeRuleID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return domain.FeeRuleID{}, status.Error(codes.InvalidArgument, "invalid fee_rule_id")
+	}
+	return domain.FeeRuleID(id), nil
+}
+
+func optionalAccountIDString(id *domain.AccountID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func optionalJournalGroupIDString(id *domain.JournalGroupID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func optionalJournalEntryIDString(id *domain.JournalEntryID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func journalEntryIDStrings(ids []domain.JournalEntryID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return s
+}
+
+func toPBJournalEntryType(t domain.EntryType) pb.JournalEntryType {
+	switch t {
+	case domain.EntryTypeTransfer, "":
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_TRANSFER
+	case domain.EntryTypeMint:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_MINT
+	case domain.EntryTypeBurn:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_BURN
+	case domain.EntryTypeReversal:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_REVERSAL
+	case domain.EntryTypeEscrow:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_ESCROW
+	case domain.EntryTypeFee:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_FEE
+	case domain.EntryTypeExpiry:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_EXPIRY
+	default:
+		return pb.JournalEntryType_JOURNAL_ENTRY_TYPE_UNSPECIFIED
+	}
+}
+
+func toDomainLabelSelector(sel *pb.LabelSelector) domain.LabelSelector {
+	out := domain.LabelSelector{
+		Key:    sel.Key,
+		Values: sel.Values,
+	}
+	switch sel.Operator {
+	case pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_EQUALS:
+		out.Operator = domain.LabelOpEquals
+	case pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_IN:
+		out.Operator = domain.LabelOpIn
+	case pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_EXISTS:
+		out.Operator = domain.LabelOpExists
+	}
+	return out
+}
+
+func toPBLabelSelector(sel domain.LabelSelector) *pb.LabelSelector {
+	out := &pb.LabelSelector{
+		Key:    sel.Key,
+		Values: sel.Values,
+	}
+	switch sel.Operator {
+	case domain.LabelOpEquals:
+		out.Operator = pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_EQUALS
+	case domain.LabelOpIn:
+		out.Operator = pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_IN
+	case domain.LabelOpExists:
+		out.Operator = pb.LabelSelectorOperator_LABEL_SELECTOR_OPERATOR_EXISTS
+	}
+	return out
+}
+
+// velocityLimitStatusError reports the broken limit and its reset time as ErrorInfo metadata,
+// so that callers can back off without parsing the message.
+func velocityLimitStatusError(err error) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+	var limitErr *domain.VelocityLimitError
+	if !errors.As(err, &limitErr) {
+		return st.Err()
+	}
+	info := &errdetails.ErrorInfo{
+		Reason: "VELOCITY_LIMIT_EXCEEDED",
+		Metadata: map[string]string{
+			"limit": string(limitErr.Limit),
+			"max":   fmt.Sprint(limitErr.Max),
+		},
+	}
+	if !limitErr.ResetsAt.IsZero() {
+		info.Metadata["resets_at"] = limitErr.ResetsAt.UTC().Format(time.RFC3339)
+	}
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// idempotencyKeyReusedStatusError reports the reused key and the entry posted with it, if any, as ErrorInfo metadata.
+func idempotencyKeyReusedStatusError(err error) error {
+	st := status.New(codes.AlreadyExists, err.Error())
+	var reusedErr *domain.IdempotencyKeyReusedError
+	if !errors.As(err, &reusedErr) {
+		return st.Err()
+	}
+	info := &errdetails.ErrorInfo{
+		Reason:   "IDEMPOTENCY_KEY_REUSED",
+		Metadata: map[string]string{"idempotency_key": reusedErr.Key},
+	}
+	if reusedErr.JournalEntryID != (domain.JournalEntryID{}) {
+		info.Metadata["journal_entry_id"] = reusedErr.JournalEntryID.String()
+	}
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func toPBVelocityLimits(l domain.VelocityLimits) *pb.VelocityLimits {
+	return &pb.VelocityLimits{
+		MaxPerTransfer:      l.MaxPerTransfer,
+		MaxOutgoingPerDay:   l.MaxOutgoingPerDay,
+		MaxTransfersPerHour: l.MaxTransfersPerHour,
+	}
+}
+
+// creditLimitOrLegacy returns creditLimit when set, otherwise translates the
+// legacy can_overdraft flag into an unlimited or zero credit limit.
+func creditLimitOrLegacy(creditLimit *int64, canOverdraft bool) int64 {
+	if creditLimit != nil {
+		return *creditLimit
+	}
+	if canOverdraft {
+		return domain.UnlimitedCreditLimit
+	}
+	return 0
+}
+
+// toStatusError maps domain errors to gRPC status errors.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrVelocityLimitExceeded):
+		return velocityLimitStatusError(err)
+	case errors.Is(err, domain.ErrVelocityLimitsNotConfigured):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidVelocityLimits):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidAmount):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrSelfTransfer):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidIdempotencyKey):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return idempotencyKeyReusedStatusError(err)
+	case errors.Is(err, domain.ErrAmountTooLarge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrDescriptionTooLong):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrBalanceOverflow):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidCreditLimit):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrCreditLimitBelowDebt):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidMaxBalance):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrMaxBalanceExceeded):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrMaxBalanceBelowBalance):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrAccountFrozen):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrAccountClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrAccountNotEmpty):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidLabel):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrTooManyLabels):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidLabelSelector):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidOwnerID):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAssetNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrAssetAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrInvalidAsset):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAssetMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrIssuerNotConfigured):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInsufficientSupply):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrSystemAccount):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrIssuerInUse):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrAccountTreeTooDeep):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrIntraTreeTransfer):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidAlias):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAliasTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrInvalidBatchSize):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrJournalEntryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrJournalEntryNotReversible):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrJournalEntryAlreadyReversed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrReversalExceedsOriginal):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrHoldNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrHoldNotAuthorized):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrHoldExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrCaptureExceedsHold):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidHoldExpiry):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrScheduledTransferNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrScheduledTransferNotPending):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidExecuteAt):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidStandingOrderEnd):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrStandingOrderNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrStandingOrderNotActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrStandingOrderNotPaused):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrStandingOrderFinished):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrEscrowNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrEscrowNotHeld):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrEscrowExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidEscrowDeadline):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidFeeRule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrFeeRuleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidPointTTL):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrPaymentRequestNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrPaymentRequestNotPending):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrPaymentRequestExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidPaymentRequestExpiry):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidSplit):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrDuplicateSplitRecipient):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidMetadata):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidJournalEntryFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrInvalidChangedBy):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
 func (h *CornucopiaHandler) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
-	acc, err := h.accountUC.CreateAccount(ctx, req.CanOverdraft)
+	input := usecase.CreateAccountInput{
+		Asset:       domain.AssetCode(req.Asset),
+		CreditLimit: creditLimitOrLegacy(req.CreditLimit, req.CanOverdraft),
+		MaxBalance:  req.MaxBalance,
+		Labels:      req.Labels,
+		OwnerID:     req.OwnerId,
+		Alias:       *trimAliasPrefix(&req.Alias),
+	}
+	if req.ParentAccountId != nil {
+		parentID, err := h.resolveAccountID(ctx, *req.ParentAccountId, "parent_account_id")
+		if err != nil {
+			return nil, err
+		}
+		input.ParentID = &parentID
+	}
+
+	acc, err := h.accountUC.CreateAccount(ctx, input)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
 	return &pb.CreateAccountResponse{
-		AccountId:    acc.ID.String(),
-		Balance:      acc.Balance,
-		CanOverdraft: acc.CanOverdraft,
+		AccountId:        acc.ID.String(),
+		Asset:            string(acc.Asset),
+		Balance:          acc.Balance,
+		HeldBalance:      acc.HeldBalance,
+		AvailableBalance: acc.AvailableBalance(),
+		CanOverdraft:     acc.CanOverdraft(),
+		CreditLimit:      acc.CreditLimit,
+		MaxBalance:       acc.MaxBalance,
+		Headroom:         acc.Headroom(),
+		Status:           toPBAccountStatus(acc.Status),
+		Labels:           acc.Labels,
+		OwnerId:          acc.OwnerID,
+		Alias:            acc.Alias,
+		ParentAccountId:  optionalAccountIDString(acc.ParentID),
+		Version:          acc.Version,
 	}, nil
 }
 
 func (h *CornucopiaHandler) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
-	id, err := parseAccountID(req.AccountId)
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid account_id")
+		return nil, err
 	}
 
 	acc, err := h.accountUC.GetAccount(ctx, id)
@@ -63,135 +821,472 @@ func (h *CornucopiaHandler) GetAccount(ctx context.Context, req *pb.GetAccountRe
 	if acc == nil {
 		return nil, status.Error(codes.NotFound, "account not found")
 	}
+	// Point expiry is optional
+	var expirations []*pb.PointExpiration
+	if h.lotUC != nil {
+		lots, err := h.lotUC.UpcomingExpirations(ctx, acc.ID)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		expirations = make([]*pb.PointExpiration, len(lots))
+		for i, lot := range lots {
+			expirations[i] = &pb.PointExpiration{
+				Amount:    lot.Remaining,
+				ExpiresAt: timestamppb.New(lot.ExpiresAt),
+			}
+		}
+	}
 	return &pb.GetAccountResponse{
-		AccountId:    acc.ID.String(),
-		Balance:      acc.Balance,
-		CanOverdraft: acc.CanOverdraft,
+		AccountId:           acc.ID.String(),
+		Asset:               string(acc.Asset),
+		Balance:             acc.Balance,
+		HeldBalance:         acc.HeldBalance,
+		AvailableBalance:    acc.AvailableBalance(),
+		CanOverdraft:        acc.CanOverdraft(),
+		CreditLimit:         acc.CreditLimit,
+		MaxBalance:          acc.MaxBalance,
+		Headroom:            acc.Headroom(),
+		Status:              toPBAccountStatus(acc.Status),
+		Labels:              acc.Labels,
+		OwnerId:             acc.OwnerID,
+		Alias:               acc.Alias,
+		ParentAccountId:     optionalAccountIDString(acc.ParentID),
+		Version:             acc.Version,
+		UpcomingExpirations: expirations,
 	}, nil
 }
 
-func (h *CornucopiaHandler) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
-	fromID, err := parseAccountID(req.FromAccountId)
+func (h *CornucopiaHandler) GetAccountTree(ctx context.Context, req *pb.GetAccountTreeRequest) (*pb.GetAccountTreeResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid from_account_id")
+		return nil, err
 	}
-	toID, err := parseAccountID(req.ToAccountId)
+
+	tree, err := h.accountUC.GetAccountTree(ctx, id)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid to_account_id")
+		return nil, toStatusError(err)
 	}
+	return &pb.GetAccountTreeResponse{
+		Root: toPBAccountTreeNode(tree),
+	}, nil
+}
 
-	input := usecase.TransferInput{
-		FromAccountID:  fromID,
-		ToAccountID:    toID,
-		Amount:         req.Amount,
-		Description:    req.Description,
-		IdempotencyKey: req.IdempotencyKey,
+func (h *CornucopiaHandler) ResolveAlias(ctx context.Context, req *pb.ResolveAliasRequest) (*pb.ResolveAliasResponse, error) {
+	acc, err := h.accountUC.ResolveAlias(ctx, *trimAliasPrefix(&req.Alias))
+	if err != nil {
+		return nil, toStatusError(err)
 	}
+	return &pb.ResolveAliasResponse{
+		Account: toPBAccount(acc),
+	}, nil
+}
 
-	out, err := h.transferUC.Transfer(ctx, input)
+func (h *CornucopiaHandler) UpdateAccount(ctx context.Context, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrAccountNotFound):
-			return nil, status.Error(codes.NotFound, err.Error())
-		case errors.Is(err, domain.ErrInsufficientBalance):
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		case errors.Is(err, domain.ErrInvalidAmount):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, domain.ErrSelfTransfer):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, domain.ErrInvalidIdempotencyKey):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, domain.ErrAmountTooLarge):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, domain.ErrDescriptionTooLong):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, domain.ErrBalanceOverflow):
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		default:
-			return nil, status.Error(codes.Internal, err.Error())
-		}
+		return nil, err
 	}
 
-	return &pb.TransferResponse{
-		JournalEntryId: out.JournalEntryID.String(),
-		CreatedAt:      timestamppb.New(out.CreatedAt),
+	input := usecase.UpdateAccountInput{
+		AccountID:       id,
+		MaxBalance:      req.MaxBalance,
+		ClearMaxBalance: req.ClearMaxBalance,
+		OwnerID:         req.OwnerId,
+		Alias:           trimAliasPrefix(req.Alias),
+		Force:           req.Force,
+		ChangedBy:       req.ChangedBy,
+	}
+	if req.CreditLimit != nil || req.CanOverdraft != nil {
+		creditLimit := creditLimitOrLegacy(req.CreditLimit, req.GetCanOverdraft())
+		input.CreditLimit = &creditLimit
+	}
+
+	acc, err := h.accountUC.UpdateAccount(ctx, input)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.UpdateAccountResponse{
+		Account: toPBAccount(acc),
 	}, nil
 }
 
-func (h *CornucopiaHandler) GetJournalEntries(ctx context.Context, req *pb.GetJournalEntriesRequest) (*pb.GetJournalEntriesResponse, error) {
-	id, err := parseAccountID(req.AccountId)
+func (h *CornucopiaHandler) SetAccountLabels(ctx context.Context, req *pb.SetAccountLabelsRequest) (*pb.SetAccountLabelsResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid account_id")
+		return nil, err
 	}
 
-	entries, err := h.transferUC.GetJournalEntries(ctx, id, int(req.Limit), int(req.Offset))
+	input := usecase.SetAccountLabelsInput{
+		AccountID: id,
+		Set:       req.Labels,
+		Remove:    req.RemoveKeys,
+		ChangedBy: req.ChangedBy,
+	}
 
+	acc, err := h.accountUC.SetAccountLabels(ctx, input)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
+	return &pb.SetAccountLabelsResponse{
+		Account: toPBAccount(acc),
+	}, nil
+}
 
-	pbEntries := make([]*pb.JournalEntry, len(entries))
-	for i, e := range entries {
-		pbEntries[i] = &pb.JournalEntry{
-			JournalEntryId: e.ID.String(),
-			FromAccountId:  e.FromAccountID.String(),
-			ToAccountId:    e.ToAccountID.String(),
-			Amount:         e.Amount,
-			Description:    e.Description,
-			CreatedAt:      timestamppb.New(e.Timestamp),
-		}
+func (h *CornucopiaHandler) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
 	}
 
-	return &pb.GetJournalEntriesResponse{
-		JournalEntries: pbEntries,
+	acc, err := h.accountUC.FreezeAccount(ctx, id, req.ChangedBy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.FreezeAccountResponse{
+		Account: toPBAccount(acc),
 	}, nil
 }
 
-func (h *CornucopiaHandler) GetAccounts(ctx context.Context, req *pb.GetAccountsRequest) (*pb.GetAccountsResponse, error) {
-	ids := make([]domain.AccountID, 0, len(req.AccountIds))
-	for _, idStr := range req.AccountIds {
-		id, err := parseAccountID(idStr)
-		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, "invalid account_id: "+idStr)
-		}
-		ids = append(ids, id)
+func (h *CornucopiaHandler) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
 	}
 
-	accounts, err := h.accountUC.GetAccounts(ctx, ids)
+	acc, err := h.accountUC.UnfreezeAccount(ctx, id, req.ChangedBy)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
+	return &pb.UnfreezeAccountResponse{
+		Account: toPBAccount(acc),
+	}, nil
+}
 
-	pbAccounts := make([]*pb.Account, len(accounts))
-	for i, acc := range accounts {
-		pbAccounts[i] = &pb.Account{
-			AccountId:    acc.ID.String(),
-			Balance:      acc.Balance,
-			CanOverdraft: acc.CanOverdraft,
-		}
+func (h *CornucopiaHandler) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
 	}
 
-	return &pb.GetAccountsResponse{
-		Accounts: pbAccounts,
+	acc, err := h.accountUC.CloseAccount(ctx, id, req.ChangedBy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CloseAccountResponse{
+		Account: toPBAccount(acc),
 	}, nil
 }
 
-func (h *CornucopiaHandler) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
-	// Build filter
-	filter := domain.AccountFilter{}
-	if req.MinBalance != nil {
-		filter.MinBalance = req.MinBalance
+func (h *CornucopiaHandler) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
 	}
-	if req.MaxBalance != nil {
-		filter.MaxBalance = req.MaxBalance
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
 	}
-	if req.CanOverdraft != nil {
-		filter.CanOverdraft = req.CanOverdraft
+
+	input := usecase.TransferInput{
+		FromAccountID:       fromID,
+		ToAccountID:         toID,
+		Amount:              req.Amount,
+		Description:         req.Description,
+		IdempotencyKey:      req.IdempotencyKey,
+		Metadata:            req.Metadata,
+		ExpectedFromVersion: req.ExpectedFromVersion,
+		ExpectedToVersion:   req.ExpectedToVersion,
+		MinFromBalance:      req.MinFromBalance,
 	}
 
-	// Build sort
-	sort := domain.AccountSort{}
-	switch req.SortField {
+	out, err := h.transferUC.Transfer(ctx, input)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.TransferResponse{
+		JournalEntryId:    out.JournalEntryID.String(),
+		CreatedAt:         timestamppb.New(out.CreatedAt),
+		Fee:               out.Fee,
+		FeeJournalEntryId: optionalJournalEntryIDString(out.FeeJournalEntryID),
+	}, nil
+}
+
+func (h *CornucopiaHandler) QuoteTransfer(ctx context.Context, req *pb.QuoteTransferRequest) (*pb.QuoteTransferResponse, error) {
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := h.transferUC.QuoteTransfer(ctx, usecase.QuoteTransferInput{
+		FromAccountID: fromID,
+		ToAccountID:   toID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &pb.QuoteTransferResponse{
+		Amount:             quote.Amount,
+		Fee:                quote.Fee,
+		Total:              quote.Total,
+		CollectorAccountId: optionalAccountIDString(quote.CollectorAccountID),
+	}
+	if quote.FeeRuleID != nil {
+		id := quote.FeeRuleID.String()
+		resp.FeeRuleId = &id
+	}
+	return resp, nil
+}
+
+func (h *CornucopiaHandler) SimulateTransfer(ctx context.Context, req *pb.SimulateTransferRequest) (*pb.SimulateTransferResponse, error) {
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := h.transferUC.SimulateTransfer(ctx, usecase.TransferInput{
+		FromAccountID:       fromID,
+		ToAccountID:         toID,
+		Amount:              req.Amount,
+		Description:         req.Description,
+		ExpectedFromVersion: req.ExpectedFromVersion,
+		ExpectedToVersion:   req.ExpectedToVersion,
+		MinFromBalance:      req.MinFromBalance,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &pb.SimulateTransferResponse{
+		Amount:               sim.Amount,
+		Fee:                  sim.Fee,
+		Total:                sim.Total,
+		FromBalance:          sim.FromBalance,
+		FromAvailableBalance: sim.FromAvailableBalance,
+		ToBalance:            sim.ToBalance,
+		CollectorAccountId:   optionalAccountIDString(sim.CollectorAccountID),
+		CollectorBalance:     sim.CollectorBalance,
+	}
+	if sim.FeeRuleID != nil {
+		id := sim.FeeRuleID.String()
+		resp.FeeRuleId = &id
+	}
+	return resp, nil
+}
+
+func (h *CornucopiaHandler) BatchTransfer(ctx context.Context, req *pb.BatchTransferRequest) (*pb.BatchTransferResponse, error) {
+	legs := make([]usecase.TransferLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		fromID, err := h.resolveAccountID(ctx, leg.FromAccountId, fmt.Sprintf("legs[%d].from_account_id", i))
+		if err != nil {
+			return nil, err
+		}
+		toID, err := h.resolveAccountID(ctx, leg.ToAccountId, fmt.Sprintf("legs[%d].to_account_id", i))
+		if err != nil {
+			return nil, err
+		}
+		legs[i] = usecase.TransferLeg{
+			FromAccountID: fromID,
+			ToAccountID:   toID,
+			Amount:        leg.Amount,
+			Description:   leg.Description,
+		}
+	}
+
+	out, err := h.transferUC.BatchTransfer(ctx, usecase.BatchTransferInput{
+		Legs:           legs,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	entryIDs := make([]string, len(out.JournalEntryIDs))
+	for i, id := range out.JournalEntryIDs {
+		entryIDs[i] = id.String()
+	}
+	return &pb.BatchTransferResponse{
+		GroupId:         out.GroupID.String(),
+		JournalEntryIds: entryIDs,
+		CreatedAt:       timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) SplitTransfer(ctx context.Context, req *pb.SplitTransferRequest) (*pb.SplitTransferResponse, error) {
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+	recipients := make([]usecase.SplitRecipient, len(req.Recipients))
+	for i, r := range req.Recipients {
+		id, err := h.resolveAccountID(ctx, r.AccountId, fmt.Sprintf("recipients[%d].account_id", i))
+		if err != nil {
+			return nil, err
+		}
+		recipients[i] = usecase.SplitRecipient{AccountID: id, Weight: r.Weight}
+	}
+
+	out, err := h.transferUC.SplitTransfer(ctx, usecase.SplitTransferInput{
+		FromAccountID:  fromID,
+		Amount:         req.Amount,
+		Recipients:     recipients,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	shares := make([]*pb.SplitShare, len(out.Shares))
+	for i, share := range out.Shares {
+		shares[i] = &pb.SplitShare{
+			AccountId:      share.AccountID.String(),
+			Amount:         share.Amount,
+			JournalEntryId: optionalJournalEntryIDString(share.JournalEntryID),
+		}
+	}
+	return &pb.SplitTransferResponse{
+		GroupId:   out.GroupID.String(),
+		Shares:    shares,
+		CreatedAt: timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ReverseJournalEntry(ctx context.Context, req *pb.ReverseJournalEntryRequest) (*pb.ReverseJournalEntryResponse, error) {
+	id, err := uuid.Parse(req.JournalEntryId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid journal_entry_id")
+	}
+
+	out, err := h.transferUC.ReverseJournalEntry(ctx, usecase.ReverseJournalEntryInput{
+		JournalEntryID: domain.JournalEntryID(id),
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.ReverseJournalEntryResponse{
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+// GetJournalEntries lists the entries of the account, of the metadata, or both. The account is optional
+// when filtering by metadata.
+func (h *CornucopiaHandler) GetJournalEntries(ctx context.Context, req *pb.GetJournalEntriesRequest) (*pb.GetJournalEntriesResponse, error) {
+	filter := domain.JournalEntryFilter{Metadata: req.Metadata}
+	if req.AccountId != "" || len(req.Metadata) == 0 {
+		id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+		if err != nil {
+			return nil, err
+		}
+		filter.AccountID = &id
+	}
+
+	entries, err := h.transferUC.FindJournalEntries(ctx, filter, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbEntries := make([]*pb.JournalEntry, len(entries))
+	for i, e := range entries {
+		pbEntries[i] = &pb.JournalEntry{
+			JournalEntryId:            e.ID.String(),
+			Type:                      toPBJournalEntryType(e.Type),
+			FromAccountId:             e.FromAccountID.String(),
+			ToAccountId:               e.ToAccountID.String(),
+			Amount:                    e.Amount,
+			Description:               e.Description,
+			GroupId:                   optionalJournalGroupIDString(e.GroupID),
+			ReversesJournalEntryId:    optionalJournalEntryIDString(e.ReversesID),
+			ReversedByJournalEntryIds: journalEntryIDStrings(e.ReversedByIDs),
+			ReversedAmount:            e.ReversedAmount,
+			EscrowId:                  optionalEscrowIDString(e.EscrowID),
+			CreatedAt:                 timestamppb.New(e.Timestamp),
+			Metadata:                  e.Metadata,
+		}
+	}
+
+	return &pb.GetJournalEntriesResponse{
+		JournalEntries: pbEntries,
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetAccounts(ctx context.Context, req *pb.GetAccountsRequest) (*pb.GetAccountsResponse, error) {
+	ids := make([]domain.AccountID, 0, len(req.AccountIds))
+	for _, idStr := range req.AccountIds {
+		id, err := h.resolveAccountID(ctx, idStr, "account_id: "+idStr)
+		if err != nil {
+			// Unknown aliases are omitted like unknown IDs.
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	accounts, err := h.accountUC.GetAccounts(ctx, ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pbAccounts := make([]*pb.Account, len(accounts))
+	for i, acc := range accounts {
+		pbAccounts[i] = toPBAccount(acc)
+	}
+
+	return &pb.GetAccountsResponse{
+		Accounts: pbAccounts,
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	// Build filter
+	filter := domain.AccountFilter{}
+	if req.MinBalance != nil {
+		filter.MinBalance = req.MinBalance
+	}
+	if req.MaxBalance != nil {
+		filter.MaxBalance = req.MaxBalance
+	}
+	if req.CanOverdraft != nil {
+		filter.CanOverdraft = req.CanOverdraft
+	}
+	if req.MinCreditLimit != nil {
+		filter.MinCreditLimit = req.MinCreditLimit
+	}
+	if req.MaxCreditLimit != nil {
+		filter.MaxCreditLimit = req.MaxCreditLimit
+	}
+	if req.OwnerId != nil {
+		filter.OwnerID = req.OwnerId
+	}
+	if req.Asset != nil {
+		asset := domain.AssetCode(*req.Asset)
+		filter.Asset = &asset
+	}
+	for _, sel := range req.LabelSelectors {
+		filter.LabelSelectors = append(filter.LabelSelectors, toDomainLabelSelector(sel))
+	}
+
+	// Build sort
+	sort := domain.AccountSort{}
+	switch req.SortField {
 	case pb.SortField_SORT_FIELD_BALANCE:
 		sort.Field = domain.SortByBalance
 	case pb.SortField_SORT_FIELD_ACCOUNT_ID:
@@ -215,16 +1310,12 @@ func (h *CornucopiaHandler) ListAccounts(ctx context.Context, req *pb.ListAccoun
 
 	out, err := h.accountUC.ListAccounts(ctx, input)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
 
 	pbAccounts := make([]*pb.Account, len(out.Accounts))
 	for i, acc := range out.Accounts {
-		pbAccounts[i] = &pb.Account{
-			AccountId:    acc.ID.String(),
-			Balance:      acc.Balance,
-			CanOverdraft: acc.CanOverdraft,
-		}
+		pbAccounts[i] = toPBAccount(acc)
 	}
 
 	return &pb.ListAccountsResponse{
@@ -232,3 +1323,801 @@ func (h *CornucopiaHandler) ListAccounts(ctx context.Context, req *pb.ListAccoun
 		TotalCount: int32(out.TotalCount),
 	}, nil
 }
+
+func (h *CornucopiaHandler) ListAccountsByOwner(ctx context.Context, req *pb.ListAccountsByOwnerRequest) (*pb.ListAccountsByOwnerResponse, error) {
+	out, err := h.accountUC.ListAccountsByOwner(ctx, req.OwnerId, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbAccounts := make([]*pb.Account, len(out.Accounts))
+	for i, acc := range out.Accounts {
+		pbAccounts[i] = toPBAccount(acc)
+	}
+
+	return &pb.ListAccountsByOwnerResponse{
+		Accounts:   pbAccounts,
+		TotalCount: int32(out.TotalCount),
+	}, nil
+}
+
+func (h *CornucopiaHandler) CreateAsset(ctx context.Context, req *pb.CreateAssetRequest) (*pb.CreateAssetResponse, error) {
+	if h.assetUC == nil {
+		return nil, notConfigured("assets")
+	}
+	asset, err := h.assetUC.CreateAsset(ctx, domain.AssetCode(req.Code), req.Name, int(req.Precision))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreateAssetResponse{
+		Asset: toPBAsset(asset),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.GetAssetResponse, error) {
+	if h.assetUC == nil {
+		return nil, notConfigured("assets")
+	}
+	asset, err := h.assetUC.GetAsset(ctx, domain.AssetCode(req.Code))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetAssetResponse{
+		Asset: toPBAsset(asset),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListAssets(ctx context.Context, req *pb.ListAssetsRequest) (*pb.ListAssetsResponse, error) {
+	if h.assetUC == nil {
+		return nil, notConfigured("assets")
+	}
+	assets, err := h.assetUC.ListAssets(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbAssets := make([]*pb.Asset, len(assets))
+	for i, asset := range assets {
+		pbAssets[i] = toPBAsset(asset)
+	}
+
+	return &pb.ListAssetsResponse{
+		Assets: pbAssets,
+	}, nil
+}
+
+func (h *CornucopiaHandler) SetAssetIssuer(ctx context.Context, req *pb.SetAssetIssuerRequest) (*pb.SetAssetIssuerResponse, error) {
+	if h.assetUC == nil {
+		return nil, notConfigured("assets")
+	}
+	issuerID, err := h.resolveAccountID(ctx, req.IssuerAccountId, "issuer_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := h.assetUC.SetAssetIssuer(ctx, domain.AssetCode(req.Code), issuerID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.SetAssetIssuerResponse{
+		Asset: toPBAsset(asset),
+	}, nil
+}
+
+func (h *CornucopiaHandler) SetAssetPointTtl(ctx context.Context, req *pb.SetAssetPointTtlRequest) (*pb.SetAssetPointTtlResponse, error) {
+	if h.assetUC == nil {
+		return nil, notConfigured("assets")
+	}
+	asset, err := h.assetUC.SetAssetPointTTL(ctx, domain.AssetCode(req.Code), int(req.Days))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.SetAssetPointTtlResponse{
+		Asset: toPBAsset(asset),
+	}, nil
+}
+
+func (h *CornucopiaHandler) Mint(ctx context.Context, req *pb.MintRequest) (*pb.MintResponse, error) {
+	if h.issuanceUC == nil {
+		return nil, notConfigured("issuance")
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := h.issuanceUC.Mint(ctx, usecase.MintInput{
+		Asset:          domain.AssetCode(req.Asset),
+		ToAccountID:    toID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.MintResponse{
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) Burn(ctx context.Context, req *pb.BurnRequest) (*pb.BurnResponse, error) {
+	if h.issuanceUC == nil {
+		return nil, notConfigured("issuance")
+	}
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := h.issuanceUC.Burn(ctx, usecase.BurnInput{
+		Asset:          domain.AssetCode(req.Asset),
+		FromAccountID:  fromID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.BurnResponse{
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetSupply(ctx context.Context, req *pb.GetSupplyRequest) (*pb.GetSupplyResponse, error) {
+	if h.issuanceUC == nil {
+		return nil, notConfigured("issuance")
+	}
+	asset, err := h.issuanceUC.GetSupply(ctx, domain.AssetCode(req.Asset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetSupplyResponse{
+		Asset:           string(asset.Code),
+		Supply:          asset.Supply,
+		IssuerAccountId: optionalAccountIDString(asset.IssuerAccountID),
+	}, nil
+}
+
+func (h *CornucopiaHandler) Authorize(ctx context.Context, req *pb.AuthorizeRequest) (*pb.AuthorizeResponse, error) {
+	if h.holdUC == nil {
+		return nil, notConfigured("holds")
+	}
+	accountID, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := h.holdUC.Authorize(ctx, usecase.AuthorizeInput{
+		AccountID:      accountID,
+		ToAccountID:    toID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+		TTL:            time.Duration(req.TtlSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.AuthorizeResponse{
+		Hold: toPBHold(hold),
+	}, nil
+}
+
+func (h *CornucopiaHandler) Capture(ctx context.Context, req *pb.CaptureRequest) (*pb.CaptureResponse, error) {
+	if h.holdUC == nil {
+		return nil, notConfigured("holds")
+	}
+	id, err := uuid.Parse(req.HoldId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid hold_id")
+	}
+
+	out, err := h.holdUC.Capture(ctx, usecase.CaptureInput{
+		HoldID: domain.HoldID(id),
+		Amount: req.Amount,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CaptureResponse{
+		Hold:           toPBHold(out.Hold),
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) Void(ctx context.Context, req *pb.VoidRequest) (*pb.VoidResponse, error) {
+	if h.holdUC == nil {
+		return nil, notConfigured("holds")
+	}
+	id, err := uuid.Parse(req.HoldId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid hold_id")
+	}
+
+	hold, err := h.holdUC.Void(ctx, domain.HoldID(id))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.VoidResponse{
+		Hold: toPBHold(hold),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetHold(ctx context.Context, req *pb.GetHoldRequest) (*pb.GetHoldResponse, error) {
+	if h.holdUC == nil {
+		return nil, notConfigured("holds")
+	}
+	id, err := uuid.Parse(req.HoldId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid hold_id")
+	}
+
+	hold, err := h.holdUC.GetHold(ctx, domain.HoldID(id))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetHoldResponse{
+		Hold: toPBHold(hold),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ScheduleTransfer(ctx context.Context, req *pb.ScheduleTransferRequest) (*pb.ScheduleTransferResponse, error) {
+	if h.scheduleUC == nil {
+		return nil, notConfigured("scheduled transfers")
+	}
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+	if req.ExecuteAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "execute_at is required")
+	}
+
+	st, err := h.scheduleUC.ScheduleTransfer(ctx, usecase.ScheduleTransferInput{
+		FromAccountID:  fromID,
+		ToAccountID:    toID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+		ExecuteAt:      req.ExecuteAt.AsTime(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ScheduleTransferResponse{
+		ScheduledTransfer: toPBScheduledTransfer(st),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	if h.scheduleUC == nil {
+		return nil, notConfigured("scheduled transfers")
+	}
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	sts, err := h.scheduleUC.ListScheduledTransfers(ctx, id, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbScheduled := make([]*pb.ScheduledTransfer, len(sts))
+	for i, st := range sts {
+		pbScheduled[i] = toPBScheduledTransfer(st)
+	}
+	return &pb.ListScheduledTransfersResponse{
+		ScheduledTransfers: pbScheduled,
+	}, nil
+}
+
+func (h *CornucopiaHandler) CancelScheduledTransfer(ctx context.Context, req *pb.CancelScheduledTransferRequest) (*pb.CancelScheduledTransferResponse, error) {
+	if h.scheduleUC == nil {
+		return nil, notConfigured("scheduled transfers")
+	}
+	id, err := uuid.Parse(req.ScheduledTransferId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid scheduled_transfer_id")
+	}
+
+	st, err := h.scheduleUC.CancelScheduledTransfer(ctx, domain.ScheduledTransferID(id))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CancelScheduledTransferResponse{
+		ScheduledTransfer: toPBScheduledTransfer(st),
+	}, nil
+}
+
+func (h *CornucopiaHandler) CreateStandingOrder(ctx context.Context, req *pb.CreateStandingOrderRequest) (*pb.CreateStandingOrderResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	fromID, err := h.resolveAccountID(ctx, req.FromAccountId, "from_account_id")
+	if err != nil {
+		return nil, err
+	}
+	toID, err := h.resolveAccountID(ctx, req.ToAccountId, "to_account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	input := usecase.CreateStandingOrderInput{
+		FromAccountID:  fromID,
+		ToAccountID:    toID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	if req.IntervalSeconds != nil {
+		input.Interval = time.Duration(*req.IntervalSeconds) * time.Second
+	}
+	if req.CronExpression != nil {
+		input.CronExpression = *req.CronExpression
+	}
+	if req.EndAt != nil {
+		endAt := req.EndAt.AsTime()
+		input.EndAt = &endAt
+	}
+
+	order, err := h.standingUC.CreateStandingOrder(ctx, input)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreateStandingOrderResponse{
+		StandingOrder: toPBStandingOrder(order),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetStandingOrder(ctx context.Context, req *pb.GetStandingOrderRequest) (*pb.GetStandingOrderResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := parseStandingOrderID(req.StandingOrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.standingUC.GetStandingOrder(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetStandingOrderResponse{
+		StandingOrder: toPBStandingOrder(order),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListStandingOrders(ctx context.Context, req *pb.ListStandingOrdersRequest) (*pb.ListStandingOrdersResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := h.standingUC.ListStandingOrders(ctx, id, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbOrders := make([]*pb.StandingOrder, len(orders))
+	for i, order := range orders {
+		pbOrders[i] = toPBStandingOrder(order)
+	}
+	return &pb.ListStandingOrdersResponse{
+		StandingOrders: pbOrders,
+	}, nil
+}
+
+func (h *CornucopiaHandler) PauseStandingOrder(ctx context.Context, req *pb.PauseStandingOrderRequest) (*pb.PauseStandingOrderResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := parseStandingOrderID(req.StandingOrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.standingUC.PauseStandingOrder(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.PauseStandingOrderResponse{
+		StandingOrder: toPBStandingOrder(order),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ResumeStandingOrder(ctx context.Context, req *pb.ResumeStandingOrderRequest) (*pb.ResumeStandingOrderResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := parseStandingOrderID(req.StandingOrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.standingUC.ResumeStandingOrder(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ResumeStandingOrderResponse{
+		StandingOrder: toPBStandingOrder(order),
+	}, nil
+}
+
+func (h *CornucopiaHandler) CancelStandingOrder(ctx context.Context, req *pb.CancelStandingOrderRequest) (*pb.CancelStandingOrderResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := parseStandingOrderID(req.StandingOrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.standingUC.CancelStandingOrder(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CancelStandingOrderResponse{
+		StandingOrder: toPBStandingOrder(order),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListStandingOrderRuns(ctx context.Context, req *pb.ListStandingOrderRunsRequest) (*pb.ListStandingOrderRunsResponse, error) {
+	if h.standingUC == nil {
+		return nil, notConfigured("standing orders")
+	}
+	id, err := parseStandingOrderID(req.StandingOrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := h.standingUC.ListStandingOrderRuns(ctx, id, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbRuns := make([]*pb.StandingOrderRun, len(runs))
+	for i, run := range runs {
+		pbRuns[i] = toPBStandingOrderRun(run)
+	}
+	return &pb.ListStandingOrderRunsResponse{
+		Runs: pbRuns,
+	}, nil
+}
+
+func (h *CornucopiaHandler) CreateEscrow(ctx context.Context, req *pb.CreateEscrowRequest) (*pb.CreateEscrowResponse, error) {
+	if h.escrowUC == nil {
+		return nil, notConfigured("escrows")
+	}
+	payerID, err := h.resolveAccountID(ctx, req.PayerAccountId, "payer_account_id")
+	if err != nil {
+		return nil, err
+	}
+	beneficiaryID, err := h.resolveAccountID(ctx, req.BeneficiaryAccountId, "beneficiary_account_id")
+	if err != nil {
+		return nil, err
+	}
+	if req.Deadline == nil {
+		return nil, status.Error(codes.InvalidArgument, "deadline is required")
+	}
+
+	out, err := h.escrowUC.CreateEscrow(ctx, usecase.CreateEscrowInput{
+		PayerAccountID:       payerID,
+		BeneficiaryAccountID: beneficiaryID,
+		Amount:               req.Amount,
+		Description:          req.Description,
+		Deadline:             req.Deadline.AsTime(),
+		IdempotencyKey:       req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreateEscrowResponse{
+		Escrow:         toPBEscrow(out.Escrow),
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ReleaseEscrow(ctx context.Context, req *pb.ReleaseEscrowRequest) (*pb.ReleaseEscrowResponse, error) {
+	if h.escrowUC == nil {
+		return nil, notConfigured("escrows")
+	}
+	id, err := parseEscrowID(req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := h.escrowUC.ReleaseEscrow(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ReleaseEscrowResponse{
+		Escrow:         toPBEscrow(out.Escrow),
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) RefundEscrow(ctx context.Context, req *pb.RefundEscrowRequest) (*pb.RefundEscrowResponse, error) {
+	if h.escrowUC == nil {
+		return nil, notConfigured("escrows")
+	}
+	id, err := parseEscrowID(req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := h.escrowUC.RefundEscrow(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.RefundEscrowResponse{
+		Escrow:         toPBEscrow(out.Escrow),
+		JournalEntryId: out.JournalEntryID.String(),
+		CreatedAt:      timestamppb.New(out.CreatedAt),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetEscrow(ctx context.Context, req *pb.GetEscrowRequest) (*pb.GetEscrowResponse, error) {
+	if h.escrowUC == nil {
+		return nil, notConfigured("escrows")
+	}
+	id, err := parseEscrowID(req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	escrow, err := h.escrowUC.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetEscrowResponse{
+		Escrow: toPBEscrow(escrow),
+	}, nil
+}
+
+func (h *CornucopiaHandler) CreatePaymentRequest(ctx context.Context, req *pb.CreatePaymentRequestRequest) (*pb.CreatePaymentRequestResponse, error) {
+	if h.paymentUC == nil {
+		return nil, notConfigured("payment requests")
+	}
+	payerID, err := h.resolveAccountID(ctx, req.PayerAccountId, "payer_account_id")
+	if err != nil {
+		return nil, err
+	}
+	payeeID, err := h.resolveAccountID(ctx, req.PayeeAccountId, "payee_account_id")
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "expires_at is required")
+	}
+
+	pr, err := h.paymentUC.CreatePaymentRequest(ctx, usecase.CreatePaymentRequestInput{
+		PayerAccountID: payerID,
+		PayeeAccountID: payeeID,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		ExpiresAt:      req.ExpiresAt.AsTime(),
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreatePaymentRequestResponse{
+		PaymentRequest: toPBPaymentRequest(pr),
+	}, nil
+}
+
+func (h *CornucopiaHandler) GetPaymentRequest(ctx context.Context, req *pb.GetPaymentRequestRequest) (*pb.GetPaymentRequestResponse, error) {
+	if h.paymentUC == nil {
+		return nil, notConfigured("payment requests")
+	}
+	id, err := parsePaymentRequestID(req.PaymentRequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := h.paymentUC.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetPaymentRequestResponse{
+		PaymentRequest: toPBPaymentRequest(pr),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListPaymentRequests(ctx context.Context, req *pb.ListPaymentRequestsRequest) (*pb.ListPaymentRequestsResponse, error) {
+	if h.paymentUC == nil {
+		return nil, notConfigured("payment requests")
+	}
+	id, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+	prStatus, err := toDomainPaymentRequestStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := h.paymentUC.ListPaymentRequests(ctx, id, prStatus, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbRequests := make([]*pb.PaymentRequest, len(prs))
+	for i, pr := range prs {
+		pbRequests[i] = toPBPaymentRequest(pr)
+	}
+	return &pb.ListPaymentRequestsResponse{
+		PaymentRequests: pbRequests,
+	}, nil
+}
+
+func (h *CornucopiaHandler) AcceptPaymentRequest(ctx context.Context, req *pb.AcceptPaymentRequestRequest) (*pb.AcceptPaymentRequestResponse, error) {
+	if h.paymentUC == nil {
+		return nil, notConfigured("payment requests")
+	}
+	id, err := parsePaymentRequestID(req.PaymentRequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := h.paymentUC.AcceptPaymentRequest(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.AcceptPaymentRequestResponse{
+		PaymentRequest: toPBPaymentRequest(pr),
+	}, nil
+}
+
+func (h *CornucopiaHandler) DeclinePaymentRequest(ctx context.Context, req *pb.DeclinePaymentRequestRequest) (*pb.DeclinePaymentRequestResponse, error) {
+	if h.paymentUC == nil {
+		return nil, notConfigured("payment requests")
+	}
+	id, err := parsePaymentRequestID(req.PaymentRequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := h.paymentUC.DeclinePaymentRequest(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.DeclinePaymentRequestResponse{
+		PaymentRequest: toPBPaymentRequest(pr),
+	}, nil
+}
+
+func (h *CornucopiaHandler) CreateFeeRule(ctx context.Context, req *pb.CreateFeeRuleRequest) (*pb.CreateFeeRuleResponse, error) {
+	if h.feeUC == nil {
+		return nil, notConfigured("fee rules")
+	}
+	collectorID, err := h.resolveAccountID(ctx, req.CollectorAccountId, "collector_account_id")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.CreateFeeRuleInput{
+		Name:               req.Name,
+		Asset:              domain.AssetCode(req.AssetCode),
+		FlatFee:            req.FlatFee,
+		RateBasisPoints:    req.RateBasisPoints,
+		MinFee:             req.MinFee,
+		MaxFee:             req.MaxFee,
+		CollectorAccountID: collectorID,
+		Priority:           int(req.Priority),
+	}
+	if req.SourceSelector != nil {
+		sel := toDomainLabelSelector(req.SourceSelector)
+		input.SourceSelector = &sel
+	}
+	if req.DestinationAccountId != nil {
+		destinationID, err := h.resolveAccountID(ctx, *req.DestinationAccountId, "destination_account_id")
+		if err != nil {
+			return nil, err
+		}
+		input.DestinationAccountID = &destinationID
+	}
+
+	rule, err := h.feeUC.CreateFeeRule(ctx, input)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CreateFeeRuleResponse{
+		FeeRule: toPBFeeRule(rule),
+	}, nil
+}
+
+func (h *CornucopiaHandler) ListFeeRules(ctx context.Context, req *pb.ListFeeRulesRequest) (*pb.ListFeeRulesResponse, error) {
+	if h.feeUC == nil {
+		return nil, notConfigured("fee rules")
+	}
+	rules, err := h.feeUC.ListFeeRules(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pbRules := make([]*pb.FeeRule, len(rules))
+	for i, rule := range rules {
+		pbRules[i] = toPBFeeRule(rule)
+	}
+	return &pb.ListFeeRulesResponse{
+		FeeRules: pbRules,
+	}, nil
+}
+
+func (h *CornucopiaHandler) DeleteFeeRule(ctx context.Context, req *pb.DeleteFeeRuleRequest) (*pb.DeleteFeeRuleResponse, error) {
+	if h.feeUC == nil {
+		return nil, notConfigured("fee rules")
+	}
+	id, err := parseFeeRuleID(req.FeeRuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.feeUC.DeleteFeeRule(ctx, id); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.DeleteFeeRuleResponse{}, nil
+}
+
+func (h *CornucopiaHandler) GetVelocityLimits(ctx context.Context, req *pb.GetVelocityLimitsRequest) (*pb.GetVelocityLimitsResponse, error) {
+	accountID, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := h.transferUC.GetVelocityLimits(ctx, accountID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GetVelocityLimitsResponse{
+		AccountId:       accountID.String(),
+		Limits:          toPBVelocityLimits(limits.Own),
+		EffectiveLimits: toPBVelocityLimits(limits.Effective),
+	}, nil
+}
+
+func (h *CornucopiaHandler) SetVelocityLimits(ctx context.Context, req *pb.SetVelocityLimitsRequest) (*pb.SetVelocityLimitsResponse, error) {
+	accountID, err := h.resolveAccountID(ctx, req.AccountId, "account_id")
+	if err != nil {
+		return nil, err
+	}
+	var limits domain.VelocityLimits
+	if req.Limits != nil {
+		limits = domain.VelocityLimits{
+			MaxPerTransfer:      req.Limits.MaxPerTransfer,
+			MaxOutgoingPerDay:   req.Limits.MaxOutgoingPerDay,
+			MaxTransfersPerHour: req.Limits.MaxTransfersPerHour,
+		}
+	}
+
+	out, err := h.transferUC.SetVelocityLimits(ctx, accountID, limits)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.SetVelocityLimitsResponse{
+		AccountId:       accountID.String(),
+		Limits:          toPBVelocityLimits(out.Own),
+		EffectiveLimits: toPBVelocityLimits(out.Effective),
+	}, nil
+}
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/google/uuid"
@@ -38,6 +39,32 @@ func (m *mockAccountRepo) GetAccountForUpdate(ctx context.Context, id domain.Acc
 	return m.FindAccountByID(ctx, id)
 }
 
+func (m *mockAccountRepo) FindAccountByAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	for _, acc := range m.accounts {
+		if acc.Alias == alias {
+			return acc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) FindAccountsByParentIDs(ctx context.Context, parentIDs []domain.AccountID) ([]*domain.Account, error) {
+	var result []*domain.Account
+	for _, acc := range m.accounts {
+		if acc.ParentID != nil && slices.Contains(parentIDs, *acc.ParentID) {
+			result = append(result, acc)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAccountRepo) SaveAccountLabels(ctx context.Context, id domain.AccountID, labels map[string]string) error {
+	if acc, ok := m.accounts[id]; ok {
+		acc.Labels = labels
+	}
+	return nil
+}
+
 func (m *mockAccountRepo) ListAccounts(ctx context.Context, filter domain.AccountFilter, sort domain.AccountSort, limit, offset int) ([]*domain.Account, int, error) {
 	var result []*domain.Account
 	for _, acc := range m.accounts {
@@ -47,7 +74,19 @@ func (m *mockAccountRepo) ListAccounts(ctx context.Context, filter domain.Accoun
 		if filter.MaxBalance != nil && acc.Balance > *filter.MaxBalance {
 			continue
 		}
-		if filter.CanOverdraft != nil && acc.CanOverdraft != *filter.CanOverdraft {
+		if filter.CanOverdraft != nil && acc.CanOverdraft() != *filter.CanOverdraft {
+			continue
+		}
+		if filter.OwnerID != nil && acc.OwnerID != *filter.OwnerID {
+			continue
+		}
+		matched := true
+		for _, sel := range filter.LabelSelectors {
+			if !sel.Matches(acc.Labels) {
+				matched = false
+			}
+		}
+		if !matched {
 			continue
 		}
 		result = append(result, acc)
@@ -74,6 +113,40 @@ func (m *mockAccountRepo) FindAccountsByIDs(ctx context.Context, ids []domain.Ac
 	return result, nil
 }
 
+type mockAccountChangeRepo struct {
+	changes []*domain.AccountChange
+}
+
+func (m *mockAccountChangeRepo) SaveAccountChange(ctx context.Context, change *domain.AccountChange) error {
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+type mockAssetRepo struct{}
+
+func (m *mockAssetRepo) CreateAsset(ctx context.Context, asset *domain.Asset) error {
+	return nil
+}
+
+func (m *mockAssetRepo) SaveAsset(ctx context.Context, asset *domain.Asset) error {
+	return nil
+}
+
+func (m *mockAssetRepo) FindAssetByCode(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	if code == domain.DefaultAssetCode {
+		return &domain.Asset{Code: code, Name: "Point"}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockAssetRepo) GetAssetForUpdate(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	return m.FindAssetByCode(ctx, code)
+}
+
+func (m *mockAssetRepo) ListAssets(ctx context.Context) ([]*domain.Asset, error) {
+	return nil, nil
+}
+
 type mockJournalEntryRepo struct {
 	entries []*domain.JournalEntry
 }
@@ -102,6 +175,24 @@ func (m *mockJournalEntryRepo) FindByAccountID(ctx context.Context, accountID do
 	return res, nil
 }
 
+func (m *mockJournalEntryRepo) FindJournalEntries(ctx context.Context, filter domain.JournalEntryFilter, limit, offset int) ([]*domain.JournalEntry, error) {
+	var res []*domain.JournalEntry
+	for _, e := range m.entries {
+		if filter.Matches(e) {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockJournalEntryRepo) SaveJournalGroup(ctx context.Context, group *domain.JournalGroup) error {
+	return nil
+}
+
+func (m *mockJournalEntryRepo) FindJournalGroupByIdempotencyKey(ctx context.Context, key string) (*domain.JournalGroup, error) {
+	return nil, nil
+}
+
 type mockTxManager struct{}
 
 func (m *mockTxManager) Run(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -117,7 +208,7 @@ func (m *mockTxManager) RunSerialized(ctx context.Context, name string, fn func(
 func TestCornucopiaHandler_CreateAccount(t *testing.T) {
 	repo := &mockAccountRepo{accounts: make(map[domain.AccountID]*domain.Account)}
 	tm := &mockTxManager{}
-	uc := usecase.NewAccountUseCase(repo, tm)
+	uc := usecase.NewAccountUseCase(repo, &mockAccountChangeRepo{}, &mockAssetRepo{}, tm)
 	h := NewCornucopiaHandler(nil, uc)
 
 	req := &pb.CreateAccountRequest{CanOverdraft: false}
@@ -131,6 +222,96 @@ func TestCornucopiaHandler_CreateAccount(t *testing.T) {
 	}
 }
 
+func TestCornucopiaHandler_UpdateAccount_NegativeBalance(t *testing.T) {
+	repo := &mockAccountRepo{accounts: make(map[domain.AccountID]*domain.Account)}
+	tm := &mockTxManager{}
+	uc := usecase.NewAccountUseCase(repo, &mockAccountChangeRepo{}, &mockAssetRepo{}, tm)
+	h := NewCornucopiaHandler(nil, uc)
+
+	id := domain.AccountID(mustUUID("acc-1"))
+	repo.SaveAccount(context.Background(), &domain.Account{ID: id, Balance: -10, CreditLimit: domain.UnlimitedCreditLimit})
+
+	canOverdraft := false
+	req := &pb.UpdateAccountRequest{
+		AccountId:    id.String(),
+		CanOverdraft: &canOverdraft,
+		ChangedBy:    "admin",
+	}
+
+	_, err := h.UpdateAccount(context.Background(), req)
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("expected gRPC status error")
+	}
+	if st.Code() != codes.FailedPrecondition {
+		t.Errorf("expected code FailedPrecondition, got %v", st.Code())
+	}
+}
+
+func TestCornucopiaHandler_GetAccount_Alias(t *testing.T) {
+	repo := &mockAccountRepo{accounts: make(map[domain.AccountID]*domain.Account)}
+	uc := usecase.NewAccountUseCase(repo, &mockAccountChangeRepo{}, &mockAssetRepo{}, &mockTxManager{})
+	h := NewCornucopiaHandler(nil, uc)
+	ctx := context.Background()
+
+	created, err := h.CreateAccount(ctx, &pb.CreateAccountRequest{Alias: "@event-2026-pool"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := h.GetAccount(ctx, &pb.GetAccountRequest{AccountId: "@event-2026-pool"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.AccountId != created.AccountId {
+		t.Errorf("expected account %s, got %s", created.AccountId, resp.AccountId)
+	}
+
+	_, err = h.GetAccount(ctx, &pb.GetAccountRequest{AccountId: "@unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code NotFound, got %v", status.Code(err))
+	}
+	_, err = h.GetAccount(ctx, &pb.GetAccountRequest{AccountId: "@Not Valid"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code InvalidArgument, got %v", status.Code(err))
+	}
+
+	_, err = h.CreateAccount(ctx, &pb.CreateAccountRequest{Alias: "event-2026-pool"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected code AlreadyExists, got %v", status.Code(err))
+	}
+}
+
+func TestCornucopiaHandler_NotConfigured(t *testing.T) {
+	h := NewCornucopiaHandler(nil, nil)
+	ctx := context.Background()
+
+	rpcs := map[string]func() error{
+		"CreateAsset": func() error { _, err := h.CreateAsset(ctx, &pb.CreateAssetRequest{}); return err },
+		"Mint":        func() error { _, err := h.Mint(ctx, &pb.MintRequest{}); return err },
+		"Authorize":   func() error { _, err := h.Authorize(ctx, &pb.AuthorizeRequest{}); return err },
+		"ScheduleTransfer": func() error {
+			_, err := h.ScheduleTransfer(ctx, &pb.ScheduleTransferRequest{})
+			return err
+		},
+		"CreateStandingOrder": func() error {
+			_, err := h.CreateStandingOrder(ctx, &pb.CreateStandingOrderRequest{})
+			return err
+		},
+		"CreateEscrow":  func() error { _, err := h.CreateEscrow(ctx, &pb.CreateEscrowRequest{}); return err },
+		"CreateFeeRule": func() error { _, err := h.CreateFeeRule(ctx, &pb.CreateFeeRuleRequest{}); return err },
+		"CreatePaymentRequest": func() error {
+			_, err := h.CreatePaymentRequest(ctx, &pb.CreatePaymentRequestRequest{})
+			return err
+		},
+	}
+	for name, call := range rpcs {
+		if code := status.Code(call()); code != codes.Unimplemented {
+			t.Errorf("%s: expected code Unimplemented, got %v", name, code)
+		}
+	}
+}
+
 func TestCornucopiaHandler_Transfer(t *testing.T) {
 	accRepo := &mockAccountRepo{accounts: make(map[domain.AccountID]*domain.Account)}
 	txRepo := &mockJournalEntryRepo{}
@@ -176,7 +357,7 @@ func TestCornucopiaHandler_Transfer_InsufficientBalance(t *testing.T) {
 	id1 := domain.AccountID(mustUUID("acc-1"))
 	id2 := domain.AccountID(mustUUID("acc-2"))
 
-	accRepo.SaveAccount(context.Background(), &domain.Account{ID: id1, Balance: 0, CanOverdraft: false})
+	accRepo.SaveAccount(context.Background(), &domain.Account{ID: id1, Balance: 0, CreditLimit: 0})
 	accRepo.SaveAccount(context.Background(), &domain.Account{ID: id2, Balance: 0})
 
 	req := &pb.TransferRequest{
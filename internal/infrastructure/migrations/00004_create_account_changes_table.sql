@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS account_changes (
+    -- UUIDv7: time-ordered, can ORDER BY id for chronological history
+    id BINARY(16) PRIMARY KEY,
+    account_id BINARY(16) NOT NULL,
+    field VARCHAR(64) NOT NULL,
+    old_value VARCHAR(255) NOT NULL DEFAULT '',
+    new_value VARCHAR(255) NOT NULL DEFAULT '',
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_id (account_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_changes;
+-- +goose StatementEnd
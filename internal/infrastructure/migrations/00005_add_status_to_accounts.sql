@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active' AFTER can_overdraft;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts ADD COLUMN credit_limit BIGINT NOT NULL DEFAULT 0 AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- 9223372036854775807 (max BIGINT) means unlimited overdraft
+UPDATE accounts SET credit_limit = 9223372036854775807 WHERE can_overdraft = TRUE;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN can_overdraft;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts ADD COLUMN can_overdraft BOOLEAN NOT NULL DEFAULT FALSE AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE accounts SET can_overdraft = TRUE WHERE credit_limit > 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN credit_limit;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL means no cap besides BIGINT overflow
+ALTER TABLE accounts ADD COLUMN max_balance BIGINT NULL AFTER credit_limit;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN max_balance;
+-- +goose StatementEnd
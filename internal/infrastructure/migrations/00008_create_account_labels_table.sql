@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS account_labels (
+    account_id BINARY(16) NOT NULL,
+    label_key VARCHAR(63) NOT NULL,
+    label_value VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (account_id, label_key),
+    -- Serves label selectors in ListAccounts
+    INDEX idx_label_key_value (label_key, label_value)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Label changes are recorded as "label:<key>"
+ALTER TABLE account_changes MODIFY COLUMN field VARCHAR(128) NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE account_changes MODIFY COLUMN field VARCHAR(64) NOT NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_labels;
+-- +goose StatementEnd
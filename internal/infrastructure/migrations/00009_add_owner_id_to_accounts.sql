@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- External owner reference (e.g. traQ user ID). Not unique: an owner may have many accounts.
+ALTER TABLE accounts ADD COLUMN owner_id VARCHAR(255) NULL AFTER id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts ADD INDEX idx_owner_id (owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP INDEX idx_owner_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN owner_id;
+-- +goose StatementEnd
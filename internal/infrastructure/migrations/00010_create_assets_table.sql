@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS assets (
+    code VARCHAR(32) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    -- Number of decimal places clients use to display amounts
+    display_precision INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Existing accounts all hold the implicit default point type
+INSERT INTO assets (code, name, display_precision) VALUES ('point', 'Point', 0);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts ADD COLUMN asset_code VARCHAR(32) NOT NULL DEFAULT 'point' AFTER id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts ADD INDEX idx_asset_code (asset_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP INDEX idx_asset_code;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN asset_code;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS assets;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE assets
+    ADD COLUMN issuer_account_id BINARY(16) NULL AFTER display_precision,
+    -- Sum of minted minus burned amounts
+    ADD COLUMN circulating_supply BIGINT NOT NULL DEFAULT 0 AFTER issuer_account_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN entry_type VARCHAR(16) NOT NULL DEFAULT 'transfer' AFTER id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Set on issuer accounts, which only issuance entries may move
+ALTER TABLE accounts ADD COLUMN is_system BOOLEAN NOT NULL DEFAULT FALSE AFTER status;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN is_system;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN entry_type;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE assets
+    DROP COLUMN circulating_supply,
+    DROP COLUMN issuer_account_id;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL for top-level accounts
+ALTER TABLE accounts ADD COLUMN parent_id BINARY(16) NULL AFTER owner_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts ADD INDEX idx_parent_id (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP INDEX idx_parent_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN parent_id;
+-- +goose StatementEnd
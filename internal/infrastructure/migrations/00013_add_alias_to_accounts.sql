@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Unique human-readable name. NULL for accounts without an alias.
+ALTER TABLE accounts ADD COLUMN alias VARCHAR(64) NULL AFTER owner_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts ADD UNIQUE INDEX uq_alias (alias);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP INDEX uq_alias;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN alias;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Groups journal entries that were posted atomically, e.g. the legs of a batch transfer
+CREATE TABLE IF NOT EXISTS journal_groups (
+    id BINARY(16) PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN group_id BINARY(16) NULL AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions ADD INDEX idx_group_id (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP INDEX idx_group_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN group_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_groups;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The entry a reversal undoes. An entry may have several partial reversals.
+ALTER TABLE transactions ADD COLUMN reverses_id BINARY(16) NULL AFTER group_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions ADD INDEX idx_reverses_id (reverses_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP INDEX idx_reverses_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN reverses_id;
+-- +goose StatementEnd
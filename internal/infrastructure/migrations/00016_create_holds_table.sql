@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sum of authorized holds. Held points stay in balance but cannot be spent.
+ALTER TABLE accounts ADD COLUMN held_balance BIGINT NOT NULL DEFAULT 0 AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS holds (
+    id BINARY(16) PRIMARY KEY,
+    account_id BINARY(16) NOT NULL,
+    to_account_id BINARY(16) NOT NULL,
+    amount BIGINT NOT NULL,
+    captured_amount BIGINT NOT NULL DEFAULT 0,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    -- authorized, captured, voided or expired
+    status VARCHAR(16) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_id (account_id),
+    INDEX idx_status_expires_at (status, expires_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS holds;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN held_balance;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id BINARY(16) PRIMARY KEY,
+    from_account_id BINARY(16) NOT NULL,
+    to_account_id BINARY(16) NOT NULL,
+    amount BIGINT NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    execute_at DATETIME NOT NULL,
+    -- pending, executed, failed or canceled
+    status VARCHAR(16) NOT NULL,
+    journal_entry_id BINARY(16) NULL,
+    failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_from_account_id (from_account_id),
+    INDEX idx_to_account_id (to_account_id),
+    INDEX idx_status_execute_at (status, execute_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_transfers;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id BINARY(16) PRIMARY KEY,
+    from_account_id BINARY(16) NOT NULL,
+    to_account_id BINARY(16) NOT NULL,
+    amount BIGINT NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    -- Exactly one of interval_seconds and cron_expression is set
+    interval_seconds BIGINT NULL,
+    cron_expression VARCHAR(100) NULL,
+    end_at DATETIME NULL,
+    next_run_at DATETIME NOT NULL,
+    -- active, paused, canceled or completed
+    status VARCHAR(16) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_from_account_id (from_account_id),
+    INDEX idx_to_account_id (to_account_id),
+    INDEX idx_status_next_run_at (status, next_run_at)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS standing_order_runs (
+    id BINARY(16) PRIMARY KEY,
+    standing_order_id BINARY(16) NOT NULL,
+    scheduled_at DATETIME NOT NULL,
+    -- succeeded or failed
+    status VARCHAR(16) NOT NULL,
+    journal_entry_id BINARY(16) NULL,
+    failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_standing_order_scheduled_at (standing_order_id, scheduled_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS standing_order_runs;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS standing_orders;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE assets
+    -- System account holding escrowed points, created on first use
+    ADD COLUMN escrow_account_id BINARY(16) NULL AFTER issuer_account_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN escrow_id BINARY(16) NULL AFTER reverses_id,
+    ADD INDEX idx_escrow_id (escrow_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS escrows (
+    id BINARY(16) PRIMARY KEY,
+    payer_account_id BINARY(16) NOT NULL,
+    beneficiary_account_id BINARY(16) NOT NULL,
+    escrow_account_id BINARY(16) NOT NULL,
+    amount BIGINT NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    -- held, released or refunded
+    status VARCHAR(16) NOT NULL,
+    deadline DATETIME NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_payer_account_id (payer_account_id),
+    INDEX idx_beneficiary_account_id (beneficiary_account_id),
+    INDEX idx_status_deadline (status, deadline)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS escrows;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions
+    DROP INDEX idx_escrow_id,
+    DROP COLUMN escrow_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE assets DROP COLUMN escrow_account_id;
+-- +goose StatementEnd
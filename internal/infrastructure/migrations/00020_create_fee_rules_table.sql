@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id BINARY(16) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    -- Empty matches every asset
+    asset_code VARCHAR(32) NOT NULL DEFAULT '',
+    -- Label selector on the sending account as JSON, NULL matches every sender
+    source_selector TEXT NULL,
+    destination_account_id BINARY(16) NULL,
+    flat_fee BIGINT NOT NULL DEFAULT 0,
+    rate_basis_points BIGINT NOT NULL DEFAULT 0,
+    min_fee BIGINT NOT NULL DEFAULT 0,
+    max_fee BIGINT NULL,
+    collector_account_id BINARY(16) NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fee_rules;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS velocity_limits (
+    account_id BINARY(16) PRIMARY KEY,
+    -- NULL falls back to the server default
+    max_per_transfer BIGINT NULL,
+    max_outgoing_per_day BIGINT NULL,
+    max_transfers_per_hour BIGINT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Velocity checks aggregate the recent transfers sent by an account
+ALTER TABLE transactions ADD INDEX idx_from_account_type_created_at (from_account_id, entry_type, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP INDEX idx_from_account_type_created_at;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS velocity_limits;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE assets
+    -- Days credited points live before they expire, 0 for never
+    ADD COLUMN point_ttl_days INT NOT NULL DEFAULT 0 AFTER circulating_supply;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS point_lots (
+    id BINARY(16) PRIMARY KEY,
+    account_id BINARY(16) NOT NULL,
+    asset_code VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    remaining BIGINT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    journal_entry_id BINARY(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_expires_at (account_id, expires_at),
+    INDEX idx_expires_at (expires_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS point_lots;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE assets DROP COLUMN point_ttl_days;
+-- +goose StatementEnd
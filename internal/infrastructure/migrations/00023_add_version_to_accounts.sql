@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Incremented by every save, for optimistic preconditions
+ALTER TABLE accounts ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER parent_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id BINARY(16) PRIMARY KEY,
+    payer_account_id BINARY(16) NOT NULL,
+    payee_account_id BINARY(16) NOT NULL,
+    amount BIGINT NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    -- pending, accepted, declined or expired
+    status VARCHAR(16) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    journal_entry_id BINARY(16) NULL,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_payer_account_id_updated_at (payer_account_id, updated_at),
+    INDEX idx_payee_account_id_updated_at (payee_account_id, updated_at),
+    INDEX idx_status_expires_at (status, expires_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_requests;
+-- +goose StatementEnd
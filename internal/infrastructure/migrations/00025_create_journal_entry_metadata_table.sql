@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS journal_entry_metadata (
+    journal_entry_id BINARY(16) NOT NULL,
+    metadata_key VARCHAR(63) NOT NULL,
+    metadata_value VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (journal_entry_id, metadata_key),
+    -- Serves metadata filters in GetJournalEntries
+    INDEX idx_metadata_key_value (metadata_key, metadata_value)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_entry_metadata;
+-- +goose StatementEnd
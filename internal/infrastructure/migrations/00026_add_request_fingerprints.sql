@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Fingerprint of the request that used the idempotency key; empty for rows written before it was recorded
+ALTER TABLE transactions ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE journal_groups ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE holds ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE scheduled_transfers ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standing_orders ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE payment_requests ADD COLUMN request_fingerprint VARCHAR(64) NOT NULL DEFAULT '' AFTER idempotency_key;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payment_requests DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standing_orders DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE scheduled_transfers DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE holds DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE journal_groups DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE transactions DROP COLUMN request_fingerprint;
+-- +goose StatementEnd
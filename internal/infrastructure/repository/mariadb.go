@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
 )
@@ -84,36 +87,106 @@ func (r *MariaDBRepository) getExecutor(ctx context.Context) interface {
 	return r.db
 }
 
+// isDuplicateKeyError reports whether err is a unique key violation (ER_DUP_ENTRY).
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
 // -- AccountRepository --
 
-func (r *MariaDBRepository) SaveAccount(ctx context.Context, account *domain.Account) error {
-	query := `
-		INSERT INTO accounts (id, balance, can_overdraft) 
-		VALUES (?, ?, ?)
-		ON DUPLICATE KEY UPDATE balance = VALUES(balance), can_overdraft = VALUES(can_overdraft)
-	`
-	idBytes := uuid.UUID(account.ID)
-	_, err := r.getExecutor(ctx).ExecContext(ctx, query, idBytes[:], account.Balance, account.CanOverdraft)
-	return err
-}
+// accountColumns lists the accounts columns in the order scanAccount expects.
+const accountColumns = "id, asset_code, balance, held_balance, credit_limit, max_balance, status, owner_id, alias, parent_id, version, is_system"
 
-func (r *MariaDBRepository) FindAccountByID(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
-	query := "SELECT id, balance, can_overdraft FROM accounts WHERE id = ?"
-	idBytes := uuid.UUID(id)
-	row := r.getExecutor(ctx).QueryRowContext(ctx, query, idBytes[:])
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
+func scanAccount(row rowScanner) (*domain.Account, error) {
 	var idRaw uuid.UUID
+	var maxBalance sql.NullInt64
+	var ownerID, alias sql.NullString
+	var parentRaw []byte
 	var acc domain.Account
-	if err := row.Scan(&idRaw, &acc.Balance, &acc.CanOverdraft); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Not found
-		}
+	if err := row.Scan(&idRaw, &acc.Asset, &acc.Balance, &acc.HeldBalance, &acc.CreditLimit, &maxBalance, &acc.Status, &ownerID, &alias, &parentRaw, &acc.Version, &acc.System); err != nil {
 		return nil, err
 	}
+	acc.OwnerID = ownerID.String
+	acc.Alias = alias.String
 	acc.ID = domain.AccountID(idRaw)
+	if maxBalance.Valid {
+		acc.MaxBalance = &maxBalance.Int64
+	}
+	parentID, err := scanNullAccountID(parentRaw)
+	if err != nil {
+		return nil, err
+	}
+	acc.ParentID = parentID
 	return &acc, nil
 }
 
+// nullAccountID converts an optional account ID for a nullable BINARY(16) column.
+func nullAccountID(id *domain.AccountID) any {
+	if id == nil {
+		return nil
+	}
+	idBytes := uuid.UUID(*id)
+	return idBytes[:]
+}
+
+// scanNullAccountID converts a scanned nullable BINARY(16) column back into an optional account ID.
+func scanNullAccountID(raw []byte) (*domain.AccountID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	accountID := domain.AccountID(id)
+	return &accountID, nil
+}
+
+func (r *MariaDBRepository) SaveAccount(ctx context.Context, account *domain.Account) error {
+	query := `
+		INSERT INTO accounts (id, asset_code, balance, held_balance, credit_limit, max_balance, status, owner_id, alias, parent_id, version, is_system) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?)
+		ON DUPLICATE KEY UPDATE balance = VALUES(balance), held_balance = VALUES(held_balance), credit_limit = VALUES(credit_limit),
+			max_balance = VALUES(max_balance), status = VALUES(status), owner_id = VALUES(owner_id),
+			alias = VALUES(alias), is_system = VALUES(is_system), version = version + 1
+	`
+	idBytes := uuid.UUID(account.ID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		account.Asset,
+		account.Balance,
+		account.HeldBalance,
+		account.CreditLimit,
+		account.MaxBalance,
+		account.Status,
+		sql.NullString{String: account.OwnerID, Valid: account.OwnerID != ""},
+		sql.NullString{String: account.Alias, Valid: account.Alias != ""},
+		nullAccountID(account.ParentID),
+		account.System,
+	)
+	if isDuplicateKeyError(err) {
+		// The primary key is handled by the upsert, so only the alias can collide.
+		return domain.ErrAliasTaken
+	}
+	if err != nil {
+		return err
+	}
+	account.Version++
+	return nil
+}
+
+func (r *MariaDBRepository) FindAccountByID(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryAccount(ctx, query, idBytes[:])
+}
+
 func (r *MariaDBRepository) FindAccountsByIDs(ctx context.Context, ids []domain.AccountID) ([]*domain.Account, error) {
 	if len(ids) == 0 {
 		return nil, nil
@@ -129,10 +202,63 @@ func (r *MariaDBRepository) FindAccountsByIDs(ctx context.Context, ids []domain.
 	}
 
 	query := fmt.Sprintf(
-		"SELECT id, balance, can_overdraft FROM accounts WHERE id IN (%s)",
-		strings.Join(placeholders, ","),
+		"SELECT %s FROM accounts WHERE id IN (%s)",
+		accountColumns, strings.Join(placeholders, ","),
+	)
+	return r.queryAccounts(ctx, query, args...)
+}
+
+func (r *MariaDBRepository) FindAccountByAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE alias = ?"
+	return r.queryAccount(ctx, query, alias)
+}
+
+func (r *MariaDBRepository) FindAccountsByParentIDs(ctx context.Context, parentIDs []domain.AccountID) ([]*domain.Account, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(parentIDs))
+	args := make([]any, len(parentIDs))
+	for i, id := range parentIDs {
+		placeholders[i] = "?"
+		idBytes := uuid.UUID(id)
+		args[i] = idBytes[:]
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM accounts WHERE parent_id IN (%s) ORDER BY id",
+		accountColumns, strings.Join(placeholders, ","),
 	)
+	return r.queryAccounts(ctx, query, args...)
+}
+
+func (r *MariaDBRepository) GetAccountForUpdate(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryAccount(ctx, query, idBytes[:])
+}
+
+// queryAccount runs a query selecting accountColumns and returns the single account with its labels,
+// or nil if no row matched.
+func (r *MariaDBRepository) queryAccount(ctx context.Context, query string, args ...any) (*domain.Account, error) {
+	row := r.getExecutor(ctx).QueryRowContext(ctx, query, args...)
+
+	acc, err := scanAccount(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	if err := r.loadAccountLabels(ctx, []*domain.Account{acc}); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
 
+// queryAccounts runs a query selecting accountColumns and returns the accounts with their labels.
+func (r *MariaDBRepository) queryAccounts(ctx context.Context, query string, args ...any) ([]*domain.Account, error) {
 	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -141,36 +267,81 @@ func (r *MariaDBRepository) FindAccountsByIDs(ctx context.Context, ids []domain.
 
 	var accounts []*domain.Account
 	for rows.Next() {
-		var idRaw uuid.UUID
-		var acc domain.Account
-		if err := rows.Scan(&idRaw, &acc.Balance, &acc.CanOverdraft); err != nil {
+		acc, err := scanAccount(rows)
+		if err != nil {
 			return nil, err
 		}
-		acc.ID = domain.AccountID(idRaw)
-		accounts = append(accounts, &acc)
+		accounts = append(accounts, acc)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := r.loadAccountLabels(ctx, accounts); err != nil {
+		return nil, err
+	}
 	return accounts, nil
 }
 
+// loadAccountLabels fills in the labels of the given accounts with a single query.
+func (r *MariaDBRepository) loadAccountLabels(ctx context.Context, accounts []*domain.Account) error {
+	if len(accounts) == 0 {
+		return nil
+	}
 
-func (r *MariaDBRepository) GetAccountForUpdate(ctx context.Context, id domain.AccountID) (*domain.Account, error) {
-	query := "SELECT id, balance, can_overdraft FROM accounts WHERE id = ? FOR UPDATE"
-	idBytes := uuid.UUID(id)
-	row := r.getExecutor(ctx).QueryRowContext(ctx, query, idBytes[:])
+	byID := make(map[domain.AccountID]*domain.Account, len(accounts))
+	placeholders := make([]string, len(accounts))
+	args := make([]any, len(accounts))
+	for i, acc := range accounts {
+		byID[acc.ID] = acc
+		placeholders[i] = "?"
+		idBytes := uuid.UUID(acc.ID)
+		args[i] = idBytes[:]
+	}
 
-	var idRaw uuid.UUID
-	var acc domain.Account
-	if err := row.Scan(&idRaw, &acc.Balance, &acc.CanOverdraft); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Not found
+	query := fmt.Sprintf(
+		"SELECT account_id, label_key, label_value FROM account_labels WHERE account_id IN (%s)",
+		strings.Join(placeholders, ","),
+	)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idRaw uuid.UUID
+		var key, value string
+		if err := rows.Scan(&idRaw, &key, &value); err != nil {
+			return err
 		}
-		return nil, err
+		acc := byID[domain.AccountID(idRaw)]
+		if acc.Labels == nil {
+			acc.Labels = make(map[string]string)
+		}
+		acc.Labels[key] = value
 	}
-	acc.ID = domain.AccountID(idRaw)
-	return &acc, nil
+	return rows.Err()
+}
+
+func (r *MariaDBRepository) SaveAccountLabels(ctx context.Context, id domain.AccountID, labels map[string]string) error {
+	idBytes := uuid.UUID(id)
+	exec := r.getExecutor(ctx)
+	if _, err := exec.ExecContext(ctx, "DELETE FROM account_labels WHERE account_id = ?", idBytes[:]); err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(labels))
+	args := make([]any, 0, len(labels)*3)
+	for k, v := range labels {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		args = append(args, idBytes[:], k, v)
+	}
+	query := "INSERT INTO account_labels (account_id, label_key, label_value) VALUES " + strings.Join(placeholders, ",")
+	_, err := exec.ExecContext(ctx, query, args...)
+	return err
 }
 
 func (r *MariaDBRepository) ListAccounts(ctx context.Context, filter domain.AccountFilter, sort domain.AccountSort, limit, offset int) ([]*domain.Account, int, error) {
@@ -187,8 +358,41 @@ func (r *MariaDBRepository) ListAccounts(ctx context.Context, filter domain.Acco
 		args = append(args, *filter.MaxBalance)
 	}
 	if filter.CanOverdraft != nil {
-		conditions = append(conditions, "can_overdraft = ?")
-		args = append(args, *filter.CanOverdraft)
+		if *filter.CanOverdraft {
+			conditions = append(conditions, "credit_limit > 0")
+		} else {
+			conditions = append(conditions, "credit_limit = 0")
+		}
+	}
+	if filter.MinCreditLimit != nil {
+		conditions = append(conditions, "credit_limit >= ?")
+		args = append(args, *filter.MinCreditLimit)
+	}
+	if filter.MaxCreditLimit != nil {
+		conditions = append(conditions, "credit_limit <= ?")
+		args = append(args, *filter.MaxCreditLimit)
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "owner_id = ?")
+		args = append(args, *filter.OwnerID)
+	}
+	if filter.Asset != nil {
+		conditions = append(conditions, "asset_code = ?")
+		args = append(args, *filter.Asset)
+	}
+	for _, sel := range filter.LabelSelectors {
+		cond := "EXISTS (SELECT 1 FROM account_labels l WHERE l.account_id = accounts.id AND l.label_key = ?"
+		args = append(args, sel.Key)
+		switch sel.Operator {
+		case domain.LabelOpEquals, domain.LabelOpIn:
+			placeholders := make([]string, len(sel.Values))
+			for i, v := range sel.Values {
+				placeholders[i] = "?"
+				args = append(args, v)
+			}
+			cond += fmt.Sprintf(" AND l.label_value IN (%s)", strings.Join(placeholders, ","))
+		}
+		conditions = append(conditions, cond+")")
 	}
 
 	whereClause := ""
@@ -221,146 +425,1491 @@ func (r *MariaDBRepository) ListAccounts(ctx context.Context, filter domain.Acco
 
 	// Build final query
 	query := fmt.Sprintf(
-		"SELECT id, balance, can_overdraft FROM accounts %s ORDER BY %s %s LIMIT ? OFFSET ?",
-		whereClause, orderBy, orderDir,
+		"SELECT %s FROM accounts %s ORDER BY %s %s LIMIT ? OFFSET ?",
+		accountColumns, whereClause, orderBy, orderDir,
 	)
 	args = append(args, limit, offset)
 
-	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	accounts, err := r.queryAccounts(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
+
+	return accounts, totalCount, nil
+}
+
+// -- AssetRepository --
+
+const assetColumns = "code, name, display_precision, issuer_account_id, escrow_account_id, circulating_supply, point_ttl_days, created_at"
+
+func scanAsset(row rowScanner) (*domain.Asset, error) {
+	var asset domain.Asset
+	var issuerRaw, escrowRaw []byte
+	if err := row.Scan(&asset.Code, &asset.Name, &asset.Precision, &issuerRaw, &escrowRaw, &asset.Supply, &asset.PointTTLDays, &asset.CreatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if asset.IssuerAccountID, err = scanNullAccountID(issuerRaw); err != nil {
+		return nil, err
+	}
+	if asset.EscrowAccountID, err = scanNullAccountID(escrowRaw); err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (r *MariaDBRepository) CreateAsset(ctx context.Context, asset *domain.Asset) error {
+	query := "INSERT INTO assets (" + assetColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		asset.Code,
+		asset.Name,
+		asset.Precision,
+		nullAccountID(asset.IssuerAccountID),
+		nullAccountID(asset.EscrowAccountID),
+		asset.Supply,
+		asset.PointTTLDays,
+		asset.CreatedAt,
+	)
+	if isDuplicateKeyError(err) {
+		return domain.ErrAssetAlreadyExists
+	}
+	return err
+}
+
+func (r *MariaDBRepository) SaveAsset(ctx context.Context, asset *domain.Asset) error {
+	query := `
+		INSERT INTO assets (` + assetColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name), display_precision = VALUES(display_precision),
+			issuer_account_id = VALUES(issuer_account_id), escrow_account_id = VALUES(escrow_account_id),
+			circulating_supply = VALUES(circulating_supply), point_ttl_days = VALUES(point_ttl_days)
+	`
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		asset.Code,
+		asset.Name,
+		asset.Precision,
+		nullAccountID(asset.IssuerAccountID),
+		nullAccountID(asset.EscrowAccountID),
+		asset.Supply,
+		asset.PointTTLDays,
+		asset.CreatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindAssetByCode(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	query := "SELECT " + assetColumns + " FROM assets WHERE code = ?"
+	return r.queryAsset(ctx, query, code)
+}
+
+func (r *MariaDBRepository) GetAssetForUpdate(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	query := "SELECT " + assetColumns + " FROM assets WHERE code = ? FOR UPDATE"
+	return r.queryAsset(ctx, query, code)
+}
+
+func (r *MariaDBRepository) queryAsset(ctx context.Context, query string, args ...any) (*domain.Asset, error) {
+	asset, err := scanAsset(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (r *MariaDBRepository) ListAssets(ctx context.Context) ([]*domain.Asset, error) {
+	query := "SELECT " + assetColumns + " FROM assets ORDER BY code"
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
-	var accounts []*domain.Account
+	var assets []*domain.Asset
 	for rows.Next() {
-		var idRaw uuid.UUID
-		var acc domain.Account
-		if err := rows.Scan(&idRaw, &acc.Balance, &acc.CanOverdraft); err != nil {
-			return nil, 0, err
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, err
 		}
-		acc.ID = domain.AccountID(idRaw)
-		accounts = append(accounts, &acc)
+		assets = append(assets, asset)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	return accounts, totalCount, nil
+	return assets, nil
 }
 
-// -- JournalEntryRepository --
+// -- AccountChangeRepository --
 
-func (r *MariaDBRepository) SaveJournalEntry(ctx context.Context, tx *domain.JournalEntry) error {
+func (r *MariaDBRepository) SaveAccountChange(ctx context.Context, change *domain.AccountChange) error {
 	query := `
-		INSERT INTO transactions 
-		(id, from_account_id, to_account_id, amount, description, idempotency_key, prev_hash, hash, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO account_changes
+		(id, account_id, field, old_value, new_value, changed_by, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	// Convert UUIDs to byte slices for BINARY(16) storage.
-	idBytes := uuid.UUID(tx.ID)
-	fromBytes := uuid.UUID(tx.FromAccountID)
-	toBytes := uuid.UUID(tx.ToAccountID)
+	idBytes := uuid.UUID(change.ID)
+	accIDBytes := uuid.UUID(change.AccountID)
 	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
 		idBytes[:],
-		fromBytes[:],
-		toBytes[:],
-		tx.Amount,
-		tx.Description,
-		tx.IdempotencyKey,
-		tx.PreviousHash,
-		tx.Hash,
-		tx.Timestamp,
+		accIDBytes[:],
+		change.Field,
+		change.OldValue,
+		change.NewValue,
+		change.ChangedBy,
+		change.ChangedAt,
 	)
 	return err
 }
 
-func (r *MariaDBRepository) FindJournalEntryByID(ctx context.Context, id domain.JournalEntryID) (*domain.JournalEntry, error) {
+// -- HoldRepository --
+
+const holdColumns = "id, account_id, to_account_id, amount, captured_amount, description, status, idempotency_key, request_fingerprint, expires_at, created_at, updated_at"
+
+func scanHold(row rowScanner) (*domain.Hold, error) {
+	var idRaw, accountRaw, toRaw uuid.UUID
+	var hold domain.Hold
+	err := row.Scan(
+		&idRaw,
+		&accountRaw,
+		&toRaw,
+		&hold.Amount,
+		&hold.CapturedAmount,
+		&hold.Description,
+		&hold.Status,
+		&hold.IdempotencyKey,
+		&hold.RequestFingerprint,
+		&hold.ExpiresAt,
+		&hold.CreatedAt,
+		&hold.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	hold.ID = domain.HoldID(idRaw)
+	hold.AccountID = domain.AccountID(accountRaw)
+	hold.ToAccountID = domain.AccountID(toRaw)
+	return &hold, nil
+}
+
+func (r *MariaDBRepository) SaveHold(ctx context.Context, hold *domain.Hold) error {
 	query := `
-		SELECT id, from_account_id, to_account_id, amount, description, idempotency_key, prev_hash, hash, created_at 
-		FROM transactions WHERE id = ?
+		INSERT INTO holds (` + holdColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE captured_amount = VALUES(captured_amount), status = VALUES(status),
+			updated_at = VALUES(updated_at)
 	`
+	idBytes := uuid.UUID(hold.ID)
+	accountBytes := uuid.UUID(hold.AccountID)
+	toBytes := uuid.UUID(hold.ToAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		accountBytes[:],
+		toBytes[:],
+		hold.Amount,
+		hold.CapturedAmount,
+		hold.Description,
+		hold.Status,
+		hold.IdempotencyKey,
+		hold.RequestFingerprint,
+		hold.ExpiresAt,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindHoldByID(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE id = ?"
 	idBytes := uuid.UUID(id)
-	row := r.getExecutor(ctx).QueryRowContext(ctx, query, idBytes[:])
-	return scanJournalEntry(row)
+	return r.queryHold(ctx, query, idBytes[:])
 }
 
-func (r *MariaDBRepository) FindByIdempotencyKey(ctx context.Context, key string) (*domain.JournalEntry, error) {
-	query := `
-		SELECT id, from_account_id, to_account_id, amount, description, idempotency_key, prev_hash, hash, created_at 
-		FROM transactions WHERE idempotency_key = ?
-	`
-	row := r.getExecutor(ctx).QueryRowContext(ctx, query, key)
-	return scanJournalEntry(row)
+func (r *MariaDBRepository) GetHoldForUpdate(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryHold(ctx, query, idBytes[:])
 }
 
-func (r *MariaDBRepository) GetLatestJournalEntry(ctx context.Context) (*domain.JournalEntry, error) {
-	query := `
-		SELECT id, from_account_id, to_account_id, amount, description, idempotency_key, prev_hash, hash, created_at 
-		FROM transactions 
-		ORDER BY id DESC 
-		LIMIT 1 FOR UPDATE
-	`
-	row := r.getExecutor(ctx).QueryRowContext(ctx, query)
-	return scanJournalEntry(row)
+func (r *MariaDBRepository) FindHoldByIdempotencyKey(ctx context.Context, key string) (*domain.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE idempotency_key = ?"
+	return r.queryHold(ctx, query, key)
 }
 
-func (r *MariaDBRepository) FindByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.JournalEntry, error) {
+func (r *MariaDBRepository) FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*domain.Hold, error) {
 	query := `
-		SELECT id, from_account_id, to_account_id, amount, description, idempotency_key, prev_hash, hash, created_at 
-		FROM transactions 
-		WHERE from_account_id = ? OR to_account_id = ?
-		ORDER BY id DESC
-		LIMIT ? OFFSET ?
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE status = ? AND expires_at <= ?
+		ORDER BY expires_at
+		LIMIT ?
 	`
-	accIDBytes := uuid.UUID(accountID)
-	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, accIDBytes[:], accIDBytes[:], limit, offset)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, domain.HoldStatusAuthorized, now, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var txs []*domain.JournalEntry
+	var holds []*domain.Hold
 	for rows.Next() {
-		var idRaw, fromRaw, toRaw uuid.UUID
-		var tx domain.JournalEntry
-		if err := rows.Scan(&idRaw, &fromRaw, &toRaw, &tx.Amount, &tx.Description, &tx.IdempotencyKey, &tx.PreviousHash, &tx.Hash, &tx.Timestamp); err != nil {
+		hold, err := scanHold(rows)
+		if err != nil {
 			return nil, err
 		}
-		tx.ID = domain.JournalEntryID(idRaw)
-		tx.FromAccountID = domain.AccountID(fromRaw)
-		tx.ToAccountID = domain.AccountID(toRaw)
-		txs = append(txs, &tx)
+		holds = append(holds, hold)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return txs, nil
+	return holds, nil
+}
+
+func (r *MariaDBRepository) queryHold(ctx context.Context, query string, args ...any) (*domain.Hold, error) {
+	hold, err := scanHold(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hold, nil
 }
 
-// Helper to scan single row
-func scanJournalEntry(row *sql.Row) (*domain.JournalEntry, error) {
+// -- ScheduledTransferRepository --
+
+const scheduledTransferColumns = "id, from_account_id, to_account_id, amount, description, idempotency_key, request_fingerprint, execute_at, status, journal_entry_id, failure_reason, created_at, updated_at"
+
+func scanScheduledTransfer(row rowScanner) (*domain.ScheduledTransfer, error) {
 	var idRaw, fromRaw, toRaw uuid.UUID
-	var tx domain.JournalEntry
+	var entryRaw []byte
+	var st domain.ScheduledTransfer
 	err := row.Scan(
 		&idRaw,
 		&fromRaw,
 		&toRaw,
-		&tx.Amount,
-		&tx.Description,
-		&tx.IdempotencyKey,
-		&tx.PreviousHash,
-		&tx.Hash,
-		&tx.Timestamp,
+		&st.Amount,
+		&st.Description,
+		&st.IdempotencyKey,
+		&st.RequestFingerprint,
+		&st.ExecuteAt,
+		&st.Status,
+		&entryRaw,
+		&st.FailureReason,
+		&st.CreatedAt,
+		&st.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
-	tx.ID = domain.JournalEntryID(idRaw)
-	tx.FromAccountID = domain.AccountID(fromRaw)
-	tx.ToAccountID = domain.AccountID(toRaw)
-	return &tx, nil
+	st.ID = domain.ScheduledTransferID(idRaw)
+	st.FromAccountID = domain.AccountID(fromRaw)
+	st.ToAccountID = domain.AccountID(toRaw)
+	if st.JournalEntryID, err = scanNullJournalEntryID(entryRaw); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *MariaDBRepository) SaveScheduledTransfer(ctx context.Context, st *domain.ScheduledTransfer) error {
+	query := `
+		INSERT INTO scheduled_transfers (` + scheduledTransferColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = VALUES(status), journal_entry_id = VALUES(journal_entry_id),
+			failure_reason = VALUES(failure_reason), updated_at = VALUES(updated_at)
+	`
+	idBytes := uuid.UUID(st.ID)
+	fromBytes := uuid.UUID(st.FromAccountID)
+	toBytes := uuid.UUID(st.ToAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		fromBytes[:],
+		toBytes[:],
+		st.Amount,
+		st.Description,
+		st.IdempotencyKey,
+		st.RequestFingerprint,
+		st.ExecuteAt,
+		st.Status,
+		nullJournalEntryID(st.JournalEntryID),
+		st.FailureReason,
+		st.CreatedAt,
+		st.UpdatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindScheduledTransferByID(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	query := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfers WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryScheduledTransfer(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) GetScheduledTransferForUpdate(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	query := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfers WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryScheduledTransfer(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindScheduledTransferByIdempotencyKey(ctx context.Context, key string) (*domain.ScheduledTransfer, error) {
+	query := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfers WHERE idempotency_key = ?"
+	return r.queryScheduledTransfer(ctx, query, key)
+}
+
+func (r *MariaDBRepository) FindScheduledTransfersByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.ScheduledTransfer, error) {
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers
+		WHERE from_account_id = ? OR to_account_id = ?
+		ORDER BY execute_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	accIDBytes := uuid.UUID(accountID)
+	return r.queryScheduledTransfers(ctx, query, accIDBytes[:], accIDBytes[:], limit, offset)
+}
+
+func (r *MariaDBRepository) FindDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledTransfer, error) {
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers
+		WHERE status = ? AND execute_at <= ?
+		ORDER BY execute_at, id
+		LIMIT ?
+	`
+	return r.queryScheduledTransfers(ctx, query, domain.ScheduledTransferStatusPending, now, limit)
+}
+
+func (r *MariaDBRepository) queryScheduledTransfer(ctx context.Context, query string, args ...any) (*domain.ScheduledTransfer, error) {
+	st, err := scanScheduledTransfer(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return st, nil
+}
+
+func (r *MariaDBRepository) queryScheduledTransfers(ctx context.Context, query string, args ...any) ([]*domain.ScheduledTransfer, error) {
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sts []*domain.ScheduledTransfer
+	for rows.Next() {
+		st, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		sts = append(sts, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sts, nil
+}
+
+// -- StandingOrderRepository --
+
+const standingOrderColumns = "id, from_account_id, to_account_id, amount, description, interval_seconds, cron_expression, end_at, next_run_at, status, idempotency_key, request_fingerprint, created_at, updated_at"
+
+func scanStandingOrder(row rowScanner) (*domain.StandingOrder, error) {
+	var idRaw, fromRaw, toRaw uuid.UUID
+	var intervalSeconds sql.NullInt64
+	var cronExpr sql.NullString
+	var endAt sql.NullTime
+	var order domain.StandingOrder
+	err := row.Scan(
+		&idRaw,
+		&fromRaw,
+		&toRaw,
+		&order.Amount,
+		&order.Description,
+		&intervalSeconds,
+		&cronExpr,
+		&endAt,
+		&order.NextRunAt,
+		&order.Status,
+		&order.IdempotencyKey,
+		&order.RequestFingerprint,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.ID = domain.StandingOrderID(idRaw)
+	order.FromAccountID = domain.AccountID(fromRaw)
+	order.ToAccountID = domain.AccountID(toRaw)
+	order.Interval = time.Duration(intervalSeconds.Int64) * time.Second
+	order.CronExpression = cronExpr.String
+	if endAt.Valid {
+		order.EndAt = &endAt.Time
+	}
+	return &order, nil
+}
+
+func (r *MariaDBRepository) SaveStandingOrder(ctx context.Context, order *domain.StandingOrder) error {
+	query := `
+		INSERT INTO standing_orders (` + standingOrderColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE next_run_at = VALUES(next_run_at), status = VALUES(status),
+			updated_at = VALUES(updated_at)
+	`
+	var intervalSeconds sql.NullInt64
+	if order.Interval != 0 {
+		intervalSeconds = sql.NullInt64{Int64: int64(order.Interval / time.Second), Valid: true}
+	}
+	var cronExpr sql.NullString
+	if order.CronExpression != "" {
+		cronExpr = sql.NullString{String: order.CronExpression, Valid: true}
+	}
+	idBytes := uuid.UUID(order.ID)
+	fromBytes := uuid.UUID(order.FromAccountID)
+	toBytes := uuid.UUID(order.ToAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		fromBytes[:],
+		toBytes[:],
+		order.Amount,
+		order.Description,
+		intervalSeconds,
+		cronExpr,
+		order.EndAt,
+		order.NextRunAt,
+		order.Status,
+		order.IdempotencyKey,
+		order.RequestFingerprint,
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindStandingOrderByID(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	query := "SELECT " + standingOrderColumns + " FROM standing_orders WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryStandingOrder(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) GetStandingOrderForUpdate(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	query := "SELECT " + standingOrderColumns + " FROM standing_orders WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryStandingOrder(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindStandingOrderByIdempotencyKey(ctx context.Context, key string) (*domain.StandingOrder, error) {
+	query := "SELECT " + standingOrderColumns + " FROM standing_orders WHERE idempotency_key = ?"
+	return r.queryStandingOrder(ctx, query, key)
+}
+
+func (r *MariaDBRepository) FindStandingOrdersByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE from_account_id = ? OR to_account_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	accIDBytes := uuid.UUID(accountID)
+	return r.queryStandingOrders(ctx, query, accIDBytes[:], accIDBytes[:], limit, offset)
+}
+
+func (r *MariaDBRepository) FindDueStandingOrders(ctx context.Context, now time.Time, limit int) ([]*domain.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE status = ? AND next_run_at <= ?
+		ORDER BY next_run_at, id
+		LIMIT ?
+	`
+	return r.queryStandingOrders(ctx, query, domain.StandingOrderStatusActive, now, limit)
+}
+
+func (r *MariaDBRepository) queryStandingOrder(ctx context.Context, query string, args ...any) (*domain.StandingOrder, error) {
+	order, err := scanStandingOrder(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *MariaDBRepository) queryStandingOrders(ctx context.Context, query string, args ...any) ([]*domain.StandingOrder, error) {
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.StandingOrder
+	for rows.Next() {
+		order, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *MariaDBRepository) SaveStandingOrderRun(ctx context.Context, run *domain.StandingOrderRun) error {
+	query := `
+		INSERT INTO standing_order_runs (id, standing_order_id, scheduled_at, status, journal_entry_id, failure_reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	idBytes := uuid.UUID(run.ID)
+	orderBytes := uuid.UUID(run.StandingOrderID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		orderBytes[:],
+		run.ScheduledAt,
+		run.Status,
+		nullJournalEntryID(run.JournalEntryID),
+		run.FailureReason,
+		run.CreatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindStandingOrderRuns(ctx context.Context, orderID domain.StandingOrderID, limit, offset int) ([]*domain.StandingOrderRun, error) {
+	query := `
+		SELECT id, standing_order_id, scheduled_at, status, journal_entry_id, failure_reason, created_at
+		FROM standing_order_runs
+		WHERE standing_order_id = ?
+		ORDER BY scheduled_at DESC
+		LIMIT ? OFFSET ?
+	`
+	orderBytes := uuid.UUID(orderID)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, orderBytes[:], limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*domain.StandingOrderRun
+	for rows.Next() {
+		var idRaw, orderRaw uuid.UUID
+		var entryRaw []byte
+		var run domain.StandingOrderRun
+		if err := rows.Scan(&idRaw, &orderRaw, &run.ScheduledAt, &run.Status, &entryRaw, &run.FailureReason, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		run.ID = domain.StandingOrderRunID(idRaw)
+		run.StandingOrderID = domain.StandingOrderID(orderRaw)
+		if run.JournalEntryID, err = scanNullJournalEntryID(entryRaw); err != nil {
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// -- EscrowRepository --
+
+const escrowColumns = "id, payer_account_id, beneficiary_account_id, escrow_account_id, amount, description, status, deadline, idempotency_key, created_at, updated_at"
+
+func scanEscrow(row rowScanner) (*domain.Escrow, error) {
+	var idRaw, payerRaw, beneficiaryRaw, escrowAccountRaw uuid.UUID
+	var escrow domain.Escrow
+	err := row.Scan(
+		&idRaw,
+		&payerRaw,
+		&beneficiaryRaw,
+		&escrowAccountRaw,
+		&escrow.Amount,
+		&escrow.Description,
+		&escrow.Status,
+		&escrow.Deadline,
+		&escrow.IdempotencyKey,
+		&escrow.CreatedAt,
+		&escrow.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	escrow.ID = domain.EscrowID(idRaw)
+	escrow.PayerAccountID = domain.AccountID(payerRaw)
+	escrow.BeneficiaryAccountID = domain.AccountID(beneficiaryRaw)
+	escrow.EscrowAccountID = domain.AccountID(escrowAccountRaw)
+	return &escrow, nil
+}
+
+func (r *MariaDBRepository) SaveEscrow(ctx context.Context, escrow *domain.Escrow) error {
+	query := `
+		INSERT INTO escrows (` + escrowColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = VALUES(status), updated_at = VALUES(updated_at)
+	`
+	idBytes := uuid.UUID(escrow.ID)
+	payerBytes := uuid.UUID(escrow.PayerAccountID)
+	beneficiaryBytes := uuid.UUID(escrow.BeneficiaryAccountID)
+	escrowAccountBytes := uuid.UUID(escrow.EscrowAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		payerBytes[:],
+		beneficiaryBytes[:],
+		escrowAccountBytes[:],
+		escrow.Amount,
+		escrow.Description,
+		escrow.Status,
+		escrow.Deadline,
+		escrow.IdempotencyKey,
+		escrow.CreatedAt,
+		escrow.UpdatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindEscrowByID(ctx context.Context, id domain.EscrowID) (*domain.Escrow, error) {
+	query := "SELECT " + escrowColumns + " FROM escrows WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryEscrow(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) GetEscrowForUpdate(ctx context.Context, id domain.EscrowID) (*domain.Escrow, error) {
+	query := "SELECT " + escrowColumns + " FROM escrows WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryEscrow(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindEscrowByIdempotencyKey(ctx context.Context, key string) (*domain.Escrow, error) {
+	query := "SELECT " + escrowColumns + " FROM escrows WHERE idempotency_key = ?"
+	return r.queryEscrow(ctx, query, key)
+}
+
+func (r *MariaDBRepository) FindExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]*domain.Escrow, error) {
+	query := `
+		SELECT ` + escrowColumns + `
+		FROM escrows
+		WHERE status = ? AND deadline <= ?
+		ORDER BY deadline
+		LIMIT ?
+	`
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, domain.EscrowStatusHeld, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escrows []*domain.Escrow
+	for rows.Next() {
+		escrow, err := scanEscrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, escrow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return escrows, nil
+}
+
+func (r *MariaDBRepository) queryEscrow(ctx context.Context, query string, args ...any) (*domain.Escrow, error) {
+	escrow, err := scanEscrow(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return escrow, nil
+}
+
+// -- PaymentRequestRepository --
+
+const paymentRequestColumns = "id, payer_account_id, payee_account_id, amount, description, status, expires_at, journal_entry_id, idempotency_key, request_fingerprint, created_at, updated_at"
+
+func scanPaymentRequest(row rowScanner) (*domain.PaymentRequest, error) {
+	var idRaw, payerRaw, payeeRaw uuid.UUID
+	var entryRaw []byte
+	var req domain.PaymentRequest
+	err := row.Scan(
+		&idRaw,
+		&payerRaw,
+		&payeeRaw,
+		&req.Amount,
+		&req.Description,
+		&req.Status,
+		&req.ExpiresAt,
+		&entryRaw,
+		&req.IdempotencyKey,
+		&req.RequestFingerprint,
+		&req.CreatedAt,
+		&req.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.ID = domain.PaymentRequestID(idRaw)
+	req.PayerAccountID = domain.AccountID(payerRaw)
+	req.PayeeAccountID = domain.AccountID(payeeRaw)
+	if req.JournalEntryID, err = scanNullJournalEntryID(entryRaw); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *MariaDBRepository) SavePaymentRequest(ctx context.Context, req *domain.PaymentRequest) error {
+	query := `
+		INSERT INTO payment_requests (` + paymentRequestColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = VALUES(status), journal_entry_id = VALUES(journal_entry_id),
+			updated_at = VALUES(updated_at)
+	`
+	idBytes := uuid.UUID(req.ID)
+	payerBytes := uuid.UUID(req.PayerAccountID)
+	payeeBytes := uuid.UUID(req.PayeeAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		payerBytes[:],
+		payeeBytes[:],
+		req.Amount,
+		req.Description,
+		req.Status,
+		req.ExpiresAt,
+		nullJournalEntryID(req.JournalEntryID),
+		req.IdempotencyKey,
+		req.RequestFingerprint,
+		req.CreatedAt,
+		req.UpdatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindPaymentRequestByID(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	query := "SELECT " + paymentRequestColumns + " FROM payment_requests WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryPaymentRequest(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) GetPaymentRequestForUpdate(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	query := "SELECT " + paymentRequestColumns + " FROM payment_requests WHERE id = ? FOR UPDATE"
+	idBytes := uuid.UUID(id)
+	return r.queryPaymentRequest(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindPaymentRequestByIdempotencyKey(ctx context.Context, key string) (*domain.PaymentRequest, error) {
+	query := "SELECT " + paymentRequestColumns + " FROM payment_requests WHERE idempotency_key = ?"
+	return r.queryPaymentRequest(ctx, query, key)
+}
+
+func (r *MariaDBRepository) FindPaymentRequestsByAccountID(ctx context.Context, accountID domain.AccountID, status domain.PaymentRequestStatus, limit, offset int) ([]*domain.PaymentRequest, error) {
+	query := `
+		SELECT ` + paymentRequestColumns + `
+		FROM payment_requests
+		WHERE (payer_account_id = ? OR payee_account_id = ?) AND (? = '' OR status = ?)
+		ORDER BY updated_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	accIDBytes := uuid.UUID(accountID)
+	return r.queryPaymentRequests(ctx, query, accIDBytes[:], accIDBytes[:], status, status, limit, offset)
+}
+
+func (r *MariaDBRepository) FindExpiredPaymentRequests(ctx context.Context, now time.Time, limit int) ([]*domain.PaymentRequest, error) {
+	query := `
+		SELECT ` + paymentRequestColumns + `
+		FROM payment_requests
+		WHERE status = ? AND expires_at <= ?
+		ORDER BY expires_at, id
+		LIMIT ?
+	`
+	return r.queryPaymentRequests(ctx, query, domain.PaymentRequestStatusPending, now, limit)
+}
+
+func (r *MariaDBRepository) queryPaymentRequest(ctx context.Context, query string, args ...any) (*domain.PaymentRequest, error) {
+	req, err := scanPaymentRequest(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *MariaDBRepository) queryPaymentRequests(ctx context.Context, query string, args ...any) ([]*domain.PaymentRequest, error) {
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reqs []*domain.PaymentRequest
+	for rows.Next() {
+		req, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// -- FeeRuleRepository --
+
+const feeRuleColumns = "id, name, asset_code, source_selector, destination_account_id, flat_fee, rate_basis_points, min_fee, max_fee, collector_account_id, priority, created_at"
+
+func scanFeeRule(row rowScanner) (*domain.FeeRule, error) {
+	var idRaw, collectorRaw uuid.UUID
+	var destinationRaw []byte
+	var selector sql.NullString
+	var maxFee sql.NullInt64
+	var rule domain.FeeRule
+	err := row.Scan(
+		&idRaw,
+		&rule.Name,
+		&rule.Asset,
+		&selector,
+		&destinationRaw,
+		&rule.FlatFee,
+		&rule.RateBasisPoints,
+		&rule.MinFee,
+		&maxFee,
+		&collectorRaw,
+		&rule.Priority,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = domain.FeeRuleID(idRaw)
+	rule.CollectorAccountID = domain.AccountID(collectorRaw)
+	if rule.DestinationAccountID, err = scanNullAccountID(destinationRaw); err != nil {
+		return nil, err
+	}
+	if selector.Valid {
+		rule.SourceSelector = &domain.LabelSelector{}
+		if err := json.Unmarshal([]byte(selector.String), rule.SourceSelector); err != nil {
+			return nil, err
+		}
+	}
+	if maxFee.Valid {
+		rule.MaxFee = &maxFee.Int64
+	}
+	return &rule, nil
+}
+
+func (r *MariaDBRepository) SaveFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	query := `
+		INSERT INTO fee_rules (` + feeRuleColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var selector sql.NullString
+	if rule.SourceSelector != nil {
+		b, err := json.Marshal(rule.SourceSelector)
+		if err != nil {
+			return err
+		}
+		selector = sql.NullString{String: string(b), Valid: true}
+	}
+	idBytes := uuid.UUID(rule.ID)
+	collectorBytes := uuid.UUID(rule.CollectorAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		rule.Name,
+		rule.Asset,
+		selector,
+		nullAccountID(rule.DestinationAccountID),
+		rule.FlatFee,
+		rule.RateBasisPoints,
+		rule.MinFee,
+		rule.MaxFee,
+		collectorBytes[:],
+		rule.Priority,
+		rule.CreatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error) {
+	query := "SELECT " + feeRuleColumns + " FROM fee_rules ORDER BY priority DESC, created_at"
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.FeeRule
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *MariaDBRepository) DeleteFeeRule(ctx context.Context, id domain.FeeRuleID) error {
+	idBytes := uuid.UUID(id)
+	res, err := r.getExecutor(ctx).ExecContext(ctx, "DELETE FROM fee_rules WHERE id = ?", idBytes[:])
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrFeeRuleNotFound
+	}
+	return nil
+}
+
+// -- VelocityLimitRepository --
+
+func (r *MariaDBRepository) FindVelocityLimits(ctx context.Context, accountID domain.AccountID) (*domain.VelocityLimits, error) {
+	query := `
+		SELECT max_per_transfer, max_outgoing_per_day, max_transfers_per_hour
+		FROM velocity_limits
+		WHERE account_id = ?
+	`
+	idBytes := uuid.UUID(accountID)
+	var perTransfer, perDay, perHour sql.NullInt64
+	err := r.getExecutor(ctx).QueryRowContext(ctx, query, idBytes[:]).Scan(&perTransfer, &perDay, &perHour)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &domain.VelocityLimits{
+		MaxPerTransfer:      nullInt64Ptr(perTransfer),
+		MaxOutgoingPerDay:   nullInt64Ptr(perDay),
+		MaxTransfersPerHour: nullInt64Ptr(perHour),
+	}, nil
+}
+
+func (r *MariaDBRepository) SaveVelocityLimits(ctx context.Context, accountID domain.AccountID, limits *domain.VelocityLimits) error {
+	query := `
+		INSERT INTO velocity_limits (account_id, max_per_transfer, max_outgoing_per_day, max_transfers_per_hour)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			max_per_transfer = VALUES(max_per_transfer),
+			max_outgoing_per_day = VALUES(max_outgoing_per_day),
+			max_transfers_per_hour = VALUES(max_transfers_per_hour)
+	`
+	idBytes := uuid.UUID(accountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		limits.MaxPerTransfer,
+		limits.MaxOutgoingPerDay,
+		limits.MaxTransfersPerHour,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) GetOutgoingTransferStats(ctx context.Context, accountID domain.AccountID, since time.Time) (*domain.OutgoingStats, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0), COALESCE(SUM(entry_type <> ?), 0), MIN(created_at)
+		FROM transactions
+		WHERE from_account_id = ? AND entry_type IN (?, ?, ?) AND created_at >= ?
+	`
+	idBytes := uuid.UUID(accountID)
+	var stats domain.OutgoingStats
+	var oldest sql.NullTime
+	err := r.getExecutor(ctx).QueryRowContext(ctx, query,
+		domain.EntryTypeFee,
+		idBytes[:],
+		domain.EntryTypeTransfer, domain.EntryTypeFee, domain.EntryTypeEscrow,
+		since,
+	).Scan(&stats.Total, &stats.Count, &oldest)
+	if err != nil {
+		return nil, err
+	}
+	if oldest.Valid {
+		stats.Oldest = oldest.Time
+	}
+	return &stats, nil
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+// -- PointLotRepository --
+
+const pointLotColumns = "id, account_id, asset_code, amount, remaining, expires_at, journal_entry_id, created_at"
+
+func scanPointLot(row rowScanner) (*domain.PointLot, error) {
+	var idRaw, accountRaw, entryRaw uuid.UUID
+	var lot domain.PointLot
+	err := row.Scan(
+		&idRaw,
+		&accountRaw,
+		&lot.Asset,
+		&lot.Amount,
+		&lot.Remaining,
+		&lot.ExpiresAt,
+		&entryRaw,
+		&lot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	lot.ID = domain.PointLotID(idRaw)
+	lot.AccountID = domain.AccountID(accountRaw)
+	lot.JournalEntryID = domain.JournalEntryID(entryRaw)
+	return &lot, nil
+}
+
+func (r *MariaDBRepository) SavePointLot(ctx context.Context, lot *domain.PointLot) error {
+	query := `
+		INSERT INTO point_lots (` + pointLotColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE remaining = VALUES(remaining)
+	`
+	idBytes := uuid.UUID(lot.ID)
+	accountBytes := uuid.UUID(lot.AccountID)
+	entryBytes := uuid.UUID(lot.JournalEntryID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		accountBytes[:],
+		lot.Asset,
+		lot.Amount,
+		lot.Remaining,
+		lot.ExpiresAt,
+		entryBytes[:],
+		lot.CreatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) GetPointLot(ctx context.Context, id domain.PointLotID) (*domain.PointLot, error) {
+	query := "SELECT " + pointLotColumns + " FROM point_lots WHERE id = ?"
+	idBytes := uuid.UUID(id)
+	lot, err := scanPointLot(r.getExecutor(ctx).QueryRowContext(ctx, query, idBytes[:]))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lot, nil
+}
+
+func (r *MariaDBRepository) FindOpenPointLots(ctx context.Context, accountID domain.AccountID) ([]*domain.PointLot, error) {
+	query := `
+		SELECT ` + pointLotColumns + `
+		FROM point_lots
+		WHERE account_id = ? AND remaining > 0
+		ORDER BY expires_at, id
+	`
+	idBytes := uuid.UUID(accountID)
+	return r.queryPointLots(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindExpiredPointLots(ctx context.Context, now time.Time, limit int) ([]*domain.PointLot, error) {
+	query := `
+		SELECT ` + pointLotColumns + `
+		FROM point_lots
+		WHERE expires_at <= ? AND remaining > 0
+			AND account_id NOT IN (SELECT id FROM accounts WHERE status = ?)
+		ORDER BY expires_at, id
+		LIMIT ?
+	`
+	return r.queryPointLots(ctx, query, now, domain.AccountStatusFrozen, limit)
+}
+
+func (r *MariaDBRepository) queryPointLots(ctx context.Context, query string, args ...any) ([]*domain.PointLot, error) {
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*domain.PointLot
+	for rows.Next() {
+		lot, err := scanPointLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// -- JournalEntryRepository --
+
+// journalEntrySelect selects the columns scanJournalEntry expects from transactions aliased as t.
+const journalEntrySelect = `
+	SELECT t.id, t.entry_type, t.from_account_id, t.to_account_id, t.amount, t.description, t.idempotency_key,
+		t.request_fingerprint, t.group_id, t.reverses_id, t.escrow_id, t.prev_hash, t.hash, t.created_at
+	FROM transactions t
+`
+
+func (r *MariaDBRepository) SaveJournalEntry(ctx context.Context, tx *domain.JournalEntry) error {
+	query := `
+		INSERT INTO transactions 
+		(id, entry_type, from_account_id, to_account_id, amount, description, idempotency_key, request_fingerprint, group_id, reverses_id, escrow_id, prev_hash, hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	entryType := tx.Type
+	if entryType == "" {
+		entryType = domain.EntryTypeTransfer
+	}
+	// Convert UUIDs to byte slices for BINARY(16) storage.
+	idBytes := uuid.UUID(tx.ID)
+	fromBytes := uuid.UUID(tx.FromAccountID)
+	toBytes := uuid.UUID(tx.ToAccountID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		entryType,
+		fromBytes[:],
+		toBytes[:],
+		tx.Amount,
+		tx.Description,
+		tx.IdempotencyKey,
+		tx.RequestFingerprint,
+		nullJournalGroupID(tx.GroupID),
+		nullJournalEntryID(tx.ReversesID),
+		nullEscrowID(tx.EscrowID),
+		tx.PreviousHash,
+		tx.Hash,
+		tx.Timestamp,
+	)
+	if err != nil {
+		return err
+	}
+	return r.saveJournalEntryMetadata(ctx, tx.ID, tx.Metadata)
+}
+
+func (r *MariaDBRepository) saveJournalEntryMetadata(ctx context.Context, id domain.JournalEntryID, metadata map[string]string) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	idBytes := uuid.UUID(id)
+	placeholders := make([]string, 0, len(metadata))
+	args := make([]any, 0, len(metadata)*3)
+	for k, v := range metadata {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		args = append(args, idBytes[:], k, v)
+	}
+	query := "INSERT INTO journal_entry_metadata (journal_entry_id, metadata_key, metadata_value) VALUES " + strings.Join(placeholders, ",")
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *MariaDBRepository) FindJournalEntryByID(ctx context.Context, id domain.JournalEntryID) (*domain.JournalEntry, error) {
+	query := journalEntrySelect + " WHERE t.id = ?"
+	idBytes := uuid.UUID(id)
+	return r.queryJournalEntry(ctx, query, idBytes[:])
+}
+
+func (r *MariaDBRepository) FindByIdempotencyKey(ctx context.Context, key string) (*domain.JournalEntry, error) {
+	query := journalEntrySelect + " WHERE t.idempotency_key = ?"
+	return r.queryJournalEntry(ctx, query, key)
+}
+
+func (r *MariaDBRepository) GetLatestJournalEntry(ctx context.Context) (*domain.JournalEntry, error) {
+	query := journalEntrySelect + `
+		ORDER BY t.id DESC 
+		LIMIT 1 FOR UPDATE
+	`
+	return r.queryJournalEntry(ctx, query)
+}
+
+func (r *MariaDBRepository) FindByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.JournalEntry, error) {
+	query := journalEntrySelect + `
+		WHERE t.from_account_id = ? OR t.to_account_id = ?
+		ORDER BY t.id DESC
+		LIMIT ? OFFSET ?
+	`
+	accIDBytes := uuid.UUID(accountID)
+	return r.queryJournalEntries(ctx, query, accIDBytes[:], accIDBytes[:], limit, offset)
+}
+
+func (r *MariaDBRepository) FindJournalEntries(ctx context.Context, filter domain.JournalEntryFilter, limit, offset int) ([]*domain.JournalEntry, error) {
+	var conditions []string
+	var args []any
+	if filter.AccountID != nil {
+		accIDBytes := uuid.UUID(*filter.AccountID)
+		conditions = append(conditions, "(t.from_account_id = ? OR t.to_account_id = ?)")
+		args = append(args, accIDBytes[:], accIDBytes[:])
+	}
+	for k, v := range filter.Metadata {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM journal_entry_metadata m WHERE m.journal_entry_id = t.id AND m.metadata_key = ? AND m.metadata_value = ?)")
+		args = append(args, k, v)
+	}
+
+	query := journalEntrySelect
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY t.id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+	return r.queryJournalEntries(ctx, query, args...)
+}
+
+// queryJournalEntry runs a query built on journalEntrySelect and returns the single entry with its metadata
+// and reversals, or nil if no row matched.
+func (r *MariaDBRepository) queryJournalEntry(ctx context.Context, query string, args ...any) (*domain.JournalEntry, error) {
+	tx, err := scanJournalEntry(r.getExecutor(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.loadJournalEntryDetails(ctx, []*domain.JournalEntry{tx}); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *MariaDBRepository) queryJournalEntries(ctx context.Context, query string, args ...any) ([]*domain.JournalEntry, error) {
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []*domain.JournalEntry
+	for rows.Next() {
+		tx, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadJournalEntryDetails(ctx, txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// loadJournalEntryDetails fills in the metadata and the reversals of the given entries.
+func (r *MariaDBRepository) loadJournalEntryDetails(ctx context.Context, entries []*domain.JournalEntry) error {
+	if err := r.loadJournalEntryMetadata(ctx, entries); err != nil {
+		return err
+	}
+	return r.loadJournalEntryReversals(ctx, entries)
+}
+
+// loadJournalEntryReversals fills in the reversals of the given entries with a single query.
+func (r *MariaDBRepository) loadJournalEntryReversals(ctx context.Context, entries []*domain.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byID := make(map[domain.JournalEntryID]*domain.JournalEntry, len(entries))
+	placeholders := make([]string, len(entries))
+	args := make([]any, len(entries))
+	for i, entry := range entries {
+		byID[entry.ID] = entry
+		placeholders[i] = "?"
+		idBytes := uuid.UUID(entry.ID)
+		args[i] = idBytes[:]
+	}
+
+	query := fmt.Sprintf(
+		"SELECT id, reverses_id, amount FROM transactions WHERE reverses_id IN (%s) ORDER BY id",
+		strings.Join(placeholders, ","),
+	)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idRaw, reversesRaw uuid.UUID
+		var amount int64
+		if err := rows.Scan(&idRaw, &reversesRaw, &amount); err != nil {
+			return err
+		}
+		entry := byID[domain.JournalEntryID(reversesRaw)]
+		entry.ReversedByIDs = append(entry.ReversedByIDs, domain.JournalEntryID(idRaw))
+		entry.ReversedAmount += amount
+	}
+	return rows.Err()
+}
+
+// loadJournalEntryMetadata fills in the metadata of the given entries with a single query.
+func (r *MariaDBRepository) loadJournalEntryMetadata(ctx context.Context, entries []*domain.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byID := make(map[domain.JournalEntryID]*domain.JournalEntry, len(entries))
+	placeholders := make([]string, len(entries))
+	args := make([]any, len(entries))
+	for i, entry := range entries {
+		byID[entry.ID] = entry
+		placeholders[i] = "?"
+		idBytes := uuid.UUID(entry.ID)
+		args[i] = idBytes[:]
+	}
+
+	query := fmt.Sprintf(
+		"SELECT journal_entry_id, metadata_key, metadata_value FROM journal_entry_metadata WHERE journal_entry_id IN (%s)",
+		strings.Join(placeholders, ","),
+	)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idRaw uuid.UUID
+		var key, value string
+		if err := rows.Scan(&idRaw, &key, &value); err != nil {
+			return err
+		}
+		entry := byID[domain.JournalEntryID(idRaw)]
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string)
+		}
+		entry.Metadata[key] = value
+	}
+	return rows.Err()
+}
+
+// scanJournalEntry scans a row selected with journalEntrySelect.
+func scanJournalEntry(row rowScanner) (*domain.JournalEntry, error) {
+	var idRaw, fromRaw, toRaw uuid.UUID
+	var groupRaw, reversesRaw, escrowRaw []byte
+	var tx domain.JournalEntry
+	err := row.Scan(
+		&idRaw,
+		&tx.Type,
+		&fromRaw,
+		&toRaw,
+		&tx.Amount,
+		&tx.Description,
+		&tx.IdempotencyKey,
+		&tx.RequestFingerprint,
+		&groupRaw,
+		&reversesRaw,
+		&escrowRaw,
+		&tx.PreviousHash,
+		&tx.Hash,
+		&tx.Timestamp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tx.ID = domain.JournalEntryID(idRaw)
+	tx.FromAccountID = domain.AccountID(fromRaw)
+	tx.ToAccountID = domain.AccountID(toRaw)
+	if groupRaw != nil {
+		groupID, err := uuid.FromBytes(groupRaw)
+		if err != nil {
+			return nil, err
+		}
+		journalGroupID := domain.JournalGroupID(groupID)
+		tx.GroupID = &journalGroupID
+	}
+	if tx.ReversesID, err = scanNullJournalEntryID(reversesRaw); err != nil {
+		return nil, err
+	}
+	if escrowRaw != nil {
+		escrowID, err := uuid.FromBytes(escrowRaw)
+		if err != nil {
+			return nil, err
+		}
+		id := domain.EscrowID(escrowID)
+		tx.EscrowID = &id
+	}
+	return &tx, nil
+}
+
+func nullJournalEntryID(id *domain.JournalEntryID) any {
+	if id == nil {
+		return nil
+	}
+	idBytes := uuid.UUID(*id)
+	return idBytes[:]
+}
+
+func scanNullJournalEntryID(raw []byte) (*domain.JournalEntryID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	entryID := domain.JournalEntryID(id)
+	return &entryID, nil
+}
+
+func nullEscrowID(id *domain.EscrowID) any {
+	if id == nil {
+		return nil
+	}
+	idBytes := uuid.UUID(*id)
+	return idBytes[:]
+}
+
+func nullJournalGroupID(id *domain.JournalGroupID) any {
+	if id == nil {
+		return nil
+	}
+	idBytes := uuid.UUID(*id)
+	return idBytes[:]
+}
+
+func (r *MariaDBRepository) SaveJournalGroup(ctx context.Context, group *domain.JournalGroup) error {
+	query := `
+		INSERT INTO journal_groups (id, idempotency_key, request_fingerprint, description, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	idBytes := uuid.UUID(group.ID)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query,
+		idBytes[:],
+		group.IdempotencyKey,
+		group.RequestFingerprint,
+		group.Description,
+		group.CreatedAt,
+	)
+	return err
+}
+
+func (r *MariaDBRepository) FindJournalGroupByIdempotencyKey(ctx context.Context, key string) (*domain.JournalGroup, error) {
+	query := "SELECT id, idempotency_key, request_fingerprint, description, created_at FROM journal_groups WHERE idempotency_key = ?"
+	var idRaw uuid.UUID
+	var group domain.JournalGroup
+	err := r.getExecutor(ctx).QueryRowContext(ctx, query, key).Scan(&idRaw, &group.IdempotencyKey, &group.RequestFingerprint, &group.Description, &group.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	group.ID = domain.JournalGroupID(idRaw)
+
+	entriesQuery := journalEntrySelect + " WHERE t.group_id = ? ORDER BY t.id"
+	group.Entries, err = r.queryJournalEntries(ctx, entriesQuery, idRaw[:])
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
 }
@@ -2,34 +2,119 @@ package usecase
 
 import (
 	"context"
+	"maps"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
 )
 
+// MaxChangedByLength is the maximum allowed length of the actor recorded with an account change
+const MaxChangedByLength = 255
+
 type AccountUseCase struct {
 	accountRepo domain.AccountRepository
+	changeRepo  domain.AccountChangeRepository
+	assetRepo   domain.AssetRepository
 	tm          domain.TransactionManager
 }
 
-func NewAccountUseCase(accountRepo domain.AccountRepository, tm domain.TransactionManager) *AccountUseCase {
+func NewAccountUseCase(
+	accountRepo domain.AccountRepository,
+	changeRepo domain.AccountChangeRepository,
+	assetRepo domain.AssetRepository,
+	tm domain.TransactionManager,
+) *AccountUseCase {
 	return &AccountUseCase{
 		accountRepo: accountRepo,
+		changeRepo:  changeRepo,
+		assetRepo:   assetRepo,
 		tm:          tm,
 	}
 }
 
-func (u *AccountUseCase) CreateAccount(ctx context.Context, canOverdraft bool) (*domain.Account, error) {
+// CreateAccountInput represents the input for creating an account.
+type CreateAccountInput struct {
+	// Asset defaults to domain.DefaultAssetCode when empty.
+	Asset       domain.AssetCode
+	CreditLimit int64
+	// MaxBalance caps the balance. Nil means no cap.
+	MaxBalance *int64
+	Labels     map[string]string
+	OwnerID    string
+	// Alias must be unique. Empty means no alias.
+	Alias string
+	// ParentID makes the new account a sub-account. The asset defaults to the parent's.
+	ParentID *domain.AccountID
+}
+
+func (u *AccountUseCase) CreateAccount(ctx context.Context, input CreateAccountInput) (*domain.Account, error) {
+	if input.CreditLimit < 0 {
+		return nil, domain.ErrInvalidCreditLimit
+	}
+	if input.MaxBalance != nil && *input.MaxBalance < 0 {
+		return nil, domain.ErrInvalidMaxBalance
+	}
+	if err := domain.ValidateLabels(input.Labels); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateOwnerID(input.OwnerID); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateAlias(input.Alias); err != nil {
+		return nil, err
+	}
+
 	var acc *domain.Account
 
 	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		assetCode := input.Asset
+		if input.ParentID != nil {
+			parent, err := u.validateParent(ctx, *input.ParentID)
+			if err != nil {
+				return err
+			}
+			if assetCode == "" {
+				assetCode = parent.Asset
+			} else if assetCode != parent.Asset {
+				return domain.ErrAssetMismatch
+			}
+		}
+		if assetCode == "" {
+			assetCode = domain.DefaultAssetCode
+		}
+
+		asset, err := u.assetRepo.FindAssetByCode(ctx, assetCode)
+		if err != nil {
+			return err
+		}
+		if asset == nil {
+			return domain.ErrAssetNotFound
+		}
+
 		id, err := uuid.NewV7()
 		if err != nil {
 			return err
 		}
-		acc = domain.NewAccount(domain.AccountID(id), canOverdraft)
+		if err := u.checkAliasAvailable(ctx, domain.AccountID(id), input.Alias); err != nil {
+			return err
+		}
+		acc = domain.NewAccount(domain.AccountID(id), input.CreditLimit)
+		acc.Asset = asset.Code
+		acc.MaxBalance = input.MaxBalance
+		acc.Labels = input.Labels
+		acc.OwnerID = input.OwnerID
+		acc.Alias = input.Alias
+		acc.ParentID = input.ParentID
 
-		return u.accountRepo.SaveAccount(ctx, acc)
+		if err := u.accountRepo.SaveAccount(ctx, acc); err != nil {
+			return err
+		}
+		if len(acc.Labels) > 0 {
+			return u.accountRepo.SaveAccountLabels(ctx, acc.ID, acc.Labels)
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -71,6 +156,12 @@ func (u *AccountUseCase) ListAccounts(ctx context.Context, input ListAccountsInp
 		offset = 0
 	}
 
+	for _, sel := range input.Filter.LabelSelectors {
+		if err := sel.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	accounts, totalCount, err := u.accountRepo.ListAccounts(ctx, input.Filter, input.Sort, limit, offset)
 	if err != nil {
 		return nil, err
@@ -82,7 +173,204 @@ func (u *AccountUseCase) ListAccounts(ctx context.Context, input ListAccountsInp
 	}, nil
 }
 
+// ListAccountsByOwner returns the accounts that reference ownerID as their owner.
+func (u *AccountUseCase) ListAccountsByOwner(ctx context.Context, ownerID string, limit, offset int) (*ListAccountsOutput, error) {
+	if ownerID == "" {
+		return nil, domain.ErrInvalidOwnerID
+	}
+	return u.ListAccounts(ctx, ListAccountsInput{
+		Filter: domain.AccountFilter{OwnerID: &ownerID},
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
 // GetAccounts returns accounts by their IDs.
 func (u *AccountUseCase) GetAccounts(ctx context.Context, ids []domain.AccountID) ([]*domain.Account, error) {
 	return u.accountRepo.FindAccountsByIDs(ctx, ids)
 }
+
+// UpdateAccountInput represents the input for updating account settings.
+// Nil fields are left unchanged.
+type UpdateAccountInput struct {
+	AccountID   domain.AccountID
+	CreditLimit *int64
+	MaxBalance  *int64
+	// ClearMaxBalance removes the balance cap. It takes precedence over MaxBalance.
+	ClearMaxBalance bool
+	// OwnerID replaces the owner reference. An empty string removes the owner.
+	OwnerID *string
+	// Alias replaces the alias. An empty string removes the alias.
+	Alias *string
+	// Force allows lowering the credit limit below the current debt
+	// and the max balance below the current balance.
+	Force     bool
+	ChangedBy string
+}
+
+// UpdateAccount changes account settings and records each change in the account history.
+func (u *AccountUseCase) UpdateAccount(ctx context.Context, input UpdateAccountInput) (*domain.Account, error) {
+	return u.modifyAccount(ctx, input.AccountID, input.ChangedBy, func(ctx context.Context, acc *domain.Account) error {
+		if input.CreditLimit != nil {
+			if err := acc.SetCreditLimit(*input.CreditLimit, input.Force); err != nil {
+				return err
+			}
+		}
+		if input.ClearMaxBalance {
+			if err := acc.SetMaxBalance(nil, input.Force); err != nil {
+				return err
+			}
+		} else if input.MaxBalance != nil {
+			if err := acc.SetMaxBalance(input.MaxBalance, input.Force); err != nil {
+				return err
+			}
+		}
+		if input.OwnerID != nil {
+			if err := acc.SetOwnerID(*input.OwnerID); err != nil {
+				return err
+			}
+		}
+		if input.Alias != nil && *input.Alias != acc.Alias {
+			if err := u.checkAliasAvailable(ctx, acc.ID, *input.Alias); err != nil {
+				return err
+			}
+			if err := acc.SetAlias(*input.Alias); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ResolveAlias returns the account with the alias.
+func (u *AccountUseCase) ResolveAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	if alias == "" {
+		return nil, domain.ErrInvalidAlias
+	}
+	if err := domain.ValidateAlias(alias); err != nil {
+		return nil, err
+	}
+	acc, err := u.accountRepo.FindAccountByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	return acc, nil
+}
+
+// checkAliasAvailable returns ErrAliasTaken if an account other than id uses alias.
+// Concurrent claims are caught by the unique index when saving.
+func (u *AccountUseCase) checkAliasAvailable(ctx context.Context, id domain.AccountID, alias string) error {
+	if alias == "" {
+		return nil
+	}
+	owner, err := u.accountRepo.FindAccountByAlias(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.ID != id {
+		return domain.ErrAliasTaken
+	}
+	return nil
+}
+
+// SetAccountLabelsInput represents the input for editing account labels.
+type SetAccountLabelsInput struct {
+	AccountID domain.AccountID
+	// Set upserts these labels.
+	Set map[string]string
+	// Remove deletes these label keys after Set is applied.
+	Remove    []string
+	ChangedBy string
+}
+
+// SetAccountLabels edits the labels of an account and records each change in the account history.
+func (u *AccountUseCase) SetAccountLabels(ctx context.Context, input SetAccountLabelsInput) (*domain.Account, error) {
+	return u.modifyAccount(ctx, input.AccountID, input.ChangedBy, func(ctx context.Context, acc *domain.Account) error {
+		return acc.SetLabels(input.Set, input.Remove)
+	})
+}
+
+// FreezeAccount stops the account from sending points.
+func (u *AccountUseCase) FreezeAccount(ctx context.Context, id domain.AccountID, changedBy string) (*domain.Account, error) {
+	return u.modifyAccount(ctx, id, changedBy, func(ctx context.Context, acc *domain.Account) error {
+		return acc.Freeze()
+	})
+}
+
+// UnfreezeAccount makes a frozen account active again.
+func (u *AccountUseCase) UnfreezeAccount(ctx context.Context, id domain.AccountID, changedBy string) (*domain.Account, error) {
+	return u.modifyAccount(ctx, id, changedBy, func(ctx context.Context, acc *domain.Account) error {
+		return acc.Unfreeze()
+	})
+}
+
+// CloseAccount permanently closes an account with zero balance.
+func (u *AccountUseCase) CloseAccount(ctx context.Context, id domain.AccountID, changedBy string) (*domain.Account, error) {
+	return u.modifyAccount(ctx, id, changedBy, func(ctx context.Context, acc *domain.Account) error {
+		return acc.Close()
+	})
+}
+
+// modifyAccount applies fn to the account while holding its row lock,
+// then saves it and records every changed setting on behalf of changedBy.
+func (u *AccountUseCase) modifyAccount(ctx context.Context, id domain.AccountID, changedBy string, fn func(ctx context.Context, acc *domain.Account) error) (*domain.Account, error) {
+	if strings.TrimSpace(changedBy) == "" || len(changedBy) > MaxChangedByLength {
+		return nil, domain.ErrInvalidChangedBy
+	}
+
+	var acc *domain.Account
+
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		acc, err = u.accountRepo.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if acc == nil {
+			return domain.ErrAccountNotFound
+		}
+
+		before := *acc
+		before.Labels = maps.Clone(acc.Labels)
+		if err := fn(ctx, acc); err != nil {
+			return err
+		}
+
+		changes := domain.DiffAccounts(&before, acc)
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := u.accountRepo.SaveAccount(ctx, acc); err != nil {
+			return err
+		}
+		if !maps.Equal(before.Labels, acc.Labels) {
+			if err := u.accountRepo.SaveAccountLabels(ctx, acc.ID, acc.Labels); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		for _, change := range changes {
+			changeID, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			change.ID = domain.AccountChangeID(changeID)
+			change.AccountID = acc.ID
+			change.ChangedBy = changedBy
+			change.ChangedAt = now
+			if err := u.changeRepo.SaveAccountChange(ctx, &change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
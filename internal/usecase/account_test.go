@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/google/uuid"
@@ -29,6 +30,7 @@ func (m *mockAccountRepo) SaveAccount(ctx context.Context, account *domain.Accou
 		return m.err
 	}
 	m.accounts[account.ID] = account
+	account.Version++
 	return nil
 }
 
@@ -47,6 +49,41 @@ func (m *mockAccountRepo) GetAccountForUpdate(ctx context.Context, id domain.Acc
 	return m.FindAccountByID(ctx, id)
 }
 
+func (m *mockAccountRepo) FindAccountByAlias(ctx context.Context, alias string) (*domain.Account, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, acc := range m.accounts {
+		if acc.Alias == alias {
+			return acc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) FindAccountsByParentIDs(ctx context.Context, parentIDs []domain.AccountID) ([]*domain.Account, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*domain.Account
+	for _, acc := range m.accounts {
+		if acc.ParentID != nil && slices.Contains(parentIDs, *acc.ParentID) {
+			result = append(result, acc)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAccountRepo) SaveAccountLabels(ctx context.Context, id domain.AccountID, labels map[string]string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if acc, ok := m.accounts[id]; ok {
+		acc.Labels = labels
+	}
+	return nil
+}
+
 func (m *mockAccountRepo) ListAccounts(ctx context.Context, filter domain.AccountFilter, sort domain.AccountSort, limit, offset int) ([]*domain.Account, int, error) {
 	if m.err != nil {
 		return nil, 0, m.err
@@ -60,7 +97,16 @@ func (m *mockAccountRepo) ListAccounts(ctx context.Context, filter domain.Accoun
 		if filter.MaxBalance != nil && acc.Balance > *filter.MaxBalance {
 			continue
 		}
-		if filter.CanOverdraft != nil && acc.CanOverdraft != *filter.CanOverdraft {
+		if filter.CanOverdraft != nil && acc.CanOverdraft() != *filter.CanOverdraft {
+			continue
+		}
+		if filter.OwnerID != nil && acc.OwnerID != *filter.OwnerID {
+			continue
+		}
+		if filter.Asset != nil && acc.Asset != *filter.Asset {
+			continue
+		}
+		if !matchesAllSelectors(filter.LabelSelectors, acc.Labels) {
 			continue
 		}
 		result = append(result, acc)
@@ -78,6 +124,15 @@ func (m *mockAccountRepo) ListAccounts(ctx context.Context, filter domain.Accoun
 	return result, totalCount, nil
 }
 
+func matchesAllSelectors(selectors []domain.LabelSelector, labels map[string]string) bool {
+	for _, sel := range selectors {
+		if !sel.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *mockAccountRepo) FindAccountsByIDs(ctx context.Context, ids []domain.AccountID) ([]*domain.Account, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -91,16 +146,29 @@ func (m *mockAccountRepo) FindAccountsByIDs(ctx context.Context, ids []domain.Ac
 	return result, nil
 }
 
+type mockAccountChangeRepo struct {
+	changes []*domain.AccountChange
+}
+
+func newMockAccountChangeRepo() *mockAccountChangeRepo {
+	return &mockAccountChangeRepo{}
+}
+
+func (m *mockAccountChangeRepo) SaveAccountChange(ctx context.Context, change *domain.AccountChange) error {
+	m.changes = append(m.changes, change)
+	return nil
+}
+
 // mockTxManager is defined in transfer_test.go
 
 func TestAccountUseCase_CreateAccount(t *testing.T) {
 	repo := newMockAccountRepo()
 	tm := &mockTxManager{}
-	uc := NewAccountUseCase(repo, tm)
+	uc := NewAccountUseCase(repo, newMockAccountChangeRepo(), newMockAssetRepo(), tm)
 	ctx := context.Background()
 
 	// 1. Create new account
-	acc, err := uc.CreateAccount(ctx, false)
+	acc, err := uc.CreateAccount(ctx, CreateAccountInput{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -112,14 +180,14 @@ func TestAccountUseCase_CreateAccount(t *testing.T) {
 	}
 
 	// 2. Create another account
-	acc2, err := uc.CreateAccount(ctx, true)
+	acc2, err := uc.CreateAccount(ctx, CreateAccountInput{CreditLimit: domain.UnlimitedCreditLimit})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if acc.ID == acc2.ID {
 		t.Errorf("expected different account IDs")
 	}
-	if !acc2.CanOverdraft {
+	if !acc2.CanOverdraft() {
 		t.Errorf("expected can_overdraft true")
 	}
 }
@@ -127,12 +195,12 @@ func TestAccountUseCase_CreateAccount(t *testing.T) {
 func TestAccountUseCase_GetAccount(t *testing.T) {
 	repo := newMockAccountRepo()
 	tm := &mockTxManager{}
-	uc := NewAccountUseCase(repo, tm)
+	uc := NewAccountUseCase(repo, newMockAccountChangeRepo(), newMockAssetRepo(), tm)
 	ctx := context.Background()
 
 	// Setup: create an account directly in repo
 	testID := domain.AccountID(mustUUID("acc-test"))
-	existing := domain.NewAccount(testID, false)
+	existing := domain.NewAccount(testID, 0)
 	repo.SaveAccount(ctx, existing)
 
 	// Test GetAccount
@@ -161,12 +229,12 @@ func TestAccountUseCase_RepoError(t *testing.T) {
 	repo := newMockAccountRepo()
 	repo.err = errors.New("db error")
 	tm := &mockTxManager{}
-	uc := NewAccountUseCase(repo, tm)
+	uc := NewAccountUseCase(repo, newMockAccountChangeRepo(), newMockAssetRepo(), tm)
 	ctx := context.Background()
 
 	// CreateAccount should fail if SaveAccount fails (assuming Find failed or passed)
 	// In current impl, if Find fails, it tries Save. If Save fails, returns error.
-	_, err := uc.CreateAccount(ctx, false)
+	_, err := uc.CreateAccount(ctx, CreateAccountInput{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -175,24 +243,24 @@ func TestAccountUseCase_RepoError(t *testing.T) {
 func TestAccountUseCase_ListAccounts(t *testing.T) {
 	repo := newMockAccountRepo()
 	tm := &mockTxManager{}
-	uc := NewAccountUseCase(repo, tm)
+	uc := NewAccountUseCase(repo, newMockAccountChangeRepo(), newMockAssetRepo(), tm)
 	ctx := context.Background()
 
 	// Setup test accounts
 	acc1 := &domain.Account{
-		ID:           domain.AccountID(mustUUID("acc-1")),
-		Balance:      100,
-		CanOverdraft: false,
+		ID:          domain.AccountID(mustUUID("acc-1")),
+		Balance:     100,
+		CreditLimit: 0,
 	}
 	acc2 := &domain.Account{
-		ID:           domain.AccountID(mustUUID("acc-2")),
-		Balance:      500,
-		CanOverdraft: true,
+		ID:          domain.AccountID(mustUUID("acc-2")),
+		Balance:     500,
+		CreditLimit: domain.UnlimitedCreditLimit,
 	}
 	acc3 := &domain.Account{
-		ID:           domain.AccountID(mustUUID("acc-3")),
-		Balance:      50,
-		CanOverdraft: false,
+		ID:          domain.AccountID(mustUUID("acc-3")),
+		Balance:     50,
+		CreditLimit: 0,
 	}
 	repo.SaveAccount(ctx, acc1)
 	repo.SaveAccount(ctx, acc2)
@@ -258,3 +326,278 @@ func TestAccountUseCase_ListAccounts(t *testing.T) {
 		t.Errorf("expected 3 accounts, got %d", out.TotalCount)
 	}
 }
+
+func TestAccountUseCase_UpdateAccount(t *testing.T) {
+	repo := newMockAccountRepo()
+	changeRepo := newMockAccountChangeRepo()
+	tm := &mockTxManager{}
+	uc := NewAccountUseCase(repo, changeRepo, newMockAssetRepo(), tm)
+	ctx := context.Background()
+
+	testID := domain.AccountID(mustUUID("acc-update"))
+	acc := domain.NewAccount(testID, 500)
+	acc.Balance = -100
+	repo.SaveAccount(ctx, acc)
+
+	// 1. Lowering the credit limit below the current debt is refused
+	creditLimit := int64(50)
+	_, err := uc.UpdateAccount(ctx, UpdateAccountInput{
+		AccountID:   testID,
+		CreditLimit: &creditLimit,
+		ChangedBy:   "admin",
+	})
+	if err != domain.ErrCreditLimitBelowDebt {
+		t.Errorf("expected ErrCreditLimitBelowDebt, got %v", err)
+	}
+	if len(changeRepo.changes) != 0 {
+		t.Errorf("expected no history, got %d changes", len(changeRepo.changes))
+	}
+
+	// 2. Lowering the credit limit down to the current debt is allowed
+	creditLimit = 100
+	updated, err := uc.UpdateAccount(ctx, UpdateAccountInput{
+		AccountID:   testID,
+		CreditLimit: &creditLimit,
+		ChangedBy:   "admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.CreditLimit != 100 {
+		t.Errorf("expected credit limit 100, got %d", updated.CreditLimit)
+	}
+	if len(changeRepo.changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(changeRepo.changes))
+	}
+	change := changeRepo.changes[0]
+	if change.Field != domain.AccountFieldCreditLimit || change.OldValue != "500" || change.NewValue != "100" {
+		t.Errorf("unexpected change record: %+v", change)
+	}
+	if change.ChangedBy != "admin" {
+		t.Errorf("expected changed_by admin, got %s", change.ChangedBy)
+	}
+
+	// 3. Forced revoke succeeds and is recorded
+	creditLimit = 0
+	updated, err = uc.UpdateAccount(ctx, UpdateAccountInput{
+		AccountID:   testID,
+		CreditLimit: &creditLimit,
+		Force:       true,
+		ChangedBy:   "admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.CanOverdraft() {
+		t.Errorf("expected can_overdraft false")
+	}
+	if len(changeRepo.changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(changeRepo.changes))
+	}
+
+	// 4. No-op update records nothing
+	_, err = uc.UpdateAccount(ctx, UpdateAccountInput{
+		AccountID:   testID,
+		CreditLimit: &creditLimit,
+		ChangedBy:   "admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changeRepo.changes) != 2 {
+		t.Errorf("expected history unchanged, got %d changes", len(changeRepo.changes))
+	}
+
+	// 5. Missing actor
+	_, err = uc.UpdateAccount(ctx, UpdateAccountInput{AccountID: testID, CreditLimit: &creditLimit})
+	if err != domain.ErrInvalidChangedBy {
+		t.Errorf("expected ErrInvalidChangedBy, got %v", err)
+	}
+
+	// 6. Unknown account
+	_, err = uc.UpdateAccount(ctx, UpdateAccountInput{
+		AccountID:   domain.AccountID(mustUUID("acc-unknown")),
+		CreditLimit: &creditLimit,
+		ChangedBy:   "admin",
+	})
+	if err != domain.ErrAccountNotFound {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestAccountUseCase_SetAccountLabels(t *testing.T) {
+	repo := newMockAccountRepo()
+	changeRepo := newMockAccountChangeRepo()
+	tm := &mockTxManager{}
+	uc := NewAccountUseCase(repo, changeRepo, newMockAssetRepo(), tm)
+	ctx := context.Background()
+
+	acc, err := uc.CreateAccount(ctx, CreateAccountInput{
+		Labels: map[string]string{"kind": "wallet"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := uc.CreateAccount(ctx, CreateAccountInput{
+		Labels: map[string]string{"kind": "event-pool", "event": "2026"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 1. Upsert and remove
+	updated, err := uc.SetAccountLabels(ctx, SetAccountLabelsInput{
+		AccountID: acc.ID,
+		Set:       map[string]string{"kind": "shop-till", "shop": "cafe"},
+		ChangedBy: "admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Labels["kind"] != "shop-till" || updated.Labels["shop"] != "cafe" {
+		t.Errorf("unexpected labels: %v", updated.Labels)
+	}
+	if len(changeRepo.changes) != 2 {
+		t.Errorf("expected 2 changes, got %d", len(changeRepo.changes))
+	}
+
+	updated, err = uc.SetAccountLabels(ctx, SetAccountLabelsInput{
+		AccountID: acc.ID,
+		Remove:    []string{"shop"},
+		ChangedBy: "admin",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := updated.Labels["shop"]; ok {
+		t.Errorf("expected label shop to be removed")
+	}
+
+	// 2. Invalid key
+	_, err = uc.SetAccountLabels(ctx, SetAccountLabelsInput{
+		AccountID: acc.ID,
+		Set:       map[string]string{"Bad Key": "x"},
+		ChangedBy: "admin",
+	})
+	if err != domain.ErrInvalidLabel {
+		t.Errorf("expected ErrInvalidLabel, got %v", err)
+	}
+
+	// 3. Label selectors
+	out, err := uc.ListAccounts(ctx, ListAccountsInput{
+		Filter: domain.AccountFilter{LabelSelectors: []domain.LabelSelector{
+			{Key: "kind", Operator: domain.LabelOpIn, Values: []string{"shop-till", "event-pool"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.TotalCount != 2 {
+		t.Errorf("expected 2 accounts, got %d", out.TotalCount)
+	}
+
+	out, err = uc.ListAccounts(ctx, ListAccountsInput{
+		Filter: domain.AccountFilter{LabelSelectors: []domain.LabelSelector{
+			{Key: "event", Operator: domain.LabelOpExists},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.TotalCount != 1 || out.Accounts[0].ID != other.ID {
+		t.Errorf("expected only the event account, got %d accounts", out.TotalCount)
+	}
+
+	// 4. Malformed selector
+	_, err = uc.ListAccounts(ctx, ListAccountsInput{
+		Filter: domain.AccountFilter{LabelSelectors: []domain.LabelSelector{
+			{Key: "kind", Operator: domain.LabelOpEquals},
+		}},
+	})
+	if err != domain.ErrInvalidLabelSelector {
+		t.Errorf("expected ErrInvalidLabelSelector, got %v", err)
+	}
+}
+
+func TestAccountUseCase_ListAccountsByOwner(t *testing.T) {
+	repo := newMockAccountRepo()
+	tm := &mockTxManager{}
+	uc := NewAccountUseCase(repo, newMockAccountChangeRepo(), newMockAssetRepo(), tm)
+	ctx := context.Background()
+
+	for _, owner := range []string{"alice", "alice", "bob", ""} {
+		if _, err := uc.CreateAccount(ctx, CreateAccountInput{OwnerID: owner}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	out, err := uc.ListAccountsByOwner(ctx, "alice", 100, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.TotalCount != 2 {
+		t.Errorf("expected 2 accounts for alice, got %d", out.TotalCount)
+	}
+	for _, acc := range out.Accounts {
+		if acc.OwnerID != "alice" {
+			t.Errorf("expected owner alice, got %q", acc.OwnerID)
+		}
+	}
+
+	if _, err := uc.ListAccountsByOwner(ctx, "", 100, 0); err != domain.ErrInvalidOwnerID {
+		t.Errorf("expected ErrInvalidOwnerID, got %v", err)
+	}
+}
+
+func TestAccountUseCase_Alias(t *testing.T) {
+	repo := newMockAccountRepo()
+	changeRepo := newMockAccountChangeRepo()
+	uc := NewAccountUseCase(repo, changeRepo, newMockAssetRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	pool, err := uc.CreateAccount(ctx, CreateAccountInput{Alias: "event-2026-pool"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := uc.CreateAccount(ctx, CreateAccountInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := uc.CreateAccount(ctx, CreateAccountInput{Alias: "event-2026-pool"}); err != domain.ErrAliasTaken {
+		t.Errorf("expected ErrAliasTaken, got %v", err)
+	}
+	if _, err := uc.CreateAccount(ctx, CreateAccountInput{Alias: "Event Pool"}); err != domain.ErrInvalidAlias {
+		t.Errorf("expected ErrInvalidAlias, got %v", err)
+	}
+
+	taken := "event-2026-pool"
+	if _, err := uc.UpdateAccount(ctx, UpdateAccountInput{AccountID: other.ID, Alias: &taken, ChangedBy: "admin"}); err != domain.ErrAliasTaken {
+		t.Errorf("expected ErrAliasTaken, got %v", err)
+	}
+
+	// Rename frees the old alias
+	renamed := "event-2026-main"
+	if _, err := uc.UpdateAccount(ctx, UpdateAccountInput{AccountID: pool.ID, Alias: &renamed, ChangedBy: "admin"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.UpdateAccount(ctx, UpdateAccountInput{AccountID: other.ID, Alias: &taken, ChangedBy: "admin"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acc, err := uc.ResolveAlias(ctx, "event-2026-main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.ID != pool.ID {
+		t.Errorf("expected %s, got %s", pool.ID, acc.ID)
+	}
+	if _, err := uc.ResolveAlias(ctx, "missing"); err != domain.ErrAccountNotFound {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+
+	last := changeRepo.changes[len(changeRepo.changes)-1]
+	if last.Field != domain.AccountFieldAlias || last.OldValue != "" || last.NewValue != taken {
+		t.Errorf("unexpected alias change record: %+v", last)
+	}
+}
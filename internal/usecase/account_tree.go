@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// GetAccountTree returns the account with all of its sub-accounts and their rolled-up balances.
+func (u *AccountUseCase) GetAccountTree(ctx context.Context, id domain.AccountID) (*domain.AccountTreeNode, error) {
+	root, err := u.accountRepo.FindAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+
+	// Load the tree level by level. The depth limit bounds the number of queries.
+	var descendants []*domain.Account
+	level := []domain.AccountID{root.ID}
+	for depth := 1; depth < domain.MaxAccountTreeDepth && len(level) > 0; depth++ {
+		children, err := u.accountRepo.FindAccountsByParentIDs(ctx, level)
+		if err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, children...)
+		level = level[:0]
+		for _, child := range children {
+			level = append(level, child.ID)
+		}
+	}
+
+	return domain.BuildAccountTree(root, descendants)
+}
+
+// validateParent checks that a sub-account can be created under parentID and returns the parent.
+func (u *AccountUseCase) validateParent(ctx context.Context, parentID domain.AccountID) (*domain.Account, error) {
+	parent, err := u.accountRepo.FindAccountByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if parent.Status == domain.AccountStatusClosed {
+		return nil, domain.ErrAccountClosed
+	}
+
+	ancestors, err := accountAncestors(ctx, u.accountRepo, parent)
+	if err != nil {
+		return nil, err
+	}
+	// parent, its ancestors and the new account itself
+	if len(ancestors)+2 > domain.MaxAccountTreeDepth {
+		return nil, domain.ErrAccountTreeTooDeep
+	}
+	return parent, nil
+}
+
+// accountAncestors returns the ancestors of acc, nearest first.
+func accountAncestors(ctx context.Context, accountRepo domain.AccountRepository, acc *domain.Account) ([]*domain.Account, error) {
+	var ancestors []*domain.Account
+	for acc.ParentID != nil && len(ancestors) < domain.MaxAccountTreeDepth {
+		parent, err := accountRepo.FindAccountByID(ctx, *acc.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, domain.ErrAccountNotFound
+		}
+		ancestors = append(ancestors, parent)
+		acc = parent
+	}
+	return ancestors, nil
+}
+
+// accountTreeRoot returns the ID of the top-level account of acc's tree.
+func accountTreeRoot(ctx context.Context, accountRepo domain.AccountRepository, acc *domain.Account) (domain.AccountID, error) {
+	ancestors, err := accountAncestors(ctx, accountRepo, acc)
+	if err != nil {
+		return domain.AccountID{}, err
+	}
+	if len(ancestors) == 0 {
+		return acc.ID, nil
+	}
+	return ancestors[len(ancestors)-1].ID, nil
+}
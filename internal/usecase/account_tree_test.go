@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+func TestAccountUseCase_GetAccountTree(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	assetRepo := newMockAssetRepo()
+	assetRepo.SaveAsset(context.Background(), &domain.Asset{Code: "club", Name: "Club Point"})
+	uc := NewAccountUseCase(accRepo, newMockAccountChangeRepo(), assetRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	root, err := uc.CreateAccount(ctx, CreateAccountInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	child, err := uc.CreateAccount(ctx, CreateAccountInput{ParentID: &root.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	grandchild, err := uc.CreateAccount(ctx, CreateAccountInput{ParentID: &child.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grandchild.Asset != root.Asset {
+		t.Errorf("expected sub-account to inherit asset %s, got %s", root.Asset, grandchild.Asset)
+	}
+
+	if _, err := uc.CreateAccount(ctx, CreateAccountInput{ParentID: &root.ID, Asset: "club"}); err != domain.ErrAssetMismatch {
+		t.Errorf("expected ErrAssetMismatch, got %v", err)
+	}
+
+	root.Balance = 100
+	child.Balance = 30
+	grandchild.Balance = 5
+
+	tree, err := uc.GetAccountTree(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tree.RolledUpBalance != 135 {
+		t.Errorf("expected rolled-up balance 135, got %d", tree.RolledUpBalance)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].RolledUpBalance != 35 {
+		t.Errorf("expected one child with rolled-up balance 35, got %+v", tree.Children)
+	}
+
+	// Grow the chain to the depth limit
+	parent := grandchild
+	for depth := 3; depth < domain.MaxAccountTreeDepth; depth++ {
+		parent, err = uc.CreateAccount(ctx, CreateAccountInput{ParentID: &parent.ID})
+		if err != nil {
+			t.Fatalf("unexpected error at depth %d: %v", depth+1, err)
+		}
+	}
+	if _, err := uc.CreateAccount(ctx, CreateAccountInput{ParentID: &parent.ID}); err != domain.ErrAccountTreeTooDeep {
+		t.Errorf("expected ErrAccountTreeTooDeep, got %v", err)
+	}
+}
+
+func TestTransferUseCase_Transfer_IntraTreePolicy(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	ctx := context.Background()
+
+	newAcc := func(name string, parent *domain.Account) *domain.Account {
+		acc := domain.NewAccount(domain.AccountID(mustUUID(name)), 0)
+		acc.Balance = 1000
+		if parent != nil {
+			acc.ParentID = &parent.ID
+		}
+		accRepo.SaveAccount(ctx, acc)
+		return acc
+	}
+	root := newAcc("root", nil)
+	eventA := newAcc("event-a", root)
+	eventB := newAcc("event-b", root)
+	outsider := newAcc("outsider", nil)
+
+	tests := []struct {
+		name     string
+		policy   IntraTreeTransferPolicy
+		from, to *domain.Account
+		wantErr  error
+	}{
+		{"allow siblings", IntraTreeTransfersAllowed, eventA, eventB, nil},
+		{"deny parent to child", IntraTreeTransfersDenied, root, eventA, domain.ErrIntraTreeTransfer},
+		{"deny allows other trees", IntraTreeTransfersDenied, eventA, outsider, nil},
+		{"parent_child allows parent to child", IntraTreeTransfersParentChild, root, eventA, nil},
+		{"parent_child allows child to parent", IntraTreeTransfersParentChild, eventB, root, nil},
+		{"parent_child denies siblings", IntraTreeTransfersParentChild, eventA, eventB, domain.ErrIntraTreeTransfer},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{}, WithIntraTreeTransferPolicy(tt.policy))
+			_, err := uc.Transfer(ctx, TransferInput{
+				FromAccountID:  tt.from.ID,
+				ToAccountID:    tt.to.ID,
+				Amount:         1,
+				IdempotencyKey: fmt.Sprintf("tree-%d", i),
+			})
+			if err != tt.wantErr {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseIntraTreeTransferPolicy(t *testing.T) {
+	if p, err := ParseIntraTreeTransferPolicy(""); err != nil || p != IntraTreeTransfersAllowed {
+		t.Errorf("expected default allow, got %q, %v", p, err)
+	}
+	if _, err := ParseIntraTreeTransferPolicy("sometimes"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
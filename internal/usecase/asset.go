@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type AssetUseCase struct {
+	assetRepo   domain.AssetRepository
+	accountRepo domain.AccountRepository
+	tm          domain.TransactionManager
+}
+
+func NewAssetUseCase(
+	assetRepo domain.AssetRepository,
+	accountRepo domain.AccountRepository,
+	tm domain.TransactionManager,
+) *AssetUseCase {
+	return &AssetUseCase{
+		assetRepo:   assetRepo,
+		accountRepo: accountRepo,
+		tm:          tm,
+	}
+}
+
+// CreateAsset registers a new point type. It fails with ErrAssetAlreadyExists if the code is taken.
+func (u *AssetUseCase) CreateAsset(ctx context.Context, code domain.AssetCode, name string, precision int) (*domain.Asset, error) {
+	asset, err := domain.NewAsset(code, name, precision)
+	if err != nil {
+		return nil, err
+	}
+	// A plain insert, so that concurrent creates of the same code cannot overwrite each other.
+	if err := u.assetRepo.CreateAsset(ctx, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (u *AssetUseCase) GetAsset(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	return asset, nil
+}
+
+func (u *AssetUseCase) ListAssets(ctx context.Context) ([]*domain.Asset, error) {
+	return u.assetRepo.ListAssets(ctx)
+}
+
+// SetAssetIssuer designates the account that Mint debits and Burn credits.
+// The account must be an active, empty account holding the asset. It becomes a system account
+// with an unlimited credit limit. The issuer cannot change while points are in circulation,
+// since the old issuer's balance would no longer be accounted for by the supply.
+func (u *AssetUseCase) SetAssetIssuer(ctx context.Context, code domain.AssetCode, issuerID domain.AccountID) (*domain.Asset, error) {
+	var asset *domain.Asset
+
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		asset, err = u.assetRepo.GetAssetForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+		if asset == nil {
+			return domain.ErrAssetNotFound
+		}
+		if asset.IssuerAccountID != nil && *asset.IssuerAccountID == issuerID {
+			return nil
+		}
+		if asset.IssuerAccountID != nil && asset.Supply != 0 {
+			return domain.ErrIssuerInUse
+		}
+
+		issuer, err := u.accountRepo.GetAccountForUpdate(ctx, issuerID)
+		if err != nil {
+			return err
+		}
+		if issuer == nil {
+			return domain.ErrAccountNotFound
+		}
+		if issuer.Asset != asset.Code {
+			return domain.ErrAssetMismatch
+		}
+		if err := checkIssuerCandidate(issuer); err != nil {
+			return err
+		}
+
+		if asset.IssuerAccountID != nil {
+			previous, err := u.accountRepo.GetAccountForUpdate(ctx, *asset.IssuerAccountID)
+			if err != nil {
+				return err
+			}
+			if previous != nil {
+				previous.System = false
+				previous.CreditLimit = 0
+				if err := u.accountRepo.SaveAccount(ctx, previous); err != nil {
+					return err
+				}
+			}
+		}
+
+		issuer.System = true
+		issuer.CreditLimit = domain.UnlimitedCreditLimit
+		issuer.MaxBalance = nil
+		if err := u.accountRepo.SaveAccount(ctx, issuer); err != nil {
+			return err
+		}
+		asset.IssuerAccountID = &issuerID
+		return u.assetRepo.SaveAsset(ctx, asset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// checkIssuerCandidate checks that the locked account can become an issuer.
+func checkIssuerCandidate(acc *domain.Account) error {
+	switch {
+	case acc.System:
+		return domain.ErrSystemAccount
+	case acc.Status == domain.AccountStatusClosed:
+		return domain.ErrAccountClosed
+	case acc.Status == domain.AccountStatusFrozen:
+		return domain.ErrAccountFrozen
+	case acc.Balance != 0 || acc.HeldBalance != 0:
+		return domain.ErrAccountNotEmpty
+	}
+	return nil
+}
+
+// SetAssetPointTTL makes points of the asset credited from now on expire after days.
+// Zero turns expiry off for new credits. Expiring points requires an issuer to return them to.
+func (u *AssetUseCase) SetAssetPointTTL(ctx context.Context, code domain.AssetCode, days int) (*domain.Asset, error) {
+	var asset *domain.Asset
+
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		asset, err = u.assetRepo.GetAssetForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+		if asset == nil {
+			return domain.ErrAssetNotFound
+		}
+		if err := asset.SetPointTTL(days); err != nil {
+			return err
+		}
+		return u.assetRepo.SaveAsset(ctx, asset)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockAssetRepo struct {
+	assets map[domain.AssetCode]*domain.Asset
+}
+
+// newMockAssetRepo returns a registry holding only the default asset.
+func newMockAssetRepo() *mockAssetRepo {
+	return &mockAssetRepo{
+		assets: map[domain.AssetCode]*domain.Asset{
+			domain.DefaultAssetCode: {Code: domain.DefaultAssetCode, Name: "Point"},
+		},
+	}
+}
+
+func (m *mockAssetRepo) CreateAsset(ctx context.Context, asset *domain.Asset) error {
+	if _, ok := m.assets[asset.Code]; ok {
+		return domain.ErrAssetAlreadyExists
+	}
+	return m.SaveAsset(ctx, asset)
+}
+
+func (m *mockAssetRepo) SaveAsset(ctx context.Context, asset *domain.Asset) error {
+	m.assets[asset.Code] = asset
+	return nil
+}
+
+func (m *mockAssetRepo) FindAssetByCode(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	if asset, ok := m.assets[code]; ok {
+		return asset, nil
+	}
+	return nil, nil
+}
+
+func (m *mockAssetRepo) GetAssetForUpdate(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	return m.FindAssetByCode(ctx, code)
+}
+
+func (m *mockAssetRepo) ListAssets(ctx context.Context) ([]*domain.Asset, error) {
+	var result []*domain.Asset
+	for _, asset := range m.assets {
+		result = append(result, asset)
+	}
+	return result, nil
+}
+
+func TestAssetUseCase_CreateAsset(t *testing.T) {
+	repo := newMockAssetRepo()
+	tm := &mockTxManager{}
+	uc := NewAssetUseCase(repo, newMockAccountRepo(), tm)
+	ctx := context.Background()
+
+	asset, err := uc.CreateAsset(ctx, "event-ticket", "Event Ticket", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.Code != "event-ticket" {
+		t.Errorf("expected code event-ticket, got %s", asset.Code)
+	}
+
+	if _, err := uc.CreateAsset(ctx, "event-ticket", "Duplicate", 0); err != domain.ErrAssetAlreadyExists {
+		t.Errorf("expected ErrAssetAlreadyExists, got %v", err)
+	}
+	if got, _ := uc.GetAsset(ctx, "event-ticket"); got != asset {
+		t.Errorf("a duplicate create must not overwrite the asset, got %+v", got)
+	}
+	if _, err := uc.GetAsset(ctx, "unknown"); err != domain.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+	if _, err := uc.CreateAsset(ctx, "Bad Code", "Bad", 0); err != domain.ErrInvalidAsset {
+		t.Errorf("expected ErrInvalidAsset, got %v", err)
+	}
+	if _, err := uc.CreateAsset(ctx, "club", "Club", domain.MaxAssetPrecision+1); err != domain.ErrInvalidAsset {
+		t.Errorf("expected ErrInvalidAsset, got %v", err)
+	}
+}
+
+func TestAccountUseCase_CreateAccount_Asset(t *testing.T) {
+	assetRepo := newMockAssetRepo()
+	assetRepo.SaveAsset(context.Background(), &domain.Asset{Code: "club", Name: "Club Point"})
+	uc := NewAccountUseCase(newMockAccountRepo(), newMockAccountChangeRepo(), assetRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	acc, err := uc.CreateAccount(ctx, CreateAccountInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Asset != domain.DefaultAssetCode {
+		t.Errorf("expected default asset, got %s", acc.Asset)
+	}
+
+	acc, err = uc.CreateAccount(ctx, CreateAccountInput{Asset: "club"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Asset != "club" {
+		t.Errorf("expected asset club, got %s", acc.Asset)
+	}
+
+	if _, err := uc.CreateAccount(ctx, CreateAccountInput{Asset: "unknown"}); err != domain.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+
+	// ListAccounts filters by asset
+	club := domain.AssetCode("club")
+	out, err := uc.ListAccounts(ctx, ListAccountsInput{Filter: domain.AccountFilter{Asset: &club}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.TotalCount != 1 {
+		t.Errorf("expected 1 club account, got %d", out.TotalCount)
+	}
+}
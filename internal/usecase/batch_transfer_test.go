@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+func TestTransferUseCase_BatchTransfer(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	buyerID := domain.AccountID(mustUUID("buyer"))
+	shopID := domain.AccountID(mustUUID("shop"))
+	taxID := domain.AccountID(mustUUID("tax"))
+	buyer := domain.NewAccount(buyerID, 0)
+	buyer.Balance = 1000
+	accRepo.SaveAccount(ctx, buyer)
+	accRepo.SaveAccount(ctx, domain.NewAccount(shopID, 0))
+	accRepo.SaveAccount(ctx, domain.NewAccount(taxID, 0))
+
+	input := BatchTransferInput{
+		Legs: []TransferLeg{
+			{FromAccountID: buyerID, ToAccountID: shopID, Amount: 500},
+			{FromAccountID: buyerID, ToAccountID: taxID, Amount: 50, Description: "tax"},
+			// The shop forwards part of what it just received
+			{FromAccountID: shopID, ToAccountID: taxID, Amount: 20},
+		},
+		Description:    "purchase",
+		IdempotencyKey: "batch-1",
+	}
+
+	out, err := uc.BatchTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.JournalEntryIDs) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(out.JournalEntryIDs))
+	}
+
+	wantBalances := map[domain.AccountID]int64{buyerID: 450, shopID: 480, taxID: 70}
+	for id, want := range wantBalances {
+		acc, _ := accRepo.FindAccountByID(ctx, id)
+		if acc.Balance != want {
+			t.Errorf("account %s: expected balance %d, got %d", id, want, acc.Balance)
+		}
+	}
+
+	group := txRepo.groups["batch-1"]
+	for i, entry := range group.Entries {
+		if entry.GroupID == nil || *entry.GroupID != out.GroupID {
+			t.Errorf("leg %d: expected group %s, got %v", i, out.GroupID, entry.GroupID)
+		}
+		if entry.IdempotencyKey != domain.LegIdempotencyKey("batch-1", i) {
+			t.Errorf("leg %d: unexpected idempotency key %q", i, entry.IdempotencyKey)
+		}
+		if !entry.ValidateHash() {
+			t.Errorf("leg %d: invalid hash", i)
+		}
+		if i > 0 && entry.PreviousHash != group.Entries[i-1].Hash {
+			t.Errorf("leg %d: not chained to the previous leg", i)
+		}
+	}
+	if group.Entries[0].Description != "purchase" || group.Entries[1].Description != "tax" {
+		t.Errorf("unexpected leg descriptions %q, %q", group.Entries[0].Description, group.Entries[1].Description)
+	}
+
+	// Replaying returns the same group without applying it again
+	out2, err := uc.BatchTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if out2.GroupID != out.GroupID {
+		t.Errorf("expected group %s, got %s", out.GroupID, out2.GroupID)
+	}
+	if buyer.Balance != 450 {
+		t.Errorf("balance changed on idempotent call: %d", buyer.Balance)
+	}
+
+	// A client key cannot address a leg of the group
+	_, err = uc.Transfer(ctx, TransferInput{FromAccountID: buyerID, ToAccountID: shopID, Amount: 1, IdempotencyKey: "batch-1#0"})
+	if err != domain.ErrInvalidIdempotencyKey {
+		t.Errorf("expected ErrInvalidIdempotencyKey, got %v", err)
+	}
+}
+
+func TestTransferUseCase_BatchTransfer_Invalid(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	aID := domain.AccountID(mustUUID("a"))
+	bID := domain.AccountID(mustUUID("b"))
+	a := domain.NewAccount(aID, 0)
+	a.Balance = 100
+	accRepo.SaveAccount(ctx, a)
+	accRepo.SaveAccount(ctx, domain.NewAccount(bID, 0))
+
+	if _, err := uc.BatchTransfer(ctx, BatchTransferInput{IdempotencyKey: "empty"}); err != domain.ErrInvalidBatchSize {
+		t.Errorf("expected ErrInvalidBatchSize, got %v", err)
+	}
+
+	tooMany := make([]TransferLeg, MaxBatchLegs+1)
+	for i := range tooMany {
+		tooMany[i] = TransferLeg{FromAccountID: aID, ToAccountID: bID, Amount: 1}
+	}
+	if _, err := uc.BatchTransfer(ctx, BatchTransferInput{Legs: tooMany, IdempotencyKey: "many"}); err != domain.ErrInvalidBatchSize {
+		t.Errorf("expected ErrInvalidBatchSize, got %v", err)
+	}
+
+	_, err := uc.BatchTransfer(ctx, BatchTransferInput{
+		Legs: []TransferLeg{
+			{FromAccountID: aID, ToAccountID: bID, Amount: 60},
+			{FromAccountID: aID, ToAccountID: bID, Amount: 60},
+		},
+		IdempotencyKey: "overspend",
+	})
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if len(txRepo.txs) != 0 || len(txRepo.groups) != 0 {
+		t.Errorf("expected nothing to be journaled, got %d entries and %d groups", len(txRepo.txs), len(txRepo.groups))
+	}
+
+	_, err = uc.BatchTransfer(ctx, BatchTransferInput{
+		Legs:           []TransferLeg{{FromAccountID: aID, ToAccountID: aID, Amount: 1}},
+		IdempotencyKey: "self",
+	})
+	if !errors.Is(err, domain.ErrSelfTransfer) {
+		t.Errorf("expected ErrSelfTransfer, got %v", err)
+	}
+}
+
+func TestTransferUseCase_SplitTransfer(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	poolID := domain.AccountID(mustUUID("pool"))
+	firstID := domain.AccountID(mustUUID("first"))
+	secondID := domain.AccountID(mustUUID("second"))
+	thirdID := domain.AccountID(mustUUID("third"))
+	pool := domain.NewAccount(poolID, 0)
+	pool.Balance = 1000
+	accRepo.SaveAccount(ctx, pool)
+	for _, id := range []domain.AccountID{firstID, secondID, thirdID} {
+		accRepo.SaveAccount(ctx, domain.NewAccount(id, 0))
+	}
+
+	input := SplitTransferInput{
+		FromAccountID: poolID,
+		Amount:        100,
+		Recipients: []SplitRecipient{
+			{AccountID: firstID, Weight: 3},
+			{AccountID: secondID, Weight: 2},
+			{AccountID: thirdID, Weight: 2},
+		},
+		Description:    "prize",
+		IdempotencyKey: "split-1",
+	}
+	out, err := uc.SplitTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 42.86, 28.57 and 28.57: the first share has the largest remainder
+	want := map[domain.AccountID]int64{firstID: 43, secondID: 29, thirdID: 28}
+	for i, share := range out.Shares {
+		if share.AccountID != input.Recipients[i].AccountID || share.Amount != want[share.AccountID] || share.JournalEntryID == nil {
+			t.Errorf("share %d: unexpected %+v", i, share)
+		}
+		acc, _ := accRepo.FindAccountByID(ctx, share.AccountID)
+		if acc.Balance != want[share.AccountID] {
+			t.Errorf("share %d: expected balance %d, got %d", i, want[share.AccountID], acc.Balance)
+		}
+	}
+	if pool.Balance != 900 {
+		t.Errorf("expected pool balance 900, got %d", pool.Balance)
+	}
+
+	replay, err := uc.SplitTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on replay: %v", err)
+	}
+	if replay.GroupID != out.GroupID || pool.Balance != 900 {
+		t.Errorf("replay should return the original split without paying again")
+	}
+
+	input.IdempotencyKey = "split-2"
+	input.Recipients = append(input.Recipients, SplitRecipient{AccountID: firstID, Weight: 1})
+	if _, err := uc.SplitTransfer(ctx, input); !errors.Is(err, domain.ErrDuplicateSplitRecipient) {
+		t.Errorf("expected ErrDuplicateSplitRecipient, got %v", err)
+	}
+}
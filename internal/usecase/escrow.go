@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+const (
+	// MaxEscrowDuration is the latest an escrow deadline may be set
+	MaxEscrowDuration = 365 * 24 * time.Hour
+	// expiredEscrowsBatchSize is the maximum number of escrows RefundExpired refunds per call
+	expiredEscrowsBatchSize = 100
+)
+
+// EscrowUseCase locks points in escrow until they are released or refunded.
+type EscrowUseCase struct {
+	accountRepo domain.AccountRepository
+	repo        domain.JournalEntryRepository
+	assetRepo   domain.AssetRepository
+	escrowRepo  domain.EscrowRepository
+	tm          domain.TransactionManager
+	lots        *PointLotUseCase
+	// limits, if not nil, enforces the velocity limits of transfers on escrow funding.
+	limits *TransferUseCase
+}
+
+// EscrowOption configures an EscrowUseCase.
+type EscrowOption func(*EscrowUseCase)
+
+// WithEscrowVelocityLimits checks the funding of escrows against the velocity limits transferUC
+// enforces on transfers, so that escrowing and releasing cannot send more than a transfer could.
+func WithEscrowVelocityLimits(transferUC *TransferUseCase) EscrowOption {
+	return func(u *EscrowUseCase) {
+		u.limits = transferUC
+	}
+}
+
+func NewEscrowUseCase(
+	accountRepo domain.AccountRepository,
+	repo domain.JournalEntryRepository,
+	assetRepo domain.AssetRepository,
+	escrowRepo domain.EscrowRepository,
+	tm domain.TransactionManager,
+	// lots tracks expiring points. Nil disables point expiry.
+	lots *PointLotUseCase,
+	opts ...EscrowOption,
+) *EscrowUseCase {
+	u := &EscrowUseCase{
+		accountRepo: accountRepo,
+		repo:        repo,
+		assetRepo:   assetRepo,
+		escrowRepo:  escrowRepo,
+		tm:          tm,
+		lots:        lots,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+type CreateEscrowInput struct {
+	PayerAccountID       domain.AccountID
+	BeneficiaryAccountID domain.AccountID
+	Amount               int64
+	Description          string
+	Deadline             time.Time
+	IdempotencyKey       string
+}
+
+// EscrowOutput is the escrow with the journal entry that funded, released or refunded it.
+type EscrowOutput struct {
+	Escrow         *domain.Escrow
+	JournalEntryID domain.JournalEntryID
+	CreatedAt      time.Time
+}
+
+// CreateEscrow moves points from the payer into the escrow account of the payer's asset.
+// Replays of the key with other accounts, amount, description or deadline fail with
+// an *IdempotencyKeyReusedError.
+func (u *EscrowUseCase) CreateEscrow(ctx context.Context, input CreateEscrowInput) (*EscrowOutput, error) {
+	m := movement{
+		Type:           domain.EntryTypeEscrow,
+		FromAccountID:  input.PayerAccountID,
+		ToAccountID:    input.BeneficiaryAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+		Fingerprint: domain.RequestFingerprint("escrow", input.PayerAccountID, input.BeneficiaryAccountID,
+			input.Amount, input.Description, input.Deadline),
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !input.Deadline.After(now) || input.Deadline.After(now.Add(MaxEscrowDuration)) {
+		return nil, domain.ErrInvalidEscrowDeadline
+	}
+
+	existing, err := u.escrowRepo.FindEscrowByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return u.output(ctx, existing.ID, m)
+	}
+
+	payer, err := u.accountRepo.FindAccountByID(ctx, input.PayerAccountID)
+	if err != nil {
+		return nil, err
+	}
+	beneficiary, err := u.accountRepo.FindAccountByID(ctx, input.BeneficiaryAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if payer == nil || beneficiary == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if payer.Asset != beneficiary.Asset {
+		return nil, domain.ErrAssetMismatch
+	}
+	escrowAccountID, err := u.escrowAccountOf(ctx, payer.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	escrowID := domain.EscrowID(id)
+
+	m.ToAccountID = escrowAccountID
+	m.EscrowID = &escrowID
+	entry, err := u.poster().post(ctx, m, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		if u.limits != nil {
+			if err := u.limits.checkVelocityLimits(ctx, from, input.Amount); err != nil {
+				return err
+			}
+		}
+		return u.escrowRepo.SaveEscrow(ctx, &domain.Escrow{
+			ID:                   escrowID,
+			PayerAccountID:       input.PayerAccountID,
+			BeneficiaryAccountID: input.BeneficiaryAccountID,
+			EscrowAccountID:      escrowAccountID,
+			Amount:               input.Amount,
+			Description:          input.Description,
+			Status:               domain.EscrowStatusHeld,
+			Deadline:             input.Deadline,
+			IdempotencyKey:       input.IdempotencyKey,
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entry.EscrowID == nil {
+		// The key was already used by an entry that is not an escrow.
+		return nil, &domain.IdempotencyKeyReusedError{Key: input.IdempotencyKey, JournalEntryID: entry.ID}
+	}
+
+	// A concurrent request with the same key may have won; report its escrow.
+	return u.output(ctx, *entry.EscrowID, m)
+}
+
+// ReleaseEscrow pays the escrowed points to the beneficiary. It fails once the deadline has passed.
+func (u *EscrowUseCase) ReleaseEscrow(ctx context.Context, id domain.EscrowID) (*EscrowOutput, error) {
+	return u.settle(ctx, id, true)
+}
+
+// RefundEscrow pays the escrowed points back to the payer.
+func (u *EscrowUseCase) RefundEscrow(ctx context.Context, id domain.EscrowID) (*EscrowOutput, error) {
+	return u.settle(ctx, id, false)
+}
+
+// RefundExpired refunds the held escrows whose deadline is at or before now and returns how many were refunded.
+// An escrow that cannot be refunded, e.g. because the payer was closed, does not hold up the others;
+// its error is returned together with those of the other failed refunds.
+func (u *EscrowUseCase) RefundExpired(ctx context.Context, now time.Time) (int, error) {
+	escrows, err := u.escrowRepo.FindExpiredEscrows(ctx, now, expiredEscrowsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	var errs []error
+	for _, escrow := range escrows {
+		if _, err := u.settle(ctx, escrow.ID, false); err != nil {
+			// Released or refunded in the meantime
+			if errors.Is(err, domain.ErrEscrowNotHeld) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("escrow %s: %w", escrow.ID, err))
+			continue
+		}
+		refunded++
+	}
+	return refunded, errors.Join(errs...)
+}
+
+func (u *EscrowUseCase) GetEscrow(ctx context.Context, id domain.EscrowID) (*domain.Escrow, error) {
+	escrow, err := u.escrowRepo.FindEscrowByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if escrow == nil {
+		return nil, domain.ErrEscrowNotFound
+	}
+	return escrow, nil
+}
+
+// settle moves the escrowed points to the beneficiary (release) or back to the payer.
+// Release and refund post with different keys, so retrying one never returns the other's entry.
+func (u *EscrowUseCase) settle(ctx context.Context, id domain.EscrowID, release bool) (*EscrowOutput, error) {
+	escrow, err := u.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	m := movement{
+		Type:           domain.EntryTypeEscrow,
+		FromAccountID:  escrow.EscrowAccountID,
+		ToAccountID:    escrow.PayerAccountID,
+		Amount:         escrow.Amount,
+		Description:    escrow.Description,
+		IdempotencyKey: escrow.RefundIdempotencyKey(),
+		EscrowID:       &escrow.ID,
+		Fingerprint:    domain.RequestFingerprint("escrow_refund", escrow.ID),
+	}
+	if release {
+		m.ToAccountID = escrow.BeneficiaryAccountID
+		m.IdempotencyKey = escrow.ReleaseIdempotencyKey()
+		m.Fingerprint = domain.RequestFingerprint("escrow_release", escrow.ID)
+	}
+
+	_, err = u.poster().post(ctx, m, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		locked, err := u.escrowRepo.GetEscrowForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if release {
+			err = locked.Release(time.Now())
+		} else {
+			err = locked.Refund(time.Now())
+		}
+		if err != nil {
+			return err
+		}
+		return u.escrowRepo.SaveEscrow(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.output(ctx, id, m)
+}
+
+// output loads the escrow and the journal entry posted with the key of m,
+// which must have been posted by the request of m.
+func (u *EscrowUseCase) output(ctx context.Context, id domain.EscrowID, m movement) (*EscrowOutput, error) {
+	escrow, err := u.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := u.repo.FindByIdempotencyKey(ctx, m.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, domain.ErrJournalEntryNotFound
+	}
+	if err := checkReplay(m.IdempotencyKey, m.Fingerprint, entry.RequestFingerprint, entry.ID); err != nil {
+		return nil, err
+	}
+	return &EscrowOutput{
+		Escrow:         escrow,
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	}, nil
+}
+
+// escrowAccountOf returns the escrow account of the asset, creating it on first use.
+func (u *EscrowUseCase) escrowAccountOf(ctx context.Context, code domain.AssetCode) (domain.AccountID, error) {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, code)
+	if err != nil {
+		return domain.AccountID{}, err
+	}
+	if asset == nil {
+		return domain.AccountID{}, domain.ErrAssetNotFound
+	}
+	if asset.EscrowAccountID != nil {
+		return *asset.EscrowAccountID, nil
+	}
+
+	var escrowAccountID domain.AccountID
+	err = u.tm.Run(ctx, func(ctx context.Context) error {
+		asset, err := u.assetRepo.GetAssetForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+		if asset == nil {
+			return domain.ErrAssetNotFound
+		}
+		// Created by a concurrent request
+		if asset.EscrowAccountID != nil {
+			escrowAccountID = *asset.EscrowAccountID
+			return nil
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		acc := domain.NewAccount(domain.AccountID(id), 0)
+		acc.Asset = code
+		// Only escrow entries may move the escrowed points.
+		acc.System = true
+		if err := u.accountRepo.SaveAccount(ctx, acc); err != nil {
+			return err
+		}
+		asset.EscrowAccountID = &acc.ID
+		escrowAccountID = acc.ID
+		return u.assetRepo.SaveAsset(ctx, asset)
+	})
+	if err != nil {
+		return domain.AccountID{}, err
+	}
+	return escrowAccountID, nil
+}
+
+func (u *EscrowUseCase) poster() *poster {
+	return &poster{accountRepo: u.accountRepo, repo: u.repo, tm: u.tm, lots: u.lots}
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockEscrowRepo struct {
+	escrows map[domain.EscrowID]*domain.Escrow
+}
+
+func newMockEscrowRepo() *mockEscrowRepo {
+	return &mockEscrowRepo{escrows: make(map[domain.EscrowID]*domain.Escrow)}
+}
+
+func (m *mockEscrowRepo) SaveEscrow(ctx context.Context, escrow *domain.Escrow) error {
+	m.escrows[escrow.ID] = escrow
+	return nil
+}
+
+func (m *mockEscrowRepo) FindEscrowByID(ctx context.Context, id domain.EscrowID) (*domain.Escrow, error) {
+	return m.escrows[id], nil
+}
+
+func (m *mockEscrowRepo) GetEscrowForUpdate(ctx context.Context, id domain.EscrowID) (*domain.Escrow, error) {
+	return m.escrows[id], nil
+}
+
+func (m *mockEscrowRepo) FindEscrowByIdempotencyKey(ctx context.Context, key string) (*domain.Escrow, error) {
+	for _, escrow := range m.escrows {
+		if escrow.IdempotencyKey == key {
+			return escrow, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockEscrowRepo) FindExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]*domain.Escrow, error) {
+	var res []*domain.Escrow
+	for _, escrow := range m.escrows {
+		if escrow.Status == domain.EscrowStatusHeld && !escrow.Deadline.After(now) && len(res) < limit {
+			res = append(res, escrow)
+		}
+	}
+	return res, nil
+}
+
+func TestEscrowUseCase(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewEscrowUseCase(accRepo, txRepo, newMockAssetRepo(), newMockEscrowRepo(), &mockTxManager{}, nil)
+	ctx := context.Background()
+
+	payer := domain.NewAccount(domain.AccountID(mustUUID("payer")), 0)
+	payer.Asset = domain.DefaultAssetCode
+	payer.Balance = 1000
+	accRepo.SaveAccount(ctx, payer)
+	winner := domain.NewAccount(domain.AccountID(mustUUID("winner")), 0)
+	winner.Asset = domain.DefaultAssetCode
+	accRepo.SaveAccount(ctx, winner)
+
+	deadline := time.Now().Add(time.Hour)
+	input := CreateEscrowInput{PayerAccountID: payer.ID, BeneficiaryAccountID: winner.ID, Amount: 300, Deadline: deadline, IdempotencyKey: "bet-1"}
+	bet, err := uc.CreateEscrow(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	escrowAccount, _ := accRepo.FindAccountByID(ctx, bet.Escrow.EscrowAccountID)
+	if payer.Balance != 700 || escrowAccount.Balance != 300 {
+		t.Errorf("expected balances 700 and 300 in escrow, got %d/%d", payer.Balance, escrowAccount.Balance)
+	}
+
+	// The escrowed points can only leave through release or refund
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	_, err = transferUC.Transfer(ctx, TransferInput{FromAccountID: escrowAccount.ID, ToAccountID: winner.ID, Amount: 300, IdempotencyKey: "drain"})
+	if err != domain.ErrSystemAccount {
+		t.Errorf("expected ErrSystemAccount, got %v", err)
+	}
+	accountUC := NewAccountUseCase(accRepo, newMockAccountChangeRepo(), newMockAssetRepo(), &mockTxManager{})
+	if _, err := accountUC.FreezeAccount(ctx, escrowAccount.ID, "admin"); err != domain.ErrSystemAccount {
+		t.Errorf("expected ErrSystemAccount, got %v", err)
+	}
+
+	again, err := uc.CreateEscrow(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.Escrow.ID != bet.Escrow.ID || again.JournalEntryID != bet.JournalEntryID || payer.Balance != 700 {
+		t.Errorf("expected retry to return escrow %s without paying again", bet.Escrow.ID)
+	}
+
+	released, err := uc.ReleaseEscrow(ctx, bet.Escrow.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if released.Escrow.Status != domain.EscrowStatusReleased || winner.Balance != 300 || escrowAccount.Balance != 0 {
+		t.Errorf("unexpected state after release: %+v, winner %d", released.Escrow, winner.Balance)
+	}
+	if _, err := uc.RefundEscrow(ctx, bet.Escrow.ID); err != domain.ErrEscrowNotHeld {
+		t.Errorf("expected ErrEscrowNotHeld, got %v", err)
+	}
+
+	funding, _ := txRepo.FindJournalEntryByID(ctx, bet.JournalEntryID)
+	settlement, _ := txRepo.FindJournalEntryByID(ctx, released.JournalEntryID)
+	for _, entry := range []*domain.JournalEntry{funding, settlement} {
+		if entry.Type != domain.EntryTypeEscrow || entry.EscrowID == nil || *entry.EscrowID != bet.Escrow.ID {
+			t.Errorf("expected escrow entry for %s, got %+v", bet.Escrow.ID, entry)
+		}
+	}
+
+	// Unreleased escrows are refunded at the deadline
+	bounty, err := uc.CreateEscrow(ctx, CreateEscrowInput{PayerAccountID: payer.ID, BeneficiaryAccountID: winner.ID, Amount: 200, Deadline: deadline, IdempotencyKey: "bounty-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bounty.Escrow.EscrowAccountID != escrowAccount.ID {
+		t.Errorf("expected escrows of one asset to share the escrow account")
+	}
+	n, err := uc.RefundExpired(ctx, deadline)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || bounty.Escrow.Status != domain.EscrowStatusRefunded || payer.Balance != 700 {
+		t.Errorf("expected 1 refund restoring balance 700, got %d refunds, balance %d", n, payer.Balance)
+	}
+
+	_, err = uc.CreateEscrow(ctx, CreateEscrowInput{PayerAccountID: payer.ID, BeneficiaryAccountID: winner.ID, Amount: 100, Deadline: time.Now(), IdempotencyKey: "late"})
+	if err != domain.ErrInvalidEscrowDeadline {
+		t.Errorf("expected ErrInvalidEscrowDeadline, got %v", err)
+	}
+}
+
+func TestEscrowUseCase_RefundExpired_ContinuesPastFailures(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	escrowRepo := newMockEscrowRepo()
+	uc := NewEscrowUseCase(accRepo, newMockJournalEntryRepo(), newMockAssetRepo(), escrowRepo, &mockTxManager{}, nil)
+	ctx := context.Background()
+
+	beneficiary := domain.NewAccount(domain.AccountID(mustUUID("beneficiary")), 0)
+	beneficiary.Asset = domain.DefaultAssetCode
+	accRepo.SaveAccount(ctx, beneficiary)
+	deadline := time.Now().Add(time.Hour)
+	payers := make([]*domain.Account, 2)
+	for i := range payers {
+		payers[i] = domain.NewAccount(domain.AccountID(mustUUID(fmt.Sprintf("refund-payer-%d", i))), 0)
+		payers[i].Asset = domain.DefaultAssetCode
+		payers[i].Balance = 100
+		accRepo.SaveAccount(ctx, payers[i])
+		_, err := uc.CreateEscrow(ctx, CreateEscrowInput{
+			PayerAccountID:       payers[i].ID,
+			BeneficiaryAccountID: beneficiary.ID,
+			Amount:               100,
+			Deadline:             deadline,
+			IdempotencyKey:       fmt.Sprintf("refund-%d", i),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first payer can no longer receive the refund
+	payers[0].Status = domain.AccountStatusClosed
+
+	n, err := uc.RefundExpired(ctx, deadline)
+	if !errors.Is(err, domain.ErrAccountClosed) {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+	if n != 1 || payers[1].Balance != 100 {
+		t.Errorf("expected the other escrow to be refunded, got %d refunds, balance %d", n, payers[1].Balance)
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// FeeUseCase manages the fee rules that TransferUseCase charges.
+type FeeUseCase struct {
+	accountRepo domain.AccountRepository
+	feeRuleRepo domain.FeeRuleRepository
+}
+
+func NewFeeUseCase(
+	accountRepo domain.AccountRepository,
+	feeRuleRepo domain.FeeRuleRepository,
+) *FeeUseCase {
+	return &FeeUseCase{
+		accountRepo: accountRepo,
+		feeRuleRepo: feeRuleRepo,
+	}
+}
+
+type CreateFeeRuleInput struct {
+	Name                 string
+	Asset                domain.AssetCode
+	SourceSelector       *domain.LabelSelector
+	DestinationAccountID *domain.AccountID
+	FlatFee              int64
+	RateBasisPoints      int64
+	MinFee               int64
+	MaxFee               *int64
+	CollectorAccountID   domain.AccountID
+	Priority             int
+}
+
+// CreateFeeRule adds a fee rule. The collector must exist and, if the rule is restricted
+// to an asset, hold that asset. A rule without an asset is restricted to the collector's,
+// since the collector cannot receive fees in any other.
+func (u *FeeUseCase) CreateFeeRule(ctx context.Context, input CreateFeeRuleInput) (*domain.FeeRule, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	rule := &domain.FeeRule{
+		ID:                   domain.FeeRuleID(id),
+		Name:                 input.Name,
+		Asset:                input.Asset,
+		SourceSelector:       input.SourceSelector,
+		DestinationAccountID: input.DestinationAccountID,
+		FlatFee:              input.FlatFee,
+		RateBasisPoints:      input.RateBasisPoints,
+		MinFee:               input.MinFee,
+		MaxFee:               input.MaxFee,
+		CollectorAccountID:   input.CollectorAccountID,
+		Priority:             input.Priority,
+		CreatedAt:            time.Now(),
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	// Fees are posted like transfers, so they are bounded the same way.
+	if rule.FlatFee > MaxTransferAmount || rule.MinFee > MaxTransferAmount {
+		return nil, domain.ErrInvalidFeeRule
+	}
+
+	collector, err := u.accountRepo.FindAccountByID(ctx, input.CollectorAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if collector == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if rule.Asset != "" && collector.Asset != rule.Asset {
+		return nil, domain.ErrAssetMismatch
+	}
+	rule.Asset = collector.Asset
+
+	if err := u.feeRuleRepo.SaveFeeRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (u *FeeUseCase) ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error) {
+	return u.feeRuleRepo.ListFeeRules(ctx)
+}
+
+func (u *FeeUseCase) DeleteFeeRule(ctx context.Context, id domain.FeeRuleID) error {
+	return u.feeRuleRepo.DeleteFeeRule(ctx, id)
+}
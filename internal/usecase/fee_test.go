@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockFeeRuleRepo struct {
+	rules []*domain.FeeRule
+}
+
+func (m *mockFeeRuleRepo) SaveFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	m.rules = append(m.rules, rule)
+	return nil
+}
+
+func (m *mockFeeRuleRepo) ListFeeRules(ctx context.Context) ([]*domain.FeeRule, error) {
+	return m.rules, nil
+}
+
+func (m *mockFeeRuleRepo) DeleteFeeRule(ctx context.Context, id domain.FeeRuleID) error {
+	for i, rule := range m.rules {
+		if rule.ID == id {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrFeeRuleNotFound
+}
+
+func TestTransferUseCase_Transfer_Fee(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	feeRepo := &mockFeeRuleRepo{}
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{}, WithFeeRules(feeRepo))
+	feeUC := NewFeeUseCase(accRepo, feeRepo)
+	ctx := context.Background()
+
+	buyerID := domain.AccountID(mustUUID("buyer"))
+	shopID := domain.AccountID(mustUUID("shop"))
+	collectorID := domain.AccountID(mustUUID("collector"))
+	buyer := domain.NewAccount(buyerID, 0)
+	buyer.Balance = 1000
+	shop := domain.NewAccount(shopID, 0)
+	collector := domain.NewAccount(collectorID, 0)
+	accRepo.SaveAccount(ctx, buyer)
+	accRepo.SaveAccount(ctx, shop)
+	accRepo.SaveAccount(ctx, collector)
+
+	maxFee := int64(30)
+	if _, err := feeUC.CreateFeeRule(ctx, CreateFeeRuleInput{
+		Name:                 "market",
+		DestinationAccountID: &shopID,
+		FlatFee:              2,
+		RateBasisPoints:      500,
+		MaxFee:               &maxFee,
+		CollectorAccountID:   collectorID,
+	}); err != nil {
+		t.Fatalf("unexpected error creating rule: %v", err)
+	}
+
+	quote, err := uc.QuoteTransfer(ctx, QuoteTransferInput{FromAccountID: buyerID, ToAccountID: shopID, Amount: 200})
+	if err != nil {
+		t.Fatalf("unexpected error quoting: %v", err)
+	}
+	if quote.Fee != 12 || quote.Total != 212 || quote.CollectorAccountID == nil || *quote.CollectorAccountID != collectorID {
+		t.Errorf("unexpected quote %+v", quote)
+	}
+
+	input := TransferInput{
+		FromAccountID:  buyerID,
+		ToAccountID:    shopID,
+		Amount:         200,
+		Description:    "purchase",
+		IdempotencyKey: "fee-1",
+	}
+	out, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Fee != 12 || out.FeeJournalEntryID == nil {
+		t.Fatalf("expected a fee of 12 with its entry, got %d/%v", out.Fee, out.FeeJournalEntryID)
+	}
+	if buyer.Balance != 788 || shop.Balance != 200 || collector.Balance != 12 {
+		t.Errorf("unexpected balances %d/%d/%d", buyer.Balance, shop.Balance, collector.Balance)
+	}
+
+	feeEntry, _ := txRepo.FindJournalEntryByID(ctx, *out.FeeJournalEntryID)
+	if feeEntry.Type != domain.EntryTypeFee || feeEntry.ToAccountID != collectorID || feeEntry.GroupID == nil {
+		t.Errorf("unexpected fee entry %+v", feeEntry)
+	}
+
+	// Replaying returns the same result even after the rules change
+	feeRepo.rules = nil
+	out2, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if out2.JournalEntryID != out.JournalEntryID || out2.Fee != 12 {
+		t.Errorf("expected the original result, got %+v", out2)
+	}
+	if buyer.Balance != 788 {
+		t.Errorf("retry must not apply again, balance %d", buyer.Balance)
+	}
+
+	// Replaying the key with another amount fails
+	mismatched := input
+	mismatched.Amount = 201
+	if _, err := uc.Transfer(ctx, mismatched); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	// Without a matching rule the transfer is a single entry
+	out3, err := uc.Transfer(ctx, TransferInput{FromAccountID: buyerID, ToAccountID: shopID, Amount: 100, IdempotencyKey: "fee-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out3.Fee != 0 || out3.FeeJournalEntryID != nil {
+		t.Errorf("expected no fee, got %+v", out3)
+	}
+}
+
+func TestTransferUseCase_Transfer_FeeInsufficientBalance(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	collectorID := domain.AccountID(mustUUID("collector"))
+	feeRepo := &mockFeeRuleRepo{rules: []*domain.FeeRule{
+		{ID: domain.FeeRuleID(uuid.New()), Name: "flat", FlatFee: 10, CollectorAccountID: collectorID, CreatedAt: time.Now()},
+	}}
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{}, WithFeeRules(feeRepo))
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("from"))
+	toID := domain.AccountID(mustUUID("to"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 100
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+	accRepo.SaveAccount(ctx, domain.NewAccount(collectorID, 0))
+
+	// The amount alone fits, but not with the fee on top
+	_, err := uc.Transfer(ctx, TransferInput{FromAccountID: fromID, ToAccountID: toID, Amount: 100, IdempotencyKey: "fee-3"})
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("expected ErrInsufficientBalance, got %v", err)
+	}
+}
+
+func TestTransferUseCase_Transfer_FeeOtherAsset(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	feeRepo := &mockFeeRuleRepo{}
+	uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{}, WithFeeRules(feeRepo))
+	feeUC := NewFeeUseCase(accRepo, feeRepo)
+	ctx := context.Background()
+
+	newAccount := func(name string, asset domain.AssetCode, balance int64) *domain.Account {
+		acc := domain.NewAccount(domain.AccountID(mustUUID(name)), 0)
+		acc.Asset = asset
+		acc.Balance = balance
+		accRepo.SaveAccount(ctx, acc)
+		return acc
+	}
+	collector := newAccount("point-collector", domain.DefaultAssetCode, 0)
+	pointFrom := newAccount("point-from", domain.DefaultAssetCode, 100)
+	pointTo := newAccount("point-to", domain.DefaultAssetCode, 0)
+	goldFrom := newAccount("gold-from", "gold", 100)
+	goldTo := newAccount("gold-to", "gold", 0)
+
+	// No asset given, so the rule is restricted to the collector's
+	rule, err := feeUC.CreateFeeRule(ctx, CreateFeeRuleInput{Name: "flat", FlatFee: 5, CollectorAccountID: collector.ID})
+	if err != nil {
+		t.Fatalf("unexpected error creating rule: %v", err)
+	}
+	if rule.Asset != domain.DefaultAssetCode {
+		t.Errorf("expected the collector's asset, got %q", rule.Asset)
+	}
+
+	out, err := uc.Transfer(ctx, TransferInput{FromAccountID: goldFrom.ID, ToAccountID: goldTo.ID, Amount: 50, IdempotencyKey: "gold-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Fee != 0 || goldFrom.Balance != 50 {
+		t.Errorf("expected no fee on gold, got fee %d, balance %d", out.Fee, goldFrom.Balance)
+	}
+	out, err = uc.Transfer(ctx, TransferInput{FromAccountID: pointFrom.ID, ToAccountID: pointTo.ID, Amount: 50, IdempotencyKey: "point-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Fee != 5 || collector.Balance != 5 {
+		t.Errorf("expected a fee of 5 on point, got fee %d, collected %d", out.Fee, collector.Balance)
+	}
+}
+
+func TestTransferUseCase_Transfer_FeeToDestination(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	feeRepo := &mockFeeRuleRepo{}
+	uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{}, WithFeeRules(feeRepo))
+	ctx := context.Background()
+
+	buyerID := domain.AccountID(mustUUID("buyer"))
+	shopID := domain.AccountID(mustUUID("shop"))
+	buyer := domain.NewAccount(buyerID, 0)
+	buyer.Balance = 1000
+	shop := domain.NewAccount(shopID, 0)
+	accRepo.SaveAccount(ctx, buyer)
+	accRepo.SaveAccount(ctx, shop)
+	// The shop collects the fee on its own sales
+	if _, err := NewFeeUseCase(accRepo, feeRepo).CreateFeeRule(ctx, CreateFeeRuleInput{
+		Name:                 "shop",
+		DestinationAccountID: &shopID,
+		FlatFee:              10,
+		CollectorAccountID:   shopID,
+	}); err != nil {
+		t.Fatalf("unexpected error creating rule: %v", err)
+	}
+
+	// The preconditions hold before the transfer, not between its legs
+	minBalance := int64(1000)
+	input := TransferInput{FromAccountID: buyerID, ToAccountID: shopID, Amount: 200, IdempotencyKey: "own-fee-1", MinFromBalance: &minBalance}
+	sim, err := uc.SimulateTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error simulating: %v", err)
+	}
+	if sim.Fee != 10 || sim.ToBalance != 210 {
+		t.Errorf("unexpected simulation %+v", sim)
+	}
+	out, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Fee != 10 || buyer.Balance != 790 || shop.Balance != 210 {
+		t.Errorf("expected a fee of 10 and balances 790/210, got %d, %d/%d", out.Fee, buyer.Balance, shop.Balance)
+	}
+}
+
+func TestTransferUseCase_Transfer_FeeCollectorInTree(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	feeRepo := &mockFeeRuleRepo{}
+	uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{},
+		WithFeeRules(feeRepo), WithIntraTreeTransferPolicy(IntraTreeTransfersDenied))
+	ctx := context.Background()
+
+	rootID := domain.AccountID(mustUUID("root"))
+	collectorID := domain.AccountID(mustUUID("collector"))
+	toID := domain.AccountID(mustUUID("to"))
+	root := domain.NewAccount(rootID, 0)
+	root.Balance = 100
+	collector := domain.NewAccount(collectorID, 0)
+	collector.ParentID = &rootID
+	accRepo.SaveAccount(ctx, root)
+	accRepo.SaveAccount(ctx, collector)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+	if _, err := NewFeeUseCase(accRepo, feeRepo).CreateFeeRule(ctx, CreateFeeRuleInput{Name: "flat", FlatFee: 5, CollectorAccountID: collectorID}); err != nil {
+		t.Fatalf("unexpected error creating rule: %v", err)
+	}
+
+	// The fee leg is not a transfer within the tree
+	if _, err := uc.Transfer(ctx, TransferInput{FromAccountID: rootID, ToAccountID: toID, Amount: 50, IdempotencyKey: "tree-fee-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if collector.Balance != 5 {
+		t.Errorf("expected a fee of 5, got %d", collector.Balance)
+	}
+	// Transfers to the collector itself still are
+	if _, err := uc.Transfer(ctx, TransferInput{FromAccountID: rootID, ToAccountID: collectorID, Amount: 10, IdempotencyKey: "tree-fee-2"}); !errors.Is(err, domain.ErrIntraTreeTransfer) {
+		t.Errorf("expected ErrIntraTreeTransfer, got %v", err)
+	}
+}
+
+func TestFeeUseCase_CreateFeeRule(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	uc := NewFeeUseCase(accRepo, &mockFeeRuleRepo{})
+	ctx := context.Background()
+
+	collectorID := domain.AccountID(mustUUID("collector"))
+	collector := domain.NewAccount(collectorID, 0)
+	collector.Asset = "point"
+	accRepo.SaveAccount(ctx, collector)
+
+	tests := []struct {
+		name  string
+		input CreateFeeRuleInput
+		want  error
+	}{
+		{"valid", CreateFeeRuleInput{Name: "flat", FlatFee: 1, CollectorAccountID: collectorID}, nil},
+		{"unknown collector", CreateFeeRuleInput{Name: "flat", CollectorAccountID: domain.AccountID(mustUUID("nobody"))}, domain.ErrAccountNotFound},
+		{"collector of another asset", CreateFeeRuleInput{Name: "flat", Asset: "gold", CollectorAccountID: collectorID}, domain.ErrAssetMismatch},
+		{"flat fee too large", CreateFeeRuleInput{Name: "flat", FlatFee: MaxTransferAmount + 1, CollectorAccountID: collectorID}, domain.ErrInvalidFeeRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateFeeRule(ctx, tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
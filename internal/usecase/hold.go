@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+const (
+	// DefaultHoldTTL is how long a hold stays authorized when no TTL is given
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	// MaxHoldTTL is the longest allowed hold TTL
+	MaxHoldTTL = 30 * 24 * time.Hour
+	// expiredHoldsBatchSize is the maximum number of holds ExpireHolds releases per call
+	expiredHoldsBatchSize = 100
+)
+
+// HoldUseCase reserves points with holds and settles them later.
+type HoldUseCase struct {
+	accountRepo domain.AccountRepository
+	repo        domain.JournalEntryRepository
+	holdRepo    domain.HoldRepository
+	tm          domain.TransactionManager
+	lots        *PointLotUseCase
+	// limits, if not nil, enforces the velocity limits of transfers on captures.
+	limits *TransferUseCase
+}
+
+// HoldOption configures a HoldUseCase.
+type HoldOption func(*HoldUseCase)
+
+// WithCaptureVelocityLimits checks captures against the velocity limits transferUC enforces on
+// transfers, so that authorizing and capturing cannot send more than a transfer could.
+func WithCaptureVelocityLimits(transferUC *TransferUseCase) HoldOption {
+	return func(u *HoldUseCase) {
+		u.limits = transferUC
+	}
+}
+
+func NewHoldUseCase(
+	accountRepo domain.AccountRepository,
+	repo domain.JournalEntryRepository,
+	holdRepo domain.HoldRepository,
+	tm domain.TransactionManager,
+	// lots tracks expiring points. Nil disables point expiry.
+	lots *PointLotUseCase,
+	opts ...HoldOption,
+) *HoldUseCase {
+	u := &HoldUseCase{
+		accountRepo: accountRepo,
+		repo:        repo,
+		holdRepo:    holdRepo,
+		tm:          tm,
+		lots:        lots,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+type AuthorizeInput struct {
+	AccountID      domain.AccountID
+	ToAccountID    domain.AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+	// TTL defaults to DefaultHoldTTL when zero.
+	TTL time.Duration
+}
+
+// Authorize reserves points on the account for a later capture to ToAccountID.
+// The ledger balance is unchanged; only the available balance goes down.
+// Replays of the key with other accounts, amount, description or TTL fail with an *IdempotencyKeyReusedError.
+func (u *HoldUseCase) Authorize(ctx context.Context, input AuthorizeInput) (*domain.Hold, error) {
+	m := movement{
+		FromAccountID:  input.AccountID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	ttl := input.TTL
+	if ttl == 0 {
+		ttl = DefaultHoldTTL
+	}
+	if ttl < 0 || ttl > MaxHoldTTL {
+		return nil, domain.ErrInvalidHoldExpiry
+	}
+	fingerprint := domain.RequestFingerprint("hold", input.AccountID, input.ToAccountID, input.Amount, input.Description, ttl)
+
+	existing, err := u.holdRepo.FindHoldByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := checkReplay(input.IdempotencyKey, fingerprint, existing.RequestFingerprint, domain.JournalEntryID{}); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	var hold *domain.Hold
+
+	err = u.tm.Run(ctx, func(ctx context.Context) error {
+		existing, err := u.holdRepo.FindHoldByIdempotencyKey(ctx, input.IdempotencyKey)
+		if err == nil && existing != nil {
+			hold = existing
+			return checkReplay(input.IdempotencyKey, fingerprint, existing.RequestFingerprint, domain.JournalEntryID{})
+		}
+
+		accounts, err := lockAccounts(ctx, u.accountRepo, input.AccountID, input.ToAccountID)
+		if err != nil {
+			return err
+		}
+		from, to := accounts[input.AccountID], accounts[input.ToAccountID]
+		if from.Asset != to.Asset {
+			return domain.ErrAssetMismatch
+		}
+		// Captures post transfers, which cannot move system accounts.
+		if from.System || to.System {
+			return domain.ErrSystemAccount
+		}
+		if to.Status == domain.AccountStatusClosed {
+			return domain.ErrAccountClosed
+		}
+		if err := from.Hold(input.Amount); err != nil {
+			return err
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		hold = &domain.Hold{
+			ID:                 domain.HoldID(id),
+			AccountID:          from.ID,
+			ToAccountID:        to.ID,
+			Amount:             input.Amount,
+			Description:        input.Description,
+			Status:             domain.HoldStatusAuthorized,
+			IdempotencyKey:     input.IdempotencyKey,
+			RequestFingerprint: fingerprint,
+			ExpiresAt:          now.Add(ttl),
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+
+		if err := u.accountRepo.SaveAccount(ctx, from); err != nil {
+			return err
+		}
+		return u.holdRepo.SaveHold(ctx, hold)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+type CaptureInput struct {
+	HoldID domain.HoldID
+	// Amount to transfer. Nil captures the full held amount. The rest of the hold is released.
+	Amount *int64
+}
+
+type CaptureOutput struct {
+	Hold           *domain.Hold
+	JournalEntryID domain.JournalEntryID
+	CreatedAt      time.Time
+}
+
+// Capture settles the hold by posting a transfer of the captured amount and releasing the hold.
+// Retrying a capture returns the entry of the first one.
+func (u *HoldUseCase) Capture(ctx context.Context, input CaptureInput) (*CaptureOutput, error) {
+	hold, err := u.GetHold(ctx, input.HoldID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := hold.Amount
+	if input.Amount != nil {
+		amount = *input.Amount
+	}
+
+	entry, err := u.poster().post(ctx, movement{
+		Type:           domain.EntryTypeTransfer,
+		FromAccountID:  hold.AccountID,
+		ToAccountID:    hold.ToAccountID,
+		Amount:         amount,
+		Description:    hold.Description,
+		IdempotencyKey: hold.CaptureIdempotencyKey(),
+		// Retries may name another amount and still get the first capture.
+		Fingerprint: domain.RequestFingerprint("capture", hold.ID),
+	}, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		locked, err := u.holdRepo.GetHoldForUpdate(ctx, hold.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := locked.CheckCapturable(amount, now); err != nil {
+			return err
+		}
+		if u.limits != nil {
+			if err := u.limits.checkVelocityLimits(ctx, from, amount); err != nil {
+				return err
+			}
+		}
+		// Release the whole hold first so that the withdrawal can spend the held points.
+		if err := from.Release(locked.Amount); err != nil {
+			return err
+		}
+		if err := locked.Settle(domain.HoldStatusCaptured, now); err != nil {
+			return err
+		}
+		locked.CapturedAmount = amount
+		return u.holdRepo.SaveHold(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err = u.GetHold(ctx, input.HoldID)
+	if err != nil {
+		return nil, err
+	}
+	return &CaptureOutput{
+		Hold:           hold,
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	}, nil
+}
+
+// Void releases an authorized hold without moving points.
+func (u *HoldUseCase) Void(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	return u.release(ctx, id, domain.HoldStatusVoided)
+}
+
+// ExpireHolds releases holds that expired at or before now and returns how many were released.
+func (u *HoldUseCase) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	holds, err := u.holdRepo.FindExpiredHolds(ctx, now, expiredHoldsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, hold := range holds {
+		if _, err := u.release(ctx, hold.ID, domain.HoldStatusExpired); err != nil {
+			// Captured or voided in the meantime
+			if errors.Is(err, domain.ErrHoldNotAuthorized) {
+				continue
+			}
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (u *HoldUseCase) GetHold(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	hold, err := u.holdRepo.FindHoldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold == nil {
+		return nil, domain.ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+// release settles an authorized hold with status and returns the held points to the account.
+func (u *HoldUseCase) release(ctx context.Context, id domain.HoldID, status domain.HoldStatus) (*domain.Hold, error) {
+	hold, err := u.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.tm.Run(ctx, func(ctx context.Context) error {
+		// Lock the account before the hold, in the same order as Capture.
+		accounts, err := lockAccounts(ctx, u.accountRepo, hold.AccountID)
+		if err != nil {
+			return err
+		}
+		acc := accounts[hold.AccountID]
+
+		hold, err = u.holdRepo.GetHoldForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := hold.Settle(status, time.Now()); err != nil {
+			return err
+		}
+		if err := acc.Release(hold.Amount); err != nil {
+			return err
+		}
+
+		if err := u.accountRepo.SaveAccount(ctx, acc); err != nil {
+			return err
+		}
+		return u.holdRepo.SaveHold(ctx, hold)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (u *HoldUseCase) poster() *poster {
+	return &poster{accountRepo: u.accountRepo, repo: u.repo, tm: u.tm, lots: u.lots}
+}
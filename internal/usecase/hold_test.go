@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockHoldRepo struct {
+	holds map[domain.HoldID]*domain.Hold
+}
+
+func newMockHoldRepo() *mockHoldRepo {
+	return &mockHoldRepo{holds: make(map[domain.HoldID]*domain.Hold)}
+}
+
+func (m *mockHoldRepo) SaveHold(ctx context.Context, hold *domain.Hold) error {
+	m.holds[hold.ID] = hold
+	return nil
+}
+
+func (m *mockHoldRepo) FindHoldByID(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	if hold, ok := m.holds[id]; ok {
+		return hold, nil
+	}
+	return nil, nil
+}
+
+func (m *mockHoldRepo) GetHoldForUpdate(ctx context.Context, id domain.HoldID) (*domain.Hold, error) {
+	if hold, ok := m.holds[id]; ok {
+		return hold, nil
+	}
+	return nil, domain.ErrHoldNotFound
+}
+
+func (m *mockHoldRepo) FindHoldByIdempotencyKey(ctx context.Context, key string) (*domain.Hold, error) {
+	for _, hold := range m.holds {
+		if hold.IdempotencyKey == key {
+			return hold, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockHoldRepo) FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*domain.Hold, error) {
+	var res []*domain.Hold
+	for _, hold := range m.holds {
+		if hold.Status == domain.HoldStatusAuthorized && !hold.ExpiresAt.After(now) && len(res) < limit {
+			res = append(res, hold)
+		}
+	}
+	return res, nil
+}
+
+func setupHoldTest(t *testing.T) (*HoldUseCase, *TransferUseCase, *domain.Account, *domain.Account) {
+	t.Helper()
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewHoldUseCase(accRepo, txRepo, newMockHoldRepo(), &mockTxManager{}, nil)
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	from := domain.NewAccount(domain.AccountID(mustUUID("acc-from")), 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(domain.AccountID(mustUUID("acc-to")), 0)
+	accRepo.SaveAccount(ctx, to)
+	return uc, transferUC, from, to
+}
+
+func TestHoldUseCase_AuthorizeAndCapture(t *testing.T) {
+	uc, transferUC, from, to := setupHoldTest(t)
+	ctx := context.Background()
+
+	input := AuthorizeInput{AccountID: from.ID, ToAccountID: to.ID, Amount: 600, IdempotencyKey: "auth-1"}
+	hold, err := uc.Authorize(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 1000 || from.AvailableBalance() != 400 {
+		t.Errorf("expected ledger 1000 and available 400, got %d/%d", from.Balance, from.AvailableBalance())
+	}
+
+	again, err := uc.Authorize(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.ID != hold.ID || from.HeldBalance != 600 {
+		t.Errorf("expected retry to return hold %s without holding again, held %d", hold.ID, from.HeldBalance)
+	}
+	changed := input
+	changed.Amount = 500
+	if _, err := uc.Authorize(ctx, changed); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	_, err = transferUC.Transfer(ctx, TransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 401, IdempotencyKey: "tx-1"})
+	if err != domain.ErrInsufficientBalance {
+		t.Errorf("expected transfer to respect the hold, got %v", err)
+	}
+
+	tooMuch := int64(601)
+	if _, err := uc.Capture(ctx, CaptureInput{HoldID: hold.ID, Amount: &tooMuch}); err != domain.ErrCaptureExceedsHold {
+		t.Errorf("expected ErrCaptureExceedsHold, got %v", err)
+	}
+
+	partial := int64(250)
+	out, err := uc.Capture(ctx, CaptureInput{HoldID: hold.ID, Amount: &partial})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Hold.Status != domain.HoldStatusCaptured || out.Hold.CapturedAmount != 250 {
+		t.Errorf("unexpected hold after capture: %+v", out.Hold)
+	}
+	if from.Balance != 750 || from.HeldBalance != 0 || to.Balance != 250 {
+		t.Errorf("expected balances 750/250 with nothing held, got %d/%d held %d", from.Balance, to.Balance, from.HeldBalance)
+	}
+
+	// Retrying the capture returns the first entry
+	retry, err := uc.Capture(ctx, CaptureInput{HoldID: hold.ID, Amount: &partial})
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if retry.JournalEntryID != out.JournalEntryID || to.Balance != 250 {
+		t.Errorf("expected retry to return entry %s, got %s", out.JournalEntryID, retry.JournalEntryID)
+	}
+
+	if _, err := uc.Void(ctx, hold.ID); err != domain.ErrHoldNotAuthorized {
+		t.Errorf("expected ErrHoldNotAuthorized, got %v", err)
+	}
+}
+
+func TestHoldUseCase_VoidAndExpire(t *testing.T) {
+	uc, _, from, to := setupHoldTest(t)
+	ctx := context.Background()
+
+	voided, err := uc.Authorize(ctx, AuthorizeInput{AccountID: from.ID, ToAccountID: to.ID, Amount: 300, IdempotencyKey: "auth-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expiring, err := uc.Authorize(ctx, AuthorizeInput{AccountID: from.ID, ToAccountID: to.ID, Amount: 200, IdempotencyKey: "auth-2", TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.AvailableBalance() != 500 {
+		t.Errorf("expected available 500, got %d", from.AvailableBalance())
+	}
+
+	if _, err := uc.Void(ctx, voided.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.HeldBalance != 200 {
+		t.Errorf("expected 200 held after void, got %d", from.HeldBalance)
+	}
+
+	n, err := uc.ExpireHolds(ctx, time.Now().Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || expiring.Status != domain.HoldStatusExpired {
+		t.Errorf("expected 1 expired hold, got %d (status %s)", n, expiring.Status)
+	}
+	if from.Balance != 1000 || from.HeldBalance != 0 || to.Balance != 0 {
+		t.Errorf("expected no points moved, got %d/%d held %d", from.Balance, to.Balance, from.HeldBalance)
+	}
+
+	_, err = uc.Authorize(ctx, AuthorizeInput{AccountID: from.ID, ToAccountID: to.ID, Amount: 100, IdempotencyKey: "auth-3", TTL: MaxHoldTTL + time.Second})
+	if err != domain.ErrInvalidHoldExpiry {
+		t.Errorf("expected ErrInvalidHoldExpiry, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// IssuanceUseCase creates and destroys points through each asset's issuer account.
+type IssuanceUseCase struct {
+	accountRepo domain.AccountRepository
+	repo        domain.JournalEntryRepository
+	assetRepo   domain.AssetRepository
+	tm          domain.TransactionManager
+	lots        *PointLotUseCase
+}
+
+func NewIssuanceUseCase(
+	accountRepo domain.AccountRepository,
+	repo domain.JournalEntryRepository,
+	assetRepo domain.AssetRepository,
+	tm domain.TransactionManager,
+	// lots tracks expiring points. Nil disables point expiry.
+	lots *PointLotUseCase,
+) *IssuanceUseCase {
+	return &IssuanceUseCase{
+		accountRepo: accountRepo,
+		repo:        repo,
+		assetRepo:   assetRepo,
+		tm:          tm,
+		lots:        lots,
+	}
+}
+
+type MintInput struct {
+	Asset          domain.AssetCode
+	ToAccountID    domain.AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+}
+
+type BurnInput struct {
+	Asset          domain.AssetCode
+	FromAccountID  domain.AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+}
+
+// Mint creates points by posting a mint entry from the issuer account to the recipient
+// and increases the circulating supply.
+func (u *IssuanceUseCase) Mint(ctx context.Context, input MintInput) (*TransferOutput, error) {
+	issuerID, err := u.issuerOf(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := u.poster().post(ctx, movement{
+		Type:           domain.EntryTypeMint,
+		FromAccountID:  issuerID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+		Fingerprint:    domain.RequestFingerprint("mint", input.Asset, input.ToAccountID, input.Amount, input.Description),
+	}, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		asset, err := u.lockAsset(ctx, input.Asset, issuerID)
+		if err != nil {
+			return err
+		}
+		if err := asset.AddSupply(input.Amount); err != nil {
+			return err
+		}
+		return u.assetRepo.SaveAsset(ctx, asset)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferOutput{
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	}, nil
+}
+
+// Burn destroys points by posting a burn entry from the holder to the issuer account
+// and decreases the circulating supply.
+func (u *IssuanceUseCase) Burn(ctx context.Context, input BurnInput) (*TransferOutput, error) {
+	issuerID, err := u.issuerOf(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := u.poster().post(ctx, movement{
+		Type:           domain.EntryTypeBurn,
+		FromAccountID:  input.FromAccountID,
+		ToAccountID:    issuerID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+		Fingerprint:    domain.RequestFingerprint("burn", input.Asset, input.FromAccountID, input.Amount, input.Description),
+	}, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		asset, err := u.lockAsset(ctx, input.Asset, issuerID)
+		if err != nil {
+			return err
+		}
+		if err := asset.RemoveSupply(input.Amount); err != nil {
+			return err
+		}
+		return u.assetRepo.SaveAsset(ctx, asset)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferOutput{
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	}, nil
+}
+
+// GetSupply returns the asset with its circulating supply and issuer.
+func (u *IssuanceUseCase) GetSupply(ctx context.Context, code domain.AssetCode) (*domain.Asset, error) {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	return asset, nil
+}
+
+func (u *IssuanceUseCase) issuerOf(ctx context.Context, code domain.AssetCode) (domain.AccountID, error) {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, code)
+	if err != nil {
+		return domain.AccountID{}, err
+	}
+	if asset == nil {
+		return domain.AccountID{}, domain.ErrAssetNotFound
+	}
+	if asset.IssuerAccountID == nil {
+		return domain.AccountID{}, domain.ErrIssuerNotConfigured
+	}
+	return *asset.IssuerAccountID, nil
+}
+
+// lockAsset locks the asset row and verifies that issuerID is still its issuer.
+func (u *IssuanceUseCase) lockAsset(ctx context.Context, code domain.AssetCode, issuerID domain.AccountID) (*domain.Asset, error) {
+	asset, err := u.assetRepo.GetAssetForUpdate(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if asset.IssuerAccountID == nil || *asset.IssuerAccountID != issuerID {
+		return nil, domain.ErrIssuerNotConfigured
+	}
+	return asset, nil
+}
+
+func (u *IssuanceUseCase) poster() *poster {
+	return &poster{accountRepo: u.accountRepo, repo: u.repo, tm: u.tm, lots: u.lots}
+}
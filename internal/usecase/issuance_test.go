@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+func TestIssuanceUseCase_MintAndBurn(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	assetRepo := newMockAssetRepo()
+	tm := &mockTxManager{}
+	uc := NewIssuanceUseCase(accRepo, txRepo, assetRepo, tm, nil)
+	ctx := context.Background()
+
+	issuerID := domain.AccountID(mustUUID("issuer"))
+	holderID := domain.AccountID(mustUUID("holder"))
+	issuer := domain.NewAccount(issuerID, 0)
+	issuer.Asset = domain.DefaultAssetCode
+	accRepo.SaveAccount(ctx, issuer)
+	holder := domain.NewAccount(holderID, 0)
+	holder.Asset = domain.DefaultAssetCode
+	accRepo.SaveAccount(ctx, holder)
+
+	// No issuer designated yet
+	_, err := uc.Mint(ctx, MintInput{Asset: domain.DefaultAssetCode, ToAccountID: holderID, Amount: 100, IdempotencyKey: "mint-0"})
+	if err != domain.ErrIssuerNotConfigured {
+		t.Fatalf("expected ErrIssuerNotConfigured, got %v", err)
+	}
+
+	assetUC := NewAssetUseCase(assetRepo, accRepo, tm)
+	if _, err := assetUC.SetAssetIssuer(ctx, domain.DefaultAssetCode, issuerID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mintInput := MintInput{Asset: domain.DefaultAssetCode, ToAccountID: holderID, Amount: 1000, IdempotencyKey: "mint-1"}
+	out, err := uc.Mint(ctx, mintInput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txRepo.txs[out.JournalEntryID.String()].Type != domain.EntryTypeMint {
+		t.Errorf("expected mint entry, got %s", txRepo.txs[out.JournalEntryID.String()].Type)
+	}
+
+	// Replaying the mint must not change the supply again
+	if _, err := uc.Mint(ctx, mintInput); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+
+	out, err = uc.Burn(ctx, BurnInput{Asset: domain.DefaultAssetCode, FromAccountID: holderID, Amount: 300, IdempotencyKey: "burn-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txRepo.txs[out.JournalEntryID.String()].Type != domain.EntryTypeBurn {
+		t.Errorf("expected burn entry, got %s", txRepo.txs[out.JournalEntryID.String()].Type)
+	}
+
+	asset, err := uc.GetSupply(ctx, domain.DefaultAssetCode)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.Supply != 700 {
+		t.Errorf("expected supply 700, got %d", asset.Supply)
+	}
+	if holder.Balance != 700 {
+		t.Errorf("expected holder balance 700, got %d", holder.Balance)
+	}
+	if issuer.Balance != -700 {
+		t.Errorf("expected issuer balance -700, got %d", issuer.Balance)
+	}
+
+	// Only issuance entries move the issuer's points, so the supply stays accurate.
+	transferUC := NewTransferUseCase(accRepo, txRepo, tm)
+	_, err = transferUC.Transfer(ctx, TransferInput{FromAccountID: issuerID, ToAccountID: holderID, Amount: 50, IdempotencyKey: "issuer-out"})
+	if err != domain.ErrSystemAccount {
+		t.Errorf("expected ErrSystemAccount, got %v", err)
+	}
+	_, err = transferUC.Transfer(ctx, TransferInput{FromAccountID: holderID, ToAccountID: issuerID, Amount: 50, IdempotencyKey: "issuer-in"})
+	if err != domain.ErrSystemAccount {
+		t.Errorf("expected ErrSystemAccount, got %v", err)
+	}
+	if issuer.Balance != -700 || holder.Balance != 700 {
+		t.Errorf("unexpected balances %d/%d", issuer.Balance, holder.Balance)
+	}
+	accountUC := NewAccountUseCase(accRepo, newMockAccountChangeRepo(), assetRepo, tm)
+	if _, err := accountUC.FreezeAccount(ctx, issuerID, "admin"); err != domain.ErrSystemAccount {
+		t.Errorf("expected ErrSystemAccount, got %v", err)
+	}
+
+	// The issuer cannot change while points are in circulation
+	otherID := domain.AccountID(mustUUID("other-issuer"))
+	accRepo.SaveAccount(ctx, domain.NewAccount(otherID, 0))
+	if _, err := assetUC.SetAssetIssuer(ctx, domain.DefaultAssetCode, otherID); err != domain.ErrIssuerInUse {
+		t.Errorf("expected ErrIssuerInUse, got %v", err)
+	}
+}
+
+func TestIssuanceUseCase_Burn_InsufficientSupply(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	assetRepo := newMockAssetRepo()
+	uc := NewIssuanceUseCase(accRepo, newMockJournalEntryRepo(), assetRepo, &mockTxManager{}, nil)
+	ctx := context.Background()
+
+	issuerID := domain.AccountID(mustUUID("issuer"))
+	holderID := domain.AccountID(mustUUID("holder"))
+	accRepo.SaveAccount(ctx, domain.NewAccount(issuerID, 0))
+	holder := domain.NewAccount(holderID, 0)
+	holder.Balance = 500
+	accRepo.SaveAccount(ctx, holder)
+	assetRepo.assets[domain.DefaultAssetCode].IssuerAccountID = &issuerID
+
+	_, err := uc.Burn(ctx, BurnInput{Asset: domain.DefaultAssetCode, FromAccountID: holderID, Amount: 100, IdempotencyKey: "burn-1"})
+	if err != domain.ErrInsufficientSupply {
+		t.Errorf("expected ErrInsufficientSupply, got %v", err)
+	}
+}
+
+func TestAssetUseCase_SetAssetIssuer(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	assetRepo := newMockAssetRepo()
+	assetRepo.SaveAsset(context.Background(), &domain.Asset{Code: "club", Name: "Club Point"})
+	uc := NewAssetUseCase(assetRepo, accRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	clubID := domain.AccountID(mustUUID("club-issuer"))
+	club := domain.NewAccount(clubID, 0)
+	club.Asset = "club"
+	accRepo.SaveAccount(ctx, club)
+	fundedID := domain.AccountID(mustUUID("club-funded"))
+	funded := domain.NewAccount(fundedID, 0)
+	funded.Asset = "club"
+	funded.Balance = 10
+	accRepo.SaveAccount(ctx, funded)
+
+	if _, err := uc.SetAssetIssuer(ctx, domain.DefaultAssetCode, clubID); err != domain.ErrAssetMismatch {
+		t.Errorf("expected ErrAssetMismatch, got %v", err)
+	}
+	if _, err := uc.SetAssetIssuer(ctx, "club", domain.AccountID(mustUUID("missing"))); err != domain.ErrAccountNotFound {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+	if _, err := uc.SetAssetIssuer(ctx, "club", fundedID); err != domain.ErrAccountNotEmpty {
+		t.Errorf("expected ErrAccountNotEmpty, got %v", err)
+	}
+	asset, err := uc.SetAssetIssuer(ctx, "club", clubID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.IssuerAccountID == nil || *asset.IssuerAccountID != clubID {
+		t.Errorf("expected issuer %s, got %v", clubID, asset.IssuerAccountID)
+	}
+	if !club.System || club.CreditLimit != domain.UnlimitedCreditLimit {
+		t.Errorf("expected a system account with unlimited credit, got %+v", club)
+	}
+
+	// Without points in circulation the issuer can be replaced
+	nextID := domain.AccountID(mustUUID("club-issuer-2"))
+	next := domain.NewAccount(nextID, 0)
+	next.Asset = "club"
+	accRepo.SaveAccount(ctx, next)
+	if _, err := uc.SetAssetIssuer(ctx, "club", nextID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if club.System || !next.System {
+		t.Errorf("expected the system flag to move to the new issuer")
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+const (
+	// MaxPaymentRequestDuration is the latest a payment request may expire
+	MaxPaymentRequestDuration = 90 * 24 * time.Hour
+	// expiredPaymentRequestsBatchSize is the maximum number of payment requests ExpireDue expires per call
+	expiredPaymentRequestsBatchSize = 100
+)
+
+// PaymentRequestUseCase lets payees request payments that the payer accepts or declines.
+type PaymentRequestUseCase struct {
+	transferUC  *TransferUseCase
+	accountRepo domain.AccountRepository
+	repo        domain.PaymentRequestRepository
+	tm          domain.TransactionManager
+}
+
+func NewPaymentRequestUseCase(
+	transferUC *TransferUseCase,
+	accountRepo domain.AccountRepository,
+	repo domain.PaymentRequestRepository,
+	tm domain.TransactionManager,
+) *PaymentRequestUseCase {
+	return &PaymentRequestUseCase{
+		transferUC:  transferUC,
+		accountRepo: accountRepo,
+		repo:        repo,
+		tm:          tm,
+	}
+}
+
+type CreatePaymentRequestInput struct {
+	PayerAccountID domain.AccountID
+	PayeeAccountID domain.AccountID
+	Amount         int64
+	Description    string
+	ExpiresAt      time.Time
+	IdempotencyKey string
+}
+
+// CreatePaymentRequest asks the payer to pay the payee.
+// Balances are checked when the payer accepts, not when the request is created.
+// Replays of the key with other accounts, amount, description or expiry fail with
+// an *IdempotencyKeyReusedError.
+func (u *PaymentRequestUseCase) CreatePaymentRequest(ctx context.Context, input CreatePaymentRequestInput) (*domain.PaymentRequest, error) {
+	m := movement{
+		FromAccountID:  input.PayerAccountID,
+		ToAccountID:    input.PayeeAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !input.ExpiresAt.After(now) || input.ExpiresAt.After(now.Add(MaxPaymentRequestDuration)) {
+		return nil, domain.ErrInvalidPaymentRequestExpiry
+	}
+
+	fingerprint := domain.RequestFingerprint("payment_request", input.PayerAccountID, input.PayeeAccountID,
+		input.Amount, input.Description, input.ExpiresAt)
+
+	existing, err := u.repo.FindPaymentRequestByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := checkReplay(input.IdempotencyKey, fingerprint, existing.RequestFingerprint, domain.JournalEntryID{}); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if err := checkFutureTransferAccounts(ctx, u.accountRepo, input.PayerAccountID, input.PayeeAccountID); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	req := &domain.PaymentRequest{
+		ID:                 domain.PaymentRequestID(id),
+		PayerAccountID:     input.PayerAccountID,
+		PayeeAccountID:     input.PayeeAccountID,
+		Amount:             input.Amount,
+		Description:        input.Description,
+		Status:             domain.PaymentRequestStatusPending,
+		ExpiresAt:          input.ExpiresAt,
+		IdempotencyKey:     input.IdempotencyKey,
+		RequestFingerprint: fingerprint,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := u.repo.SavePaymentRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (u *PaymentRequestUseCase) GetPaymentRequest(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	req, err := u.repo.FindPaymentRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, domain.ErrPaymentRequestNotFound
+	}
+	return req, nil
+}
+
+// ListPaymentRequests returns the payment requests to or from the account, most recently changed first.
+// An empty status lists requests of every status.
+func (u *PaymentRequestUseCase) ListPaymentRequests(ctx context.Context, accountID domain.AccountID, status domain.PaymentRequestStatus, limit, offset int) ([]*domain.PaymentRequest, error) {
+	limit, offset = normalizePage(limit, offset)
+	return u.repo.FindPaymentRequestsByAccountID(ctx, accountID, status, limit, offset)
+}
+
+// AcceptPaymentRequest pays the pending request with a transfer from the payer to the payee,
+// using the request ID as idempotency key. Accepting an accepted request returns it unchanged.
+// If the transfer is rejected, the request stays pending so that the payer can retry.
+func (u *PaymentRequestUseCase) AcceptPaymentRequest(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	if _, err := u.GetPaymentRequest(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var req *domain.PaymentRequest
+	// The row lock keeps the request from being declined or expired while it is paid.
+	// If saving the request fails after the transfer committed, the retry finds the entry by its key.
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		req, err = u.repo.GetPaymentRequestForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return domain.ErrPaymentRequestNotFound
+		}
+		if req.Status == domain.PaymentRequestStatusAccepted {
+			return nil
+		}
+		now := time.Now()
+		if err := req.CheckAcceptable(now); err != nil {
+			return err
+		}
+
+		out, err := u.transferUC.Transfer(ctx, TransferInput{
+			FromAccountID:  req.PayerAccountID,
+			ToAccountID:    req.PayeeAccountID,
+			Amount:         req.Amount,
+			Description:    req.Description,
+			IdempotencyKey: req.TransferIdempotencyKey(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := req.Accept(out.JournalEntryID, now); err != nil {
+			return err
+		}
+		return u.repo.SavePaymentRequest(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// DeclinePaymentRequest refuses a pending request.
+func (u *PaymentRequestUseCase) DeclinePaymentRequest(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	return u.settle(ctx, id, (*domain.PaymentRequest).Decline)
+}
+
+// ExpireDue expires the pending requests whose expiry is at or before now and returns how many were expired.
+func (u *PaymentRequestUseCase) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	reqs, err := u.repo.FindExpiredPaymentRequests(ctx, now, expiredPaymentRequestsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, req := range reqs {
+		if _, err := u.settle(ctx, req.ID, (*domain.PaymentRequest).Expire); err != nil {
+			// Accepted or declined in the meantime, or paid by an accept that failed to save the request
+			if errors.Is(err, domain.ErrPaymentRequestNotPending) {
+				continue
+			}
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// settle applies a status change to the locked request and saves it.
+// A pending request whose transfer was posted by an accept that failed to save the request is
+// marked accepted with that entry instead, and settle fails with ErrPaymentRequestNotPending.
+func (u *PaymentRequestUseCase) settle(ctx context.Context, id domain.PaymentRequestID, change func(*domain.PaymentRequest, time.Time) error) (*domain.PaymentRequest, error) {
+	if _, err := u.GetPaymentRequest(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var req *domain.PaymentRequest
+	paid := false
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		req, err = u.repo.GetPaymentRequestForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return domain.ErrPaymentRequestNotFound
+		}
+		if req.Status == domain.PaymentRequestStatusPending {
+			entry, err := u.transferUC.repo.FindByIdempotencyKey(ctx, req.TransferIdempotencyKey())
+			if err != nil {
+				return err
+			}
+			if entry != nil {
+				paid = true
+				if err := req.Accept(entry.ID, entry.Timestamp); err != nil {
+					return err
+				}
+				return u.repo.SavePaymentRequest(ctx, req)
+			}
+		}
+		if err := change(req, time.Now()); err != nil {
+			return err
+		}
+		return u.repo.SavePaymentRequest(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if paid {
+		return nil, domain.ErrPaymentRequestNotPending
+	}
+	return req, nil
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockPaymentRequestRepo struct {
+	requests map[domain.PaymentRequestID]*domain.PaymentRequest
+}
+
+func newMockPaymentRequestRepo() *mockPaymentRequestRepo {
+	return &mockPaymentRequestRepo{requests: make(map[domain.PaymentRequestID]*domain.PaymentRequest)}
+}
+
+func (m *mockPaymentRequestRepo) SavePaymentRequest(ctx context.Context, req *domain.PaymentRequest) error {
+	m.requests[req.ID] = req
+	return nil
+}
+
+func (m *mockPaymentRequestRepo) FindPaymentRequestByID(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	return m.requests[id], nil
+}
+
+func (m *mockPaymentRequestRepo) GetPaymentRequestForUpdate(ctx context.Context, id domain.PaymentRequestID) (*domain.PaymentRequest, error) {
+	return m.requests[id], nil
+}
+
+func (m *mockPaymentRequestRepo) FindPaymentRequestByIdempotencyKey(ctx context.Context, key string) (*domain.PaymentRequest, error) {
+	for _, req := range m.requests {
+		if req.IdempotencyKey == key {
+			return req, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPaymentRequestRepo) FindPaymentRequestsByAccountID(ctx context.Context, accountID domain.AccountID, status domain.PaymentRequestStatus, limit, offset int) ([]*domain.PaymentRequest, error) {
+	var res []*domain.PaymentRequest
+	for _, req := range m.requests {
+		if req.PayerAccountID != accountID && req.PayeeAccountID != accountID {
+			continue
+		}
+		if status == "" || req.Status == status {
+			res = append(res, req)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockPaymentRequestRepo) FindExpiredPaymentRequests(ctx context.Context, now time.Time, limit int) ([]*domain.PaymentRequest, error) {
+	var res []*domain.PaymentRequest
+	for _, req := range m.requests {
+		if req.Status == domain.PaymentRequestStatusPending && !req.ExpiresAt.After(now) && len(res) < limit {
+			res = append(res, req)
+		}
+	}
+	return res, nil
+}
+
+func TestPaymentRequestUseCase(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	uc := NewPaymentRequestUseCase(transferUC, accRepo, newMockPaymentRequestRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	payerID := domain.AccountID(mustUUID("pr-payer"))
+	payeeID := domain.AccountID(mustUUID("pr-payee"))
+	payer := domain.NewAccount(payerID, 0)
+	payer.Balance = 100
+	accRepo.SaveAccount(ctx, payer)
+	accRepo.SaveAccount(ctx, domain.NewAccount(payeeID, 0))
+
+	// Retries send the same expiry
+	expiresAt := time.Now().Add(time.Hour)
+	create := func(key string, amount int64) *domain.PaymentRequest {
+		t.Helper()
+		req, err := uc.CreatePaymentRequest(ctx, CreatePaymentRequestInput{
+			PayerAccountID: payerID,
+			PayeeAccountID: payeeID,
+			Amount:         amount,
+			Description:    "lunch",
+			ExpiresAt:      expiresAt,
+			IdempotencyKey: key,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		return req
+	}
+
+	t.Run("accept pays once", func(t *testing.T) {
+		req := create("pr-1", 30)
+		if again := create("pr-1", 30); again.ID != req.ID {
+			t.Errorf("retry created a second request")
+		}
+
+		accepted, err := uc.AcceptPaymentRequest(ctx, req.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if accepted.Status != domain.PaymentRequestStatusAccepted || accepted.JournalEntryID == nil {
+			t.Fatalf("unexpected request %+v", accepted)
+		}
+		entry, _ := txRepo.FindByIdempotencyKey(ctx, req.ID.String())
+		if entry == nil || entry.ID != *accepted.JournalEntryID {
+			t.Errorf("expected the transfer to use the request ID as idempotency key")
+		}
+
+		if _, err := uc.AcceptPaymentRequest(ctx, req.ID); err != nil {
+			t.Fatalf("unexpected error accepting again: %v", err)
+		}
+		if payer.Balance != 70 {
+			t.Errorf("expected balance 70, got %d", payer.Balance)
+		}
+		if _, err := uc.DeclinePaymentRequest(ctx, req.ID); err != domain.ErrPaymentRequestNotPending {
+			t.Errorf("expected ErrPaymentRequestNotPending, got %v", err)
+		}
+	})
+
+	t.Run("rejected transfer leaves the request pending", func(t *testing.T) {
+		req := create("pr-2", 500)
+		if _, err := uc.AcceptPaymentRequest(ctx, req.ID); !errors.Is(err, domain.ErrInsufficientBalance) {
+			t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+		}
+		if req.Status != domain.PaymentRequestStatusPending {
+			t.Errorf("expected pending, got %s", req.Status)
+		}
+		declined, err := uc.DeclinePaymentRequest(ctx, req.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if declined.Status != domain.PaymentRequestStatusDeclined {
+			t.Errorf("expected declined, got %s", declined.Status)
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		req := create("pr-3", 10)
+		n, err := uc.ExpireDue(ctx, req.ExpiresAt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 1 || req.Status != domain.PaymentRequestStatusExpired {
+			t.Fatalf("expected the request to expire, got %d expired and status %s", n, req.Status)
+		}
+		if _, err := uc.AcceptPaymentRequest(ctx, req.ID); err != domain.ErrPaymentRequestNotPending {
+			t.Errorf("expected ErrPaymentRequestNotPending, got %v", err)
+		}
+		if payer.Balance != 70 {
+			t.Errorf("expected balance 70, got %d", payer.Balance)
+		}
+	})
+
+	t.Run("expiry after a paid accept failed to save the request", func(t *testing.T) {
+		req := create("pr-4", 10)
+		out, err := transferUC.Transfer(ctx, TransferInput{
+			FromAccountID:  payerID,
+			ToAccountID:    payeeID,
+			Amount:         10,
+			Description:    "lunch",
+			IdempotencyKey: req.TransferIdempotencyKey(),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n, err := uc.ExpireDue(ctx, req.ExpiresAt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 0 || req.Status != domain.PaymentRequestStatusAccepted || req.JournalEntryID == nil || *req.JournalEntryID != out.JournalEntryID {
+			t.Fatalf("expected the request to be accepted with entry %s, got %d expired and %+v", out.JournalEntryID, n, req)
+		}
+		if payer.Balance != 60 {
+			t.Errorf("expected balance 60, got %d", payer.Balance)
+		}
+	})
+
+	pending, err := uc.ListPaymentRequests(ctx, payerID, domain.PaymentRequestStatusPending, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending requests, got %d", len(pending))
+	}
+	all, err := uc.ListPaymentRequests(ctx, payeeID, "", 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("expected 4 requests, got %d", len(all))
+	}
+}
+
+func TestPaymentRequestUseCase_CreatePaymentRequest_InvalidExpiry(t *testing.T) {
+	uc := NewPaymentRequestUseCase(nil, newMockAccountRepo(), newMockPaymentRequestRepo(), &mockTxManager{})
+	for _, expiresAt := range []time.Time{time.Now().Add(-time.Minute), time.Now().Add(MaxPaymentRequestDuration + time.Hour)} {
+		_, err := uc.CreatePaymentRequest(context.Background(), CreatePaymentRequestInput{
+			PayerAccountID: domain.AccountID(mustUUID("pr-payer")),
+			PayeeAccountID: domain.AccountID(mustUUID("pr-payee")),
+			Amount:         10,
+			ExpiresAt:      expiresAt,
+			IdempotencyKey: "pr-invalid",
+		})
+		if err != domain.ErrInvalidPaymentRequestExpiry {
+			t.Errorf("expected ErrInvalidPaymentRequestExpiry for %v, got %v", expiresAt, err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+const (
+	// expiredPointLotsBatchSize is the maximum number of lots ExpireLots expires per call
+	expiredPointLotsBatchSize = 100
+	// maxUpcomingExpirations is the maximum number of lots UpcomingExpirations returns
+	maxUpcomingExpirations = 100
+)
+
+var (
+	// errPointLotChanged aborts an expiry whose lot or account changed concurrently. The next sweep retries it.
+	errPointLotChanged = errors.New("point lot changed")
+	// errPointLotFrozen skips an expiry on a frozen account. The lot expires once the account is unfrozen.
+	errPointLotFrozen = errors.New("point lot account frozen")
+)
+
+// PointLotUseCase tracks credits of expiring assets as lots and expires them.
+// Use cases posting journal entries record lots through it when configured with one.
+type PointLotUseCase struct {
+	accountRepo domain.AccountRepository
+	repo        domain.JournalEntryRepository
+	assetRepo   domain.AssetRepository
+	lotRepo     domain.PointLotRepository
+	tm          domain.TransactionManager
+}
+
+func NewPointLotUseCase(
+	accountRepo domain.AccountRepository,
+	repo domain.JournalEntryRepository,
+	assetRepo domain.AssetRepository,
+	lotRepo domain.PointLotRepository,
+	tm domain.TransactionManager,
+) *PointLotUseCase {
+	return &PointLotUseCase{
+		accountRepo: accountRepo,
+		repo:        repo,
+		assetRepo:   assetRepo,
+		lotRepo:     lotRepo,
+		tm:          tm,
+	}
+}
+
+// UpcomingExpirations returns the lots of the account with points remaining, soonest expiry first.
+func (u *PointLotUseCase) UpcomingExpirations(ctx context.Context, accountID domain.AccountID) ([]*domain.PointLot, error) {
+	lots, err := u.lotRepo.FindOpenPointLots(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) > maxUpcomingExpirations {
+		lots = lots[:maxUpcomingExpirations]
+	}
+	return lots, nil
+}
+
+// ExpireLots expires the lots that expired at or before now and returns how many were expired.
+// Each lot is returned to the issuer account of its asset with an expiry entry, reducing the supply.
+// Lots of frozen accounts are skipped. A lot that fails to expire does not stop the others;
+// the errors are joined.
+func (u *PointLotUseCase) ExpireLots(ctx context.Context, now time.Time) (int, error) {
+	lots, err := u.lotRepo.FindExpiredPointLots(ctx, now, expiredPointLotsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, lot := range lots {
+		if err := u.expire(ctx, lot); err != nil {
+			// Spent from or frozen in the meantime
+			if errors.Is(err, errPointLotChanged) || errors.Is(err, errPointLotFrozen) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("point lot %s: %w", lot.ID, err))
+			continue
+		}
+		expired++
+	}
+	return expired, errors.Join(errs...)
+}
+
+// expire posts the remaining points of the lot to the issuer. Points of the lot that are
+// committed to holds are not expired; the lot is closed without them.
+func (u *PointLotUseCase) expire(ctx context.Context, lot *domain.PointLot) error {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, lot.Asset)
+	if err != nil {
+		return err
+	}
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+	if asset.IssuerAccountID == nil {
+		return domain.ErrIssuerNotConfigured
+	}
+	acc, err := u.accountRepo.FindAccountByID(ctx, lot.AccountID)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		return domain.ErrAccountNotFound
+	}
+	// The amount read here picks the posting below; it is checked again under the account lock.
+	amount := expirableAmount(lot, acc)
+
+	// closeLot re-reads the lot under the account lock and cuts it down to what is expired.
+	closeLot := func(ctx context.Context, locked *domain.Account) error {
+		if locked.Status == domain.AccountStatusFrozen {
+			return errPointLotFrozen
+		}
+		// A hold authorized or released since the amount was read changes what can expire.
+		if expirableAmount(lot, locked) != amount {
+			return errPointLotChanged
+		}
+		current, err := u.lotRepo.GetPointLot(ctx, lot.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Remaining != lot.Remaining {
+			return errPointLotChanged
+		}
+		current.Remaining = amount
+		return u.lotRepo.SavePointLot(ctx, current)
+	}
+
+	if amount == 0 {
+		return u.tm.Run(ctx, func(ctx context.Context) error {
+			accounts, err := lockAccounts(ctx, u.accountRepo, lot.AccountID)
+			if err != nil {
+				return err
+			}
+			return closeLot(ctx, accounts[lot.AccountID])
+		})
+	}
+
+	_, err = u.poster().post(ctx, movement{
+		Type:           domain.EntryTypeExpiry,
+		FromAccountID:  lot.AccountID,
+		ToAccountID:    *asset.IssuerAccountID,
+		Amount:         amount,
+		Description:    "points expired",
+		IdempotencyKey: lot.ExpiryIdempotencyKey(),
+		Fingerprint:    domain.RequestFingerprint("expiry", lot.ID),
+	}, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		if err := closeLot(ctx, from); err != nil {
+			return err
+		}
+		locked, err := u.assetRepo.GetAssetForUpdate(ctx, lot.Asset)
+		if err != nil {
+			return err
+		}
+		if locked == nil {
+			return domain.ErrAssetNotFound
+		}
+		// Points held since before supply was tracked may exceed it.
+		if n := min(amount, locked.Supply); n > 0 {
+			if err := locked.RemoveSupply(n); err != nil {
+				return err
+			}
+		}
+		return u.assetRepo.SaveAsset(ctx, locked)
+	})
+	return err
+}
+
+// expirableAmount returns how many of the lot's points can expire from acc: points committed
+// to holds are not expired.
+func expirableAmount(lot *domain.PointLot, acc *domain.Account) int64 {
+	return min(lot.Remaining, max(acc.AvailableBalance(), 0))
+}
+
+// track updates the lots for entry, posted between the locked accounts: the sender's lots
+// are consumed soonest expiry first, and a credit of an expiring asset opens a lot for the recipient.
+// The asset's issuer and escrow accounts hold no lots.
+func (u *PointLotUseCase) track(ctx context.Context, from, to *domain.Account, entry *domain.JournalEntry) error {
+	asset, err := u.assetRepo.FindAssetByCode(ctx, from.Asset)
+	if err != nil {
+		return err
+	}
+	// Accounts of unregistered assets never have lots.
+	if asset == nil {
+		return nil
+	}
+
+	if !asset.IsSystemAccount(from.ID) {
+		lots, err := u.lotRepo.FindOpenPointLots(ctx, from.ID)
+		if err != nil {
+			return err
+		}
+		for _, lot := range domain.ConsumeLots(lots, entry.Amount) {
+			if err := u.lotRepo.SavePointLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+	}
+
+	expiresAt := asset.PointExpiry(entry.Timestamp)
+	if expiresAt.IsZero() || asset.IsSystemAccount(to.ID) {
+		return nil
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	return u.lotRepo.SavePointLot(ctx, &domain.PointLot{
+		ID:             domain.PointLotID(id),
+		AccountID:      to.ID,
+		Asset:          asset.Code,
+		Amount:         entry.Amount,
+		Remaining:      entry.Amount,
+		ExpiresAt:      expiresAt,
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	})
+}
+
+func (u *PointLotUseCase) poster() *poster {
+	return &poster{accountRepo: u.accountRepo, repo: u.repo, tm: u.tm, lots: u}
+}
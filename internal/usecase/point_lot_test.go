@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockPointLotRepo struct {
+	lots []*domain.PointLot
+}
+
+func (m *mockPointLotRepo) SavePointLot(ctx context.Context, lot *domain.PointLot) error {
+	for i, l := range m.lots {
+		if l.ID == lot.ID {
+			m.lots[i] = lot
+			return nil
+		}
+	}
+	m.lots = append(m.lots, lot)
+	return nil
+}
+
+func (m *mockPointLotRepo) GetPointLot(ctx context.Context, id domain.PointLotID) (*domain.PointLot, error) {
+	for _, l := range m.lots {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPointLotRepo) FindOpenPointLots(ctx context.Context, accountID domain.AccountID) ([]*domain.PointLot, error) {
+	return m.find(func(l *domain.PointLot) bool { return l.AccountID == accountID }), nil
+}
+
+func (m *mockPointLotRepo) FindExpiredPointLots(ctx context.Context, now time.Time, limit int) ([]*domain.PointLot, error) {
+	lots := m.find(func(l *domain.PointLot) bool { return !l.ExpiresAt.After(now) })
+	if len(lots) > limit {
+		lots = lots[:limit]
+	}
+	return lots, nil
+}
+
+func (m *mockPointLotRepo) find(match func(*domain.PointLot) bool) []*domain.PointLot {
+	var lots []*domain.PointLot
+	for _, l := range m.lots {
+		if l.Remaining > 0 && match(l) {
+			lots = append(lots, l)
+		}
+	}
+	slices.SortFunc(lots, func(a, b *domain.PointLot) int {
+		if c := a.ExpiresAt.Compare(b.ExpiresAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return lots
+}
+
+func TestPointLotUseCase_ExpireLots(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	assetRepo := newMockAssetRepo()
+	lotRepo := &mockPointLotRepo{}
+	tm := &mockTxManager{}
+	lotUC := NewPointLotUseCase(accRepo, txRepo, assetRepo, lotRepo, tm)
+	issuanceUC := NewIssuanceUseCase(accRepo, txRepo, assetRepo, tm, lotUC)
+	transferUC := NewTransferUseCase(accRepo, txRepo, tm, WithPointLots(lotUC))
+	holdUC := NewHoldUseCase(accRepo, txRepo, newMockHoldRepo(), tm, lotUC)
+	assetUC := NewAssetUseCase(assetRepo, accRepo, tm)
+	ctx := context.Background()
+
+	issuerID := domain.AccountID(mustUUID("issuer"))
+	aliceID := domain.AccountID(mustUUID("alice"))
+	bobID := domain.AccountID(mustUUID("bob"))
+	issuer := domain.NewAccount(issuerID, domain.UnlimitedCreditLimit)
+	alice := domain.NewAccount(aliceID, 0)
+	bob := domain.NewAccount(bobID, 0)
+	for _, acc := range []*domain.Account{issuer, alice, bob} {
+		acc.Asset = domain.DefaultAssetCode
+		accRepo.SaveAccount(ctx, acc)
+	}
+	if _, err := assetUC.SetAssetIssuer(ctx, domain.DefaultAssetCode, issuerID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := assetUC.SetAssetPointTTL(ctx, domain.DefaultAssetCode, 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mint := func(key string, amount int64) {
+		t.Helper()
+		if _, err := issuanceUC.Mint(ctx, MintInput{Asset: domain.DefaultAssetCode, ToAccountID: aliceID, Amount: amount, IdempotencyKey: key}); err != nil {
+			t.Fatalf("unexpected error minting: %v", err)
+		}
+	}
+	mint("mint-1", 100)
+	first := lotRepo.lots[0]
+	mint("mint-2", 50)
+	second := lotRepo.lots[1]
+	// The second lot expires later
+	second.ExpiresAt = second.ExpiresAt.Add(time.Hour)
+
+	// Spending consumes the lot expiring first and gives the recipient a lot of its own
+	if _, err := transferUC.Transfer(ctx, TransferInput{FromAccountID: aliceID, ToAccountID: bobID, Amount: 30, IdempotencyKey: "t-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Remaining != 70 || second.Remaining != 50 {
+		t.Errorf("expected remaining 70/50, got %d/%d", first.Remaining, second.Remaining)
+	}
+	upcoming, err := lotUC.UpcomingExpirations(ctx, bobID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upcoming) != 1 || upcoming[0].Remaining != 30 {
+		t.Errorf("expected one lot of 30 for bob, got %v", upcoming)
+	}
+
+	// 20 of alice's points are committed to a hold and do not expire
+	if _, err := holdUC.Authorize(ctx, AuthorizeInput{AccountID: aliceID, ToAccountID: bobID, Amount: 100, IdempotencyKey: "h-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := first.ExpiresAt.Add(time.Minute)
+	n, err := lotUC.ExpireLots(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Alice's first lot, cut down to the 20 points available, and bob's lot
+	if n != 2 {
+		t.Errorf("expected 2 expired lots, got %d", n)
+	}
+	if alice.Balance != 100 || bob.Balance != 0 {
+		t.Errorf("expected balances 100/0, got %d/%d", alice.Balance, bob.Balance)
+	}
+	if first.Remaining != 0 {
+		t.Errorf("expected the first lot to be closed, got %d", first.Remaining)
+	}
+	asset, _ := assetRepo.FindAssetByCode(ctx, domain.DefaultAssetCode)
+	if asset.Supply != 100 {
+		t.Errorf("expected supply 100, got %d", asset.Supply)
+	}
+	entry := txRepo.idempotency[first.ExpiryIdempotencyKey()]
+	if entry == nil || entry.Type != domain.EntryTypeExpiry || entry.ToAccountID != issuerID || entry.Amount != 20 {
+		t.Errorf("unexpected expiry entry %+v", entry)
+	}
+
+	// Nothing left to expire
+	if n, err := lotUC.ExpireLots(ctx, now); err != nil || n != 0 {
+		t.Errorf("expected nothing to expire, got %d, %v", n, err)
+	}
+}
+
+func TestPointLotUseCase_ExpireLots_FrozenAccount(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	assetRepo := newMockAssetRepo()
+	lotRepo := &mockPointLotRepo{}
+	tm := &mockTxManager{}
+	lotUC := NewPointLotUseCase(accRepo, txRepo, assetRepo, lotRepo, tm)
+	issuanceUC := NewIssuanceUseCase(accRepo, txRepo, assetRepo, tm, lotUC)
+	assetUC := NewAssetUseCase(assetRepo, accRepo, tm)
+	ctx := context.Background()
+
+	issuerID := domain.AccountID(mustUUID("issuer"))
+	aliceID := domain.AccountID(mustUUID("alice"))
+	bobID := domain.AccountID(mustUUID("bob"))
+	issuer := domain.NewAccount(issuerID, 0)
+	alice := domain.NewAccount(aliceID, 0)
+	bob := domain.NewAccount(bobID, 0)
+	for _, acc := range []*domain.Account{issuer, alice, bob} {
+		acc.Asset = domain.DefaultAssetCode
+		accRepo.SaveAccount(ctx, acc)
+	}
+	if _, err := assetUC.SetAssetIssuer(ctx, domain.DefaultAssetCode, issuerID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := assetUC.SetAssetPointTTL(ctx, domain.DefaultAssetCode, 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []domain.AccountID{aliceID, bobID} {
+		if _, err := issuanceUC.Mint(ctx, MintInput{Asset: domain.DefaultAssetCode, ToAccountID: id, Amount: 100, IdempotencyKey: "mint-" + id.String()}); err != nil {
+			t.Fatalf("unexpected error minting: %v", err)
+		}
+	}
+	if err := alice.Freeze(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Alice's lot waits for her account to be unfrozen and does not hold up bob's
+	now := lotRepo.lots[1].ExpiresAt.Add(time.Minute)
+	n, err := lotUC.ExpireLots(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 expired lot, got %d", n)
+	}
+	if alice.Balance != 100 || bob.Balance != 0 {
+		t.Errorf("expected balances 100/0, got %d/%d", alice.Balance, bob.Balance)
+	}
+
+	if err := alice.Unfreeze(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, err := lotUC.ExpireLots(ctx, now); err != nil || n != 1 {
+		t.Errorf("expected 1 expired lot, got %d, %v", n, err)
+	}
+	if alice.Balance != 0 {
+		t.Errorf("expected alice's points to expire, got %d", alice.Balance)
+	}
+}
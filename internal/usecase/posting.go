@@ -0,0 +1,420 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// journalChainLock is the named lock serializing appends to the journal hash chain.
+const journalChainLock = "journal_entry_chain"
+
+// errMissingFingerprint rejects a posting without a request fingerprint, whose key any request could replay.
+var errMissingFingerprint = errors.New("request fingerprint missing")
+
+// movement describes points moving between two accounts as a single journal entry.
+type movement struct {
+	Type           domain.EntryType
+	FromAccountID  domain.AccountID
+	ToAccountID    domain.AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+	// ReversesID is set for reversals.
+	ReversesID *domain.JournalEntryID
+	// EscrowID is set for movements funding or settling an escrow.
+	EscrowID *domain.EscrowID
+	Metadata map[string]string
+	// Fingerprint identifies the request, see domain.RequestFingerprint. Replays of the key with
+	// another fingerprint fail. The legs of a group take the fingerprint of the group.
+	Fingerprint string
+}
+
+func (m movement) validate() error {
+	if err := validateIdempotencyKey(m.IdempotencyKey); err != nil {
+		return err
+	}
+	return m.validateLeg()
+}
+
+// validateLeg checks everything but the idempotency key, which the legs of a group derive from the group key.
+func (m movement) validateLeg() error {
+	if err := m.validateAmount(); err != nil {
+		return err
+	}
+	if len(m.Description) > MaxDescriptionLength {
+		return domain.ErrDescriptionTooLong
+	}
+	return domain.ValidateMetadata(m.Metadata)
+}
+
+// validateAmount checks the amount and the accounts, but not the idempotency key and the description.
+func (m movement) validateAmount() error {
+	if m.Amount <= 0 {
+		return domain.ErrInvalidAmount
+	}
+	if m.Amount > MaxTransferAmount {
+		return domain.ErrAmountTooLarge
+	}
+	if m.FromAccountID == m.ToAccountID {
+		return domain.ErrSelfTransfer
+	}
+	return nil
+}
+
+// validateIdempotencyKey checks a client-supplied idempotency key. '#' is reserved for the keys
+// of group legs, so that no client key can collide with one, see domain.LegIdempotencyKey.
+func validateIdempotencyKey(key string) error {
+	if strings.TrimSpace(key) == "" || len(key) > MaxIdempotencyKeyLength || strings.Contains(key, "#") {
+		return domain.ErrInvalidIdempotencyKey
+	}
+	return nil
+}
+
+// movementGroup describes movements that are applied all together or not at all.
+type movementGroup struct {
+	IdempotencyKey string
+	Description    string
+	// Fingerprint identifies the request, see domain.RequestFingerprint. Replays of the key with
+	// another fingerprint fail.
+	Fingerprint string
+	// Legs get their idempotency keys derived from the group key.
+	Legs []movement
+}
+
+// legs returns the legs with derived idempotency keys, validated.
+func (g movementGroup) legs() ([]movement, error) {
+	if len(g.Legs) == 0 || len(g.Legs) > MaxBatchLegs {
+		return nil, domain.ErrInvalidBatchSize
+	}
+	if err := validateIdempotencyKey(g.IdempotencyKey); err != nil {
+		return nil, err
+	}
+	if len(domain.LegIdempotencyKey(g.IdempotencyKey, len(g.Legs)-1)) > MaxIdempotencyKeyLength {
+		return nil, domain.ErrInvalidIdempotencyKey
+	}
+	if len(g.Description) > MaxDescriptionLength {
+		return nil, domain.ErrDescriptionTooLong
+	}
+
+	legs := make([]movement, len(g.Legs))
+	for i, leg := range g.Legs {
+		if err := leg.validateLeg(); err != nil {
+			return nil, legError(i, err)
+		}
+		leg.IdempotencyKey = domain.LegIdempotencyKey(g.IdempotencyKey, i)
+		leg.Fingerprint = g.Fingerprint
+		legs[i] = leg
+	}
+	return legs, nil
+}
+
+// legError tells which leg of a group failed. The cause stays matchable with errors.Is.
+func legError(i int, err error) error {
+	return fmt.Errorf("leg %d: %w", i, err)
+}
+
+// movementCheck runs after the accounts of a movement are locked and before balances change.
+// m is the movement, or the leg of a group, being applied. Returning an error aborts the posting.
+type movementCheck func(ctx context.Context, m movement, from, to *domain.Account) error
+
+// poster appends movements to the journal and applies them to account balances.
+type poster struct {
+	accountRepo domain.AccountRepository
+	repo        domain.JournalEntryRepository
+	tm          domain.TransactionManager
+	// lots, if not nil, tracks the lots of expiring points.
+	lots *PointLotUseCase
+}
+
+// post validates and performs m atomically and returns its journal entry.
+// If an entry with the same idempotency key exists, it is returned and nothing is applied,
+// unless it was posted by a request with a different fingerprint. check may be nil.
+func (p *poster) post(ctx context.Context, m movement, check movementCheck) (*domain.JournalEntry, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if m.Fingerprint == "" {
+		return nil, errMissingFingerprint
+	}
+
+	// 1. Idempotency Check (Quick check before TX)
+	existing, err := p.repo.FindByIdempotencyKey(ctx, m.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, checkReplay(m.IdempotencyKey, m.Fingerprint, existing.RequestFingerprint, existing.ID)
+	}
+
+	var newEntry *domain.JournalEntry
+
+	// 2. Atomic Transaction with Global Serialization for Hash Chain
+	err = p.tm.RunSerialized(ctx, journalChainLock, func(ctx context.Context) error {
+		return p.tm.Run(ctx, func(ctx context.Context) error {
+			// Re-check idempotency inside TX (double check locking)
+			existing, err := p.repo.FindByIdempotencyKey(ctx, m.IdempotencyKey)
+			if err == nil && existing != nil {
+				newEntry = existing
+				return checkReplay(m.IdempotencyKey, m.Fingerprint, existing.RequestFingerprint, existing.ID)
+			}
+
+			accounts, err := lockAccounts(ctx, p.accountRepo, m.FromAccountID, m.ToAccountID)
+			if err != nil {
+				return err
+			}
+			if err := applyMovement(ctx, accounts, m, check); err != nil {
+				return err
+			}
+			newEntry, err = newJournalEntry(m)
+			if err != nil {
+				return err
+			}
+			if err := p.trackLots(ctx, accounts, newEntry); err != nil {
+				return err
+			}
+
+			// Save All
+			if err := saveAccounts(ctx, p.accountRepo, accounts); err != nil {
+				return err
+			}
+			return appendJournalEntries(ctx, p.repo, newEntry)
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return newEntry, nil
+}
+
+// postGroup performs all legs of g in one transaction and records them as one journal group.
+// If a group with the same idempotency key exists, it is returned and nothing is applied,
+// unless it was posted by a request with a different fingerprint.
+// check, if not nil, runs for every leg.
+func (p *poster) postGroup(ctx context.Context, g movementGroup, check movementCheck) (*domain.JournalGroup, error) {
+	legs, err := g.legs()
+	if err != nil {
+		return nil, err
+	}
+	if g.Fingerprint == "" {
+		return nil, errMissingFingerprint
+	}
+
+	existing, err := p.repo.FindJournalGroupByIdempotencyKey(ctx, g.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, checkGroupReplay(g.IdempotencyKey, g.Fingerprint, existing)
+	}
+
+	var group *domain.JournalGroup
+
+	err = p.tm.RunSerialized(ctx, journalChainLock, func(ctx context.Context) error {
+		return p.tm.Run(ctx, func(ctx context.Context) error {
+			existing, err := p.repo.FindJournalGroupByIdempotencyKey(ctx, g.IdempotencyKey)
+			if err == nil && existing != nil {
+				group = existing
+				return checkGroupReplay(g.IdempotencyKey, g.Fingerprint, existing)
+			}
+
+			ids := make([]domain.AccountID, 0, 2*len(legs))
+			for _, leg := range legs {
+				ids = append(ids, leg.FromAccountID, leg.ToAccountID)
+			}
+			accounts, err := lockAccounts(ctx, p.accountRepo, ids...)
+			if err != nil {
+				return err
+			}
+
+			groupID, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			group = &domain.JournalGroup{
+				ID:                 domain.JournalGroupID(groupID),
+				IdempotencyKey:     g.IdempotencyKey,
+				RequestFingerprint: g.Fingerprint,
+				Description:        g.Description,
+				CreatedAt:          time.Now(),
+			}
+
+			// Legs apply in order, so a leg may spend points received by an earlier one.
+			for i, leg := range legs {
+				if err := applyMovement(ctx, accounts, leg, check); err != nil {
+					return legError(i, err)
+				}
+				if leg.Description == "" {
+					leg.Description = g.Description
+				}
+				entry, err := newJournalEntry(leg)
+				if err != nil {
+					return err
+				}
+				if err := p.trackLots(ctx, accounts, entry); err != nil {
+					return legError(i, err)
+				}
+				entry.GroupID = &group.ID
+				group.Entries = append(group.Entries, entry)
+			}
+
+			if err := saveAccounts(ctx, p.accountRepo, accounts); err != nil {
+				return err
+			}
+			if err := p.repo.SaveJournalGroup(ctx, group); err != nil {
+				return err
+			}
+			return appendJournalEntries(ctx, p.repo, group.Entries...)
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// checkReplay returns an *IdempotencyKeyReusedError if what was posted or created with key came
+// from a request with another fingerprint. existingID is the entry it posted, zero if none.
+// Every keyed request records its fingerprint, so an existing fingerprint is only missing for
+// rows written before fingerprints were recorded; those are not checked.
+func checkReplay(key, fingerprint, existingFingerprint string, existingID domain.JournalEntryID) error {
+	if existingFingerprint == "" || fingerprint == existingFingerprint {
+		return nil
+	}
+	return &domain.IdempotencyKeyReusedError{Key: key, JournalEntryID: existingID}
+}
+
+// checkGroupReplay is checkReplay for a group found by key.
+func checkGroupReplay(key, fingerprint string, existing *domain.JournalGroup) error {
+	var firstID domain.JournalEntryID
+	if len(existing.Entries) > 0 {
+		firstID = existing.Entries[0].ID
+	}
+	return checkReplay(key, fingerprint, existing.RequestFingerprint, firstID)
+}
+
+// trackLots updates the point lots for the entry posted between the locked accounts.
+func (p *poster) trackLots(ctx context.Context, accounts map[domain.AccountID]*domain.Account, entry *domain.JournalEntry) error {
+	if p.lots == nil {
+		return nil
+	}
+	return p.lots.track(ctx, accounts[entry.FromAccountID], accounts[entry.ToAccountID], entry)
+}
+
+// applyMovement moves m.Amount between the locked accounts.
+func applyMovement(ctx context.Context, accounts map[domain.AccountID]*domain.Account, m movement, check movementCheck) error {
+	from, to := accounts[m.FromAccountID], accounts[m.ToAccountID]
+
+	if from.Asset != to.Asset {
+		return domain.ErrAssetMismatch
+	}
+	// Points leave or enter the issuer and escrow accounts only through the entries that account for them.
+	if (from.System || to.System) && !m.Type.MovesSystemAccounts() {
+		return domain.ErrSystemAccount
+	}
+	if check != nil {
+		if err := check(ctx, m, from, to); err != nil {
+			return err
+		}
+	}
+
+	if err := from.Withdraw(m.Amount); err != nil {
+		return err
+	}
+	return to.Deposit(m.Amount)
+}
+
+func newJournalEntry(m movement) (*domain.JournalEntry, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	return &domain.JournalEntry{
+		ID:             domain.JournalEntryID(id),
+		Type:           m.Type,
+		FromAccountID:  m.FromAccountID,
+		ToAccountID:    m.ToAccountID,
+		Amount:         m.Amount,
+		Description:    m.Description,
+		IdempotencyKey: m.IdempotencyKey,
+		ReversesID:     m.ReversesID,
+		EscrowID:       m.EscrowID,
+		Metadata:       m.Metadata,
+		Timestamp:      time.Now(),
+		// Legs of a group carry the fingerprint of the group, which records it as well.
+		RequestFingerprint: m.Fingerprint,
+	}, nil
+}
+
+// lockAccounts loads the accounts with row locks, acquired in lexical ID order
+// so that concurrent postings cannot deadlock. Missing accounts yield ErrAccountNotFound.
+func lockAccounts(ctx context.Context, accountRepo domain.AccountRepository, ids ...domain.AccountID) (map[domain.AccountID]*domain.Account, error) {
+	sorted := make([]domain.AccountID, len(ids))
+	copy(sorted, ids)
+	slices.SortFunc(sorted, func(a, b domain.AccountID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	accounts := make(map[domain.AccountID]*domain.Account, len(sorted))
+	for _, id := range sorted {
+		if _, ok := accounts[id]; ok {
+			continue
+		}
+		acc, err := accountRepo.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			return nil, domain.ErrAccountNotFound
+		}
+		accounts[id] = acc
+	}
+	return accounts, nil
+}
+
+// saveAccounts saves the locked accounts in lexical ID order.
+func saveAccounts(ctx context.Context, accountRepo domain.AccountRepository, accounts map[domain.AccountID]*domain.Account) error {
+	ids := make([]domain.AccountID, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b domain.AccountID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, id := range ids {
+		if err := accountRepo.SaveAccount(ctx, accounts[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendJournalEntries links the entries, in order, to the end of the hash chain, computes their hashes and saves them.
+// The caller must hold journalChainLock.
+func appendJournalEntries(ctx context.Context, repo domain.JournalEntryRepository, entries ...*domain.JournalEntry) error {
+	latestEntry, err := repo.GetLatestJournalEntry(ctx)
+	if err != nil {
+		return err
+	}
+	previousHash := ""
+	if latestEntry != nil {
+		previousHash = latestEntry.Hash
+	}
+	for _, entry := range entries {
+		entry.PreviousHash = previousHash
+		entry.Hash = entry.ComputeHash()
+		if err := repo.SaveJournalEntry(ctx, entry); err != nil {
+			return err
+		}
+		previousHash = entry.Hash
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+func TestTransferUseCase_ReverseJournalEntry(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("acc-from"))
+	toID := domain.AccountID(mustUUID("acc-to"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(toID, 0)
+	accRepo.SaveAccount(ctx, to)
+
+	out, err := uc.Transfer(ctx, TransferInput{FromAccountID: fromID, ToAccountID: toID, Amount: 500, IdempotencyKey: "mistake"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tooMuch := int64(501)
+	_, err = uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: out.JournalEntryID, Amount: &tooMuch, IdempotencyKey: "rev-0"})
+	if err != domain.ErrReversalExceedsOriginal {
+		t.Errorf("expected ErrReversalExceedsOriginal, got %v", err)
+	}
+
+	partial := int64(200)
+	input := ReverseJournalEntryInput{JournalEntryID: out.JournalEntryID, Amount: &partial, IdempotencyKey: "rev-1"}
+	rev, err := uc.ReverseJournalEntry(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 700 || to.Balance != 300 {
+		t.Errorf("expected balances 700/300, got %d/%d", from.Balance, to.Balance)
+	}
+
+	original, _ := txRepo.FindJournalEntryByID(ctx, out.JournalEntryID)
+	if len(original.ReversedByIDs) != 1 || original.ReversedByIDs[0] != rev.JournalEntryID || original.ReversedAmount != 200 {
+		t.Errorf("expected original to link to reversal %s, got %v", rev.JournalEntryID, original.ReversedByIDs)
+	}
+	reversal, _ := txRepo.FindJournalEntryByID(ctx, rev.JournalEntryID)
+	if reversal.Type != domain.EntryTypeReversal || reversal.ReversesID == nil || *reversal.ReversesID != out.JournalEntryID {
+		t.Errorf("unexpected reversal entry: %+v", reversal)
+	}
+
+	// Replaying the same request is idempotent
+	again, err := uc.ReverseJournalEntry(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.JournalEntryID != rev.JournalEntryID {
+		t.Errorf("expected reversal %s, got %s", rev.JournalEntryID, again.JournalEntryID)
+	}
+
+	// The rest can be reversed in further parts, but not more than the rest
+	_, err = uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: out.JournalEntryID, IdempotencyKey: "rev-2"})
+	if err != domain.ErrReversalExceedsOriginal {
+		t.Errorf("expected ErrReversalExceedsOriginal, got %v", err)
+	}
+	rest := int64(300)
+	if _, err := uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: out.JournalEntryID, Amount: &rest, IdempotencyKey: "rev-5"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 1000 || to.Balance != 0 {
+		t.Errorf("expected balances 1000/0, got %d/%d", from.Balance, to.Balance)
+	}
+	if len(original.ReversedByIDs) != 2 || original.ReversedAmount != 500 {
+		t.Errorf("expected two reversals of 500 in total, got %v/%d", original.ReversedByIDs, original.ReversedAmount)
+	}
+	one := int64(1)
+	_, err = uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: out.JournalEntryID, Amount: &one, IdempotencyKey: "rev-6"})
+	if err != domain.ErrJournalEntryAlreadyReversed {
+		t.Errorf("expected ErrJournalEntryAlreadyReversed, got %v", err)
+	}
+	_, err = uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: rev.JournalEntryID, IdempotencyKey: "rev-3"})
+	if err != domain.ErrJournalEntryNotReversible {
+		t.Errorf("expected ErrJournalEntryNotReversible, got %v", err)
+	}
+	_, err = uc.ReverseJournalEntry(ctx, ReverseJournalEntryInput{JournalEntryID: domain.JournalEntryID(uuid.New()), IdempotencyKey: "rev-4"})
+	if err != domain.ErrJournalEntryNotFound {
+		t.Errorf("expected ErrJournalEntryNotFound, got %v", err)
+	}
+}
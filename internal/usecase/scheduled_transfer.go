@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// dueScheduledTransfersBatchSize is the maximum number of scheduled transfers ExecuteDue runs per call
+const dueScheduledTransfersBatchSize = 100
+
+// ScheduledTransferUseCase queues transfers and executes them once they are due.
+type ScheduledTransferUseCase struct {
+	transferUC  *TransferUseCase
+	accountRepo domain.AccountRepository
+	repo        domain.ScheduledTransferRepository
+	tm          domain.TransactionManager
+}
+
+func NewScheduledTransferUseCase(
+	transferUC *TransferUseCase,
+	accountRepo domain.AccountRepository,
+	repo domain.ScheduledTransferRepository,
+	tm domain.TransactionManager,
+) *ScheduledTransferUseCase {
+	return &ScheduledTransferUseCase{
+		transferUC:  transferUC,
+		accountRepo: accountRepo,
+		repo:        repo,
+		tm:          tm,
+	}
+}
+
+type ScheduleTransferInput struct {
+	FromAccountID  domain.AccountID
+	ToAccountID    domain.AccountID
+	Amount         int64
+	Description    string
+	IdempotencyKey string
+	ExecuteAt      time.Time
+}
+
+// ScheduleTransfer queues a transfer for execution at ExecuteAt.
+// Balances are checked when the transfer executes, not when it is scheduled.
+// Replays of the key with other accounts, amount, description or execution time fail with
+// an *IdempotencyKeyReusedError.
+func (u *ScheduledTransferUseCase) ScheduleTransfer(ctx context.Context, input ScheduleTransferInput) (*domain.ScheduledTransfer, error) {
+	m := movement{
+		FromAccountID:  input.FromAccountID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !input.ExecuteAt.After(now) {
+		return nil, domain.ErrInvalidExecuteAt
+	}
+	fingerprint := domain.RequestFingerprint("scheduled_transfer", input.FromAccountID, input.ToAccountID,
+		input.Amount, input.Description, input.ExecuteAt)
+
+	existing, err := u.repo.FindScheduledTransferByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := checkReplay(input.IdempotencyKey, fingerprint, existing.RequestFingerprint, domain.JournalEntryID{}); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if err := checkFutureTransferAccounts(ctx, u.accountRepo, input.FromAccountID, input.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	st := &domain.ScheduledTransfer{
+		ID:                 domain.ScheduledTransferID(id),
+		FromAccountID:      input.FromAccountID,
+		ToAccountID:        input.ToAccountID,
+		Amount:             input.Amount,
+		Description:        input.Description,
+		IdempotencyKey:     input.IdempotencyKey,
+		RequestFingerprint: fingerprint,
+		ExecuteAt:          input.ExecuteAt,
+		Status:             domain.ScheduledTransferStatusPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := u.repo.SaveScheduledTransfer(ctx, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (u *ScheduledTransferUseCase) GetScheduledTransfer(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	st, err := u.repo.FindScheduledTransferByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, domain.ErrScheduledTransferNotFound
+	}
+	return st, nil
+}
+
+// ListScheduledTransfers returns the scheduled transfers from or to the account.
+func (u *ScheduledTransferUseCase) ListScheduledTransfers(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.ScheduledTransfer, error) {
+	limit, offset = normalizePage(limit, offset)
+	return u.repo.FindScheduledTransfersByAccountID(ctx, accountID, limit, offset)
+}
+
+// CancelScheduledTransfer cancels a pending scheduled transfer.
+func (u *ScheduledTransferUseCase) CancelScheduledTransfer(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	if _, err := u.GetScheduledTransfer(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var st *domain.ScheduledTransfer
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		st, err = u.repo.GetScheduledTransferForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			return domain.ErrScheduledTransferNotFound
+		}
+		if err := st.Cancel(time.Now()); err != nil {
+			return err
+		}
+		return u.repo.SaveScheduledTransfer(ctx, st)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// ExecuteDue executes the pending transfers due at now and returns how many were settled.
+// Transfers rejected by the ledger are marked failed with the reason; other errors leave them pending
+// for a retry without holding up the other transfers, and are returned together.
+func (u *ScheduledTransferUseCase) ExecuteDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := u.repo.FindDueScheduledTransfers(ctx, now, dueScheduledTransfersBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	var errs []error
+	for _, st := range due {
+		if err := u.execute(ctx, st.ID); err != nil {
+			// Canceled in the meantime
+			if errors.Is(err, domain.ErrScheduledTransferNotPending) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("scheduled transfer %s: %w", st.ID, err))
+			continue
+		}
+		settled++
+	}
+	return settled, errors.Join(errs...)
+}
+
+// execute runs the transfer while holding the row lock, so that it cannot be canceled halfway.
+// The transfer commits on its own; if settling the schedule fails afterwards,
+// the next attempt finds the entry by the derived idempotency key instead of paying twice.
+func (u *ScheduledTransferUseCase) execute(ctx context.Context, id domain.ScheduledTransferID) error {
+	return u.tm.Run(ctx, func(ctx context.Context) error {
+		st, err := u.repo.GetScheduledTransferForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			return domain.ErrScheduledTransferNotFound
+		}
+		if st.Status != domain.ScheduledTransferStatusPending {
+			return domain.ErrScheduledTransferNotPending
+		}
+
+		out, err := u.transferUC.Transfer(ctx, TransferInput{
+			FromAccountID:  st.FromAccountID,
+			ToAccountID:    st.ToAccountID,
+			Amount:         st.Amount,
+			Description:    st.Description,
+			IdempotencyKey: st.TransferIdempotencyKey(),
+		})
+		switch {
+		case err == nil:
+			err = st.MarkExecuted(out.JournalEntryID, time.Now())
+		case isTransferRejection(err):
+			err = st.MarkFailed(err.Error(), time.Now())
+		}
+		if err != nil {
+			return err
+		}
+		return u.repo.SaveScheduledTransfer(ctx, st)
+	})
+}
+
+// checkFutureTransferAccounts rejects transfers queued for later that could never succeed.
+// Balances and statuses are checked when the transfer executes.
+func checkFutureTransferAccounts(ctx context.Context, accountRepo domain.AccountRepository, fromID, toID domain.AccountID) error {
+	from, err := accountRepo.FindAccountByID(ctx, fromID)
+	if err != nil {
+		return err
+	}
+	to, err := accountRepo.FindAccountByID(ctx, toID)
+	if err != nil {
+		return err
+	}
+	if from == nil || to == nil {
+		return domain.ErrAccountNotFound
+	}
+	if from.Asset != to.Asset {
+		return domain.ErrAssetMismatch
+	}
+	return nil
+}
+
+// transferRejections are the errors for which retrying the same transfer later is pointless.
+var transferRejections = []error{
+	domain.ErrAccountNotFound,
+	domain.ErrInsufficientBalance,
+	domain.ErrBalanceOverflow,
+	domain.ErrMaxBalanceExceeded,
+	domain.ErrAccountFrozen,
+	domain.ErrAccountClosed,
+	domain.ErrAssetMismatch,
+	domain.ErrIntraTreeTransfer,
+	domain.ErrSystemAccount,
+	// Retrying at the next tick would mostly hit the limit again, and a transfer above
+	// the per-transfer limit never passes.
+	domain.ErrVelocityLimitExceeded,
+}
+
+func isTransferRejection(err error) bool {
+	for _, target := range transferRejections {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
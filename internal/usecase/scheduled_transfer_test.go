@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockScheduledTransferRepo struct {
+	scheduled map[domain.ScheduledTransferID]*domain.ScheduledTransfer
+}
+
+func newMockScheduledTransferRepo() *mockScheduledTransferRepo {
+	return &mockScheduledTransferRepo{scheduled: make(map[domain.ScheduledTransferID]*domain.ScheduledTransfer)}
+}
+
+func (m *mockScheduledTransferRepo) SaveScheduledTransfer(ctx context.Context, st *domain.ScheduledTransfer) error {
+	m.scheduled[st.ID] = st
+	return nil
+}
+
+func (m *mockScheduledTransferRepo) FindScheduledTransferByID(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	return m.scheduled[id], nil
+}
+
+func (m *mockScheduledTransferRepo) GetScheduledTransferForUpdate(ctx context.Context, id domain.ScheduledTransferID) (*domain.ScheduledTransfer, error) {
+	return m.scheduled[id], nil
+}
+
+func (m *mockScheduledTransferRepo) FindScheduledTransferByIdempotencyKey(ctx context.Context, key string) (*domain.ScheduledTransfer, error) {
+	for _, st := range m.scheduled {
+		if st.IdempotencyKey == key {
+			return st, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockScheduledTransferRepo) FindScheduledTransfersByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.ScheduledTransfer, error) {
+	var res []*domain.ScheduledTransfer
+	for _, st := range m.scheduled {
+		if st.FromAccountID == accountID || st.ToAccountID == accountID {
+			res = append(res, st)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockScheduledTransferRepo) FindDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledTransfer, error) {
+	var res []*domain.ScheduledTransfer
+	for _, st := range m.scheduled {
+		if st.Status == domain.ScheduledTransferStatusPending && !st.ExecuteAt.After(now) && len(res) < limit {
+			res = append(res, st)
+		}
+	}
+	return res, nil
+}
+
+func TestScheduledTransferUseCase_ExecuteDue(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	uc := NewScheduledTransferUseCase(transferUC, accRepo, newMockScheduledTransferRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	from := domain.NewAccount(domain.AccountID(mustUUID("acc-from")), 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(domain.AccountID(mustUUID("acc-to")), 0)
+	accRepo.SaveAccount(ctx, to)
+
+	executeAt := time.Now().Add(time.Hour)
+	_, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, IdempotencyKey: "past", ExecuteAt: time.Now().Add(-time.Second)})
+	if err != domain.ErrInvalidExecuteAt {
+		t.Errorf("expected ErrInvalidExecuteAt, got %v", err)
+	}
+
+	payout, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 700, IdempotencyKey: "payout", ExecuteAt: executeAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tooMuch, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 5000, IdempotencyKey: "too-much", ExecuteAt: executeAt.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	canceled, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, IdempotencyKey: "canceled", ExecuteAt: executeAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.CancelScheduledTransfer(ctx, canceled.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n, err := uc.ExecuteDue(ctx, time.Now())
+	if err != nil || n != 0 || from.Balance != 1000 {
+		t.Fatalf("expected nothing due yet, got %d (%v)", n, err)
+	}
+
+	n, err = uc.ExecuteDue(ctx, executeAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 settled transfers, got %d", n)
+	}
+	if from.Balance != 300 || to.Balance != 700 {
+		t.Errorf("expected balances 300/700, got %d/%d", from.Balance, to.Balance)
+	}
+	if payout.Status != domain.ScheduledTransferStatusExecuted || payout.JournalEntryID == nil {
+		t.Errorf("unexpected payout after execution: %+v", payout)
+	}
+	if tooMuch.Status != domain.ScheduledTransferStatusFailed || tooMuch.FailureReason != domain.ErrInsufficientBalance.Error() {
+		t.Errorf("expected failure for insufficient balance, got %+v", tooMuch)
+	}
+	if canceled.Status != domain.ScheduledTransferStatusCanceled {
+		t.Errorf("expected canceled transfer to stay canceled, got %s", canceled.Status)
+	}
+
+	// A restart that lost the settled status replays the transfer without paying twice
+	payout.Status = domain.ScheduledTransferStatusPending
+	if _, err := uc.ExecuteDue(ctx, executeAt.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 300 || to.Balance != 700 {
+		t.Errorf("expected balances to stay 300/700, got %d/%d", from.Balance, to.Balance)
+	}
+
+	if _, err := uc.CancelScheduledTransfer(ctx, payout.ID); err != domain.ErrScheduledTransferNotPending {
+		t.Errorf("expected ErrScheduledTransferNotPending, got %v", err)
+	}
+}
+
+func TestScheduledTransferUseCase_ExecuteDue_VelocityLimit(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	limitRepo := &mockVelocityLimitRepo{limits: make(map[domain.AccountID]*domain.VelocityLimits), journal: txRepo}
+	perTransfer := int64(500)
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{},
+		WithVelocityLimits(limitRepo, domain.VelocityLimits{MaxPerTransfer: &perTransfer}))
+	uc := NewScheduledTransferUseCase(transferUC, accRepo, newMockScheduledTransferRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	from := domain.NewAccount(domain.AccountID(mustUUID("acc-from")), 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(domain.AccountID(mustUUID("acc-to")), 0)
+	accRepo.SaveAccount(ctx, to)
+
+	executeAt := time.Now().Add(time.Hour)
+	large, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 700, IdempotencyKey: "large", ExecuteAt: executeAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	small, err := uc.ScheduleTransfer(ctx, ScheduleTransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, IdempotencyKey: "small", ExecuteAt: executeAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The transfer over the limit fails instead of blocking the batch
+	n, err := uc.ExecuteDue(ctx, executeAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 || large.Status != domain.ScheduledTransferStatusFailed || small.Status != domain.ScheduledTransferStatusExecuted {
+		t.Errorf("expected the large transfer to fail and the small one to execute, got %d: %s/%s", n, large.Status, small.Status)
+	}
+	if from.Balance != 900 {
+		t.Errorf("expected balance 900, got %d", from.Balance)
+	}
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// dueStandingOrdersBatchSize is the maximum number of standing orders RunDue runs per call
+const dueStandingOrdersBatchSize = 100
+
+// StandingOrderUseCase manages recurring transfers and runs them on schedule.
+type StandingOrderUseCase struct {
+	transferUC  *TransferUseCase
+	accountRepo domain.AccountRepository
+	repo        domain.StandingOrderRepository
+	tm          domain.TransactionManager
+}
+
+func NewStandingOrderUseCase(
+	transferUC *TransferUseCase,
+	accountRepo domain.AccountRepository,
+	repo domain.StandingOrderRepository,
+	tm domain.TransactionManager,
+) *StandingOrderUseCase {
+	return &StandingOrderUseCase{
+		transferUC:  transferUC,
+		accountRepo: accountRepo,
+		repo:        repo,
+		tm:          tm,
+	}
+}
+
+type CreateStandingOrderInput struct {
+	FromAccountID domain.AccountID
+	ToAccountID   domain.AccountID
+	Amount        int64
+	Description   string
+	// Exactly one of Interval and CronExpression must be set.
+	Interval       time.Duration
+	CronExpression string
+	// EndAt is optional.
+	EndAt          *time.Time
+	IdempotencyKey string
+}
+
+// CreateStandingOrder creates an active standing order whose first run is the first scheduled time from now.
+// Replays of the key with other accounts, amount, description, schedule or end fail with
+// an *IdempotencyKeyReusedError.
+func (u *StandingOrderUseCase) CreateStandingOrder(ctx context.Context, input CreateStandingOrderInput) (*domain.StandingOrder, error) {
+	m := movement{
+		FromAccountID:  input.FromAccountID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	schedule, err := domain.ParseSchedule(input.Interval, input.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// Whole seconds, so that run times survive the round trip through DATETIME columns unchanged
+	nextRunAt := schedule.Next(now.Truncate(time.Second))
+	if input.EndAt != nil && input.EndAt.Before(nextRunAt) {
+		return nil, domain.ErrInvalidStandingOrderEnd
+	}
+
+	fingerprint := domain.RequestFingerprint("standing_order", input.FromAccountID, input.ToAccountID,
+		input.Amount, input.Description, input.Interval, input.CronExpression, input.EndAt)
+
+	existing, err := u.repo.FindStandingOrderByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := checkReplay(input.IdempotencyKey, fingerprint, existing.RequestFingerprint, domain.JournalEntryID{}); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if err := checkFutureTransferAccounts(ctx, u.accountRepo, input.FromAccountID, input.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	order := &domain.StandingOrder{
+		ID:                 domain.StandingOrderID(id),
+		FromAccountID:      input.FromAccountID,
+		ToAccountID:        input.ToAccountID,
+		Amount:             input.Amount,
+		Description:        input.Description,
+		Interval:           input.Interval,
+		CronExpression:     input.CronExpression,
+		EndAt:              input.EndAt,
+		NextRunAt:          nextRunAt,
+		Status:             domain.StandingOrderStatusActive,
+		IdempotencyKey:     input.IdempotencyKey,
+		RequestFingerprint: fingerprint,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := u.repo.SaveStandingOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (u *StandingOrderUseCase) GetStandingOrder(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	order, err := u.repo.FindStandingOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrStandingOrderNotFound
+	}
+	return order, nil
+}
+
+// ListStandingOrders returns the standing orders from or to the account.
+func (u *StandingOrderUseCase) ListStandingOrders(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.StandingOrder, error) {
+	limit, offset = normalizePage(limit, offset)
+	return u.repo.FindStandingOrdersByAccountID(ctx, accountID, limit, offset)
+}
+
+// ListStandingOrderRuns returns the run history of the order, newest first.
+func (u *StandingOrderUseCase) ListStandingOrderRuns(ctx context.Context, id domain.StandingOrderID, limit, offset int) ([]*domain.StandingOrderRun, error) {
+	if _, err := u.GetStandingOrder(ctx, id); err != nil {
+		return nil, err
+	}
+	limit, offset = normalizePage(limit, offset)
+	return u.repo.FindStandingOrderRuns(ctx, id, limit, offset)
+}
+
+func (u *StandingOrderUseCase) PauseStandingOrder(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	return u.modifyStandingOrder(ctx, id, (*domain.StandingOrder).Pause)
+}
+
+func (u *StandingOrderUseCase) ResumeStandingOrder(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	return u.modifyStandingOrder(ctx, id, (*domain.StandingOrder).Resume)
+}
+
+func (u *StandingOrderUseCase) CancelStandingOrder(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	return u.modifyStandingOrder(ctx, id, (*domain.StandingOrder).Cancel)
+}
+
+func (u *StandingOrderUseCase) modifyStandingOrder(ctx context.Context, id domain.StandingOrderID, fn func(*domain.StandingOrder, time.Time) error) (*domain.StandingOrder, error) {
+	var order *domain.StandingOrder
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = u.repo.GetStandingOrderForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return domain.ErrStandingOrderNotFound
+		}
+		if err := fn(order, time.Now()); err != nil {
+			return err
+		}
+		return u.repo.SaveStandingOrder(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// RunDue runs every standing order due at now once and returns how many runs were recorded.
+// Orders that missed several runs catch up by one run per call. An order failing with an error
+// that is not a rejection is retried at the next call without holding up the others.
+//
+// Several replicas may call RunDue at once: each run happens under the order's row lock
+// and posts its transfer with a key derived from the order and the scheduled time.
+func (u *StandingOrderUseCase) RunDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := u.repo.FindDueStandingOrders(ctx, now, dueStandingOrdersBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	var errs []error
+	for _, order := range due {
+		ok, err := u.run(ctx, order.ID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("standing order %s: %w", order.ID, err))
+			continue
+		}
+		if ok {
+			ran++
+		}
+	}
+	return ran, errors.Join(errs...)
+}
+
+// run executes the next run of the order if it is still due and reports whether it did.
+// Rejected transfers are recorded as failed runs and the order moves on to its next run.
+func (u *StandingOrderUseCase) run(ctx context.Context, id domain.StandingOrderID, now time.Time) (bool, error) {
+	ran := false
+	err := u.tm.Run(ctx, func(ctx context.Context) error {
+		order, err := u.repo.GetStandingOrderForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Paused, canceled or already run by another replica in the meantime
+		if order == nil || order.Status != domain.StandingOrderStatusActive || order.NextRunAt.After(now) {
+			return nil
+		}
+
+		runID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		run := &domain.StandingOrderRun{
+			ID:              domain.StandingOrderRunID(runID),
+			StandingOrderID: order.ID,
+			ScheduledAt:     order.NextRunAt,
+			CreatedAt:       time.Now(),
+		}
+
+		out, err := u.transferUC.Transfer(ctx, TransferInput{
+			FromAccountID:  order.FromAccountID,
+			ToAccountID:    order.ToAccountID,
+			Amount:         order.Amount,
+			Description:    order.Description,
+			IdempotencyKey: order.RunIdempotencyKey(order.NextRunAt),
+		})
+		switch {
+		case err == nil:
+			run.Status = domain.StandingOrderRunStatusSucceeded
+			run.JournalEntryID = &out.JournalEntryID
+		case isTransferRejection(err):
+			run.Status = domain.StandingOrderRunStatusFailed
+			run.FailureReason = err.Error()
+		default:
+			return err
+		}
+
+		if err := order.Advance(run.CreatedAt); err != nil {
+			return err
+		}
+		if err := u.repo.SaveStandingOrderRun(ctx, run); err != nil {
+			return err
+		}
+		if err := u.repo.SaveStandingOrder(ctx, order); err != nil {
+			return err
+		}
+		ran = true
+		return nil
+	})
+	return ran, err
+}
+
+// normalizePage clamps the limit to 1..1000, defaulting to 50, and the offset to non-negative.
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
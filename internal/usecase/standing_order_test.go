@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+type mockStandingOrderRepo struct {
+	orders map[domain.StandingOrderID]*domain.StandingOrder
+	runs   []*domain.StandingOrderRun
+}
+
+func newMockStandingOrderRepo() *mockStandingOrderRepo {
+	return &mockStandingOrderRepo{orders: make(map[domain.StandingOrderID]*domain.StandingOrder)}
+}
+
+func (m *mockStandingOrderRepo) SaveStandingOrder(ctx context.Context, order *domain.StandingOrder) error {
+	m.orders[order.ID] = order
+	return nil
+}
+
+func (m *mockStandingOrderRepo) FindStandingOrderByID(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	return m.orders[id], nil
+}
+
+func (m *mockStandingOrderRepo) GetStandingOrderForUpdate(ctx context.Context, id domain.StandingOrderID) (*domain.StandingOrder, error) {
+	return m.orders[id], nil
+}
+
+func (m *mockStandingOrderRepo) FindStandingOrderByIdempotencyKey(ctx context.Context, key string) (*domain.StandingOrder, error) {
+	for _, order := range m.orders {
+		if order.IdempotencyKey == key {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStandingOrderRepo) FindStandingOrdersByAccountID(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.StandingOrder, error) {
+	var res []*domain.StandingOrder
+	for _, order := range m.orders {
+		if order.FromAccountID == accountID || order.ToAccountID == accountID {
+			res = append(res, order)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockStandingOrderRepo) FindDueStandingOrders(ctx context.Context, now time.Time, limit int) ([]*domain.StandingOrder, error) {
+	var res []*domain.StandingOrder
+	for _, order := range m.orders {
+		if order.Status == domain.StandingOrderStatusActive && !order.NextRunAt.After(now) && len(res) < limit {
+			res = append(res, order)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockStandingOrderRepo) SaveStandingOrderRun(ctx context.Context, run *domain.StandingOrderRun) error {
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *mockStandingOrderRepo) FindStandingOrderRuns(ctx context.Context, orderID domain.StandingOrderID, limit, offset int) ([]*domain.StandingOrderRun, error) {
+	var res []*domain.StandingOrderRun
+	for i := len(m.runs) - 1; i >= 0; i-- {
+		if m.runs[i].StandingOrderID == orderID {
+			res = append(res, m.runs[i])
+		}
+	}
+	return res, nil
+}
+
+func TestStandingOrderUseCase_RunDue(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	uc := NewStandingOrderUseCase(transferUC, accRepo, newMockStandingOrderRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	from := domain.NewAccount(domain.AccountID(mustUUID("acc-from")), 0)
+	from.Balance = 250
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(domain.AccountID(mustUUID("acc-to")), 0)
+	accRepo.SaveAccount(ctx, to)
+
+	_, err := uc.CreateStandingOrder(ctx, CreateStandingOrderInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, Interval: time.Hour, CronExpression: "0 9 1 * *", IdempotencyKey: "both"})
+	if err != domain.ErrInvalidSchedule {
+		t.Errorf("expected ErrInvalidSchedule, got %v", err)
+	}
+
+	order, err := uc.CreateStandingOrder(ctx, CreateStandingOrderInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, Interval: time.Hour, IdempotencyKey: "stipend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := order.NextRunAt
+
+	// Another replica running the same due order only sees it once
+	for range 2 {
+		if _, err := uc.RunDue(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if from.Balance != 150 || to.Balance != 100 {
+		t.Errorf("expected balances 150/100, got %d/%d", from.Balance, to.Balance)
+	}
+	if !order.NextRunAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("expected next run at %v, got %v", first.Add(time.Hour), order.NextRunAt)
+	}
+
+	if _, err := uc.PauseStandingOrder(ctx, order.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, _ := uc.RunDue(ctx, first.Add(time.Hour)); n != 0 {
+		t.Errorf("expected paused order not to run, ran %d", n)
+	}
+	if _, err := uc.ResumeStandingOrder(ctx, order.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Runs until the source runs dry, then records the failure and moves on
+	for range 2 {
+		if _, err := uc.RunDue(ctx, order.NextRunAt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if from.Balance != 50 || to.Balance != 200 {
+		t.Errorf("expected balances 50/200, got %d/%d", from.Balance, to.Balance)
+	}
+
+	runs, err := uc.ListStandingOrderRuns(ctx, order.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(runs))
+	}
+	if runs[0].Status != domain.StandingOrderRunStatusFailed || runs[0].FailureReason != domain.ErrInsufficientBalance.Error() {
+		t.Errorf("expected the last run to fail for insufficient balance, got %+v", runs[0])
+	}
+	if runs[1].Status != domain.StandingOrderRunStatusSucceeded || runs[1].JournalEntryID == nil {
+		t.Errorf("expected a succeeded run, got %+v", runs[1])
+	}
+
+	if _, err := uc.CancelStandingOrder(ctx, order.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.PauseStandingOrder(ctx, order.ID); err != domain.ErrStandingOrderNotActive {
+		t.Errorf("expected ErrStandingOrderNotActive, got %v", err)
+	}
+}
@@ -2,10 +2,11 @@ package usecase
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
 )
 
@@ -14,24 +15,123 @@ const (
 	MaxTransferAmount int64 = 100_000_000_000
 	// MaxDescriptionLength is the maximum allowed description length
 	MaxDescriptionLength = 500
+	// MaxIdempotencyKeyLength is the maximum allowed idempotency key length
+	MaxIdempotencyKeyLength = 255
+	// MaxBatchLegs is the maximum number of legs in a batch transfer
+	MaxBatchLegs = 100
 )
 
+// IntraTreeTransferPolicy controls transfers between accounts of the same account tree.
+type IntraTreeTransferPolicy string
+
+const (
+	// IntraTreeTransfersAllowed places no restriction on transfers within a tree.
+	IntraTreeTransfersAllowed IntraTreeTransferPolicy = "allow"
+	// IntraTreeTransfersParentChild only allows transfers between a sub-account and its direct parent.
+	IntraTreeTransfersParentChild IntraTreeTransferPolicy = "parent_child"
+	// IntraTreeTransfersDenied forbids all transfers within a tree.
+	IntraTreeTransfersDenied IntraTreeTransferPolicy = "deny"
+)
+
+// ParseIntraTreeTransferPolicy parses a policy name. The empty string means IntraTreeTransfersAllowed.
+func ParseIntraTreeTransferPolicy(s string) (IntraTreeTransferPolicy, error) {
+	switch p := IntraTreeTransferPolicy(s); p {
+	case "":
+		return IntraTreeTransfersAllowed, nil
+	case IntraTreeTransfersAllowed, IntraTreeTransfersParentChild, IntraTreeTransfersDenied:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown intra-tree transfer policy %q", s)
+	}
+}
+
 type TransferUseCase struct {
-	accountRepo domain.AccountRepository
-	repo        domain.JournalEntryRepository
-	tm          domain.TransactionManager
+	accountRepo     domain.AccountRepository
+	repo            domain.JournalEntryRepository
+	tm              domain.TransactionManager
+	intraTreePolicy IntraTreeTransferPolicy
+	feeRuleRepo     domain.FeeRuleRepository
+	limitRepo       domain.VelocityLimitRepository
+	defaultLimits   domain.VelocityLimits
+	lots            *PointLotUseCase
+}
+
+// TransferOption configures a TransferUseCase.
+type TransferOption func(*TransferUseCase)
+
+// WithIntraTreeTransferPolicy sets the policy for transfers within an account tree.
+func WithIntraTreeTransferPolicy(p IntraTreeTransferPolicy) TransferOption {
+	return func(u *TransferUseCase) {
+		u.intraTreePolicy = p
+	}
+}
+
+// WithFeeRules charges transfers the fees of the matching rules in repo.
+func WithFeeRules(repo domain.FeeRuleRepository) TransferOption {
+	return func(u *TransferUseCase) {
+		u.feeRuleRepo = repo
+	}
+}
+
+// WithVelocityLimits enforces the per-account limits in repo on Transfer and on the legs of
+// BatchTransfer and SplitTransfer. Accounts fall back to defaults for the limits they do not set.
+// See WithCaptureVelocityLimits and WithEscrowVelocityLimits for holds and escrows.
+func WithVelocityLimits(repo domain.VelocityLimitRepository, defaults domain.VelocityLimits) TransferOption {
+	return func(u *TransferUseCase) {
+		u.limitRepo = repo
+		u.defaultLimits = defaults
+	}
+}
+
+// WithPointLots tracks expiring points in transfers.
+func WithPointLots(lots *PointLotUseCase) TransferOption {
+	return func(u *TransferUseCase) {
+		u.lots = lots
+	}
+}
+
+// ParseVelocityLimits parses default velocity limits. Empty strings leave the limit unset.
+func ParseVelocityLimits(maxPerTransfer, maxOutgoingPerDay, maxTransfersPerHour string) (domain.VelocityLimits, error) {
+	var limits domain.VelocityLimits
+	for _, f := range []struct {
+		s   string
+		dst **int64
+	}{
+		{maxPerTransfer, &limits.MaxPerTransfer},
+		{maxOutgoingPerDay, &limits.MaxOutgoingPerDay},
+		{maxTransfersPerHour, &limits.MaxTransfersPerHour},
+	} {
+		if f.s == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(f.s, 10, 64)
+		if err != nil {
+			return domain.VelocityLimits{}, fmt.Errorf("invalid velocity limit %q", f.s)
+		}
+		*f.dst = &v
+	}
+	if err := limits.Validate(); err != nil {
+		return domain.VelocityLimits{}, err
+	}
+	return limits, nil
 }
 
 func NewTransferUseCase(
 	accountRepo domain.AccountRepository,
 	repo domain.JournalEntryRepository,
 	tm domain.TransactionManager,
+	opts ...TransferOption,
 ) *TransferUseCase {
-	return &TransferUseCase{
-		accountRepo: accountRepo,
-		repo:        repo,
-		tm:          tm,
+	u := &TransferUseCase{
+		accountRepo:     accountRepo,
+		repo:            repo,
+		tm:              tm,
+		intraTreePolicy: IntraTreeTransfersAllowed,
+	}
+	for _, opt := range opts {
+		opt(u)
 	}
+	return u
 }
 
 type TransferInput struct {
@@ -40,15 +140,189 @@ type TransferInput struct {
 	Amount         int64
 	Description    string
 	IdempotencyKey string
+	// Metadata is recorded on the transfer's journal entry.
+	Metadata map[string]string
+	// Preconditions checked under the account row locks. Nil fields are not checked.
+	ExpectedFromVersion *int64
+	ExpectedToVersion   *int64
+	MinFromBalance      *int64
+}
+
+// checkPreconditions returns ErrPreconditionFailed unless the locked accounts are as the caller expects.
+func (in TransferInput) checkPreconditions(from, to *domain.Account) error {
+	if in.ExpectedFromVersion != nil && from.Version != *in.ExpectedFromVersion {
+		return fmt.Errorf("%w: source account version is %d, expected %d", domain.ErrPreconditionFailed, from.Version, *in.ExpectedFromVersion)
+	}
+	if in.ExpectedToVersion != nil && to.Version != *in.ExpectedToVersion {
+		return fmt.Errorf("%w: destination account version is %d, expected %d", domain.ErrPreconditionFailed, to.Version, *in.ExpectedToVersion)
+	}
+	if in.MinFromBalance != nil && from.Balance < *in.MinFromBalance {
+		return fmt.Errorf("%w: source balance is below %d", domain.ErrPreconditionFailed, *in.MinFromBalance)
+	}
+	return nil
 }
 
 type TransferOutput struct {
 	JournalEntryID domain.JournalEntryID
 	CreatedAt      time.Time
+	// Fee charged to the sender on top of the amount. Zero if no fee rule matched.
+	Fee int64
+	// FeeJournalEntryID is the entry paying the fee to the collector, if a fee was charged.
+	FeeJournalEntryID *domain.JournalEntryID
 }
 
+// Transfer moves the amount between the accounts. If a fee rule matches, the fee is posted
+// as a second entry from the sender to the collector in the same journal group.
+// The sender's velocity limits and the input's preconditions are checked under the row locks.
+// Replays of a completed transfer return its result without checking the preconditions again;
+// replays of its key with other accounts, amount, description or metadata fail with
+// an *IdempotencyKeyReusedError.
 func (u *TransferUseCase) Transfer(ctx context.Context, input TransferInput) (*TransferOutput, error) {
-	// Input Validation
+	fingerprint := domain.RequestFingerprint("transfer", input.FromAccountID, input.ToAccountID, input.Amount, input.Description, input.Metadata)
+	m := movement{
+		Type:           domain.EntryTypeTransfer,
+		FromAccountID:  input.FromAccountID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+		Metadata:       input.Metadata,
+		Fingerprint:    fingerprint,
+	}
+	check := u.transferCheck(input)
+	if u.feeRuleRepo == nil {
+		return u.transfer(ctx, m, check)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	// A retry must return the original result even if the fee rules changed since.
+	existing, err := u.repo.FindByIdempotencyKey(ctx, m.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := checkReplay(m.IdempotencyKey, fingerprint, existing.RequestFingerprint, existing.ID); err != nil {
+			return nil, err
+		}
+		return &TransferOutput{JournalEntryID: existing.ID, CreatedAt: existing.Timestamp}, nil
+	}
+	group, err := u.repo.FindJournalGroupByIdempotencyKey(ctx, m.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		if err := checkGroupReplay(m.IdempotencyKey, fingerprint, group); err != nil {
+			return nil, err
+		}
+		return newFeeTransferOutput(group), nil
+	}
+
+	quote, err := u.QuoteTransfer(ctx, QuoteTransferInput{
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if quote.Fee == 0 {
+		return u.transfer(ctx, m, check)
+	}
+
+	group, err = u.poster().postGroup(ctx, movementGroup{
+		IdempotencyKey: m.IdempotencyKey,
+		Description:    m.Description,
+		Fingerprint:    fingerprint,
+		Legs: []movement{
+			{
+				Type:          domain.EntryTypeTransfer,
+				FromAccountID: m.FromAccountID,
+				ToAccountID:   m.ToAccountID,
+				Amount:        m.Amount,
+				Description:   m.Description,
+				Metadata:      m.Metadata,
+			},
+			{
+				Type:          domain.EntryTypeFee,
+				FromAccountID: m.FromAccountID,
+				ToAccountID:   *quote.CollectorAccountID,
+				Amount:        quote.Fee,
+				Description:   "fee: " + quote.FeeRuleName,
+			},
+		},
+	}, check)
+	if err != nil {
+		return nil, err
+	}
+	return newFeeTransferOutput(group), nil
+}
+
+// transferCheck returns the checks Transfer runs on each leg under the row locks.
+// The fee leg is charged for the transfer and is not a transfer of its own: it is exempt from
+// the preconditions, the velocity limits and the intra-tree policy, so that a collector inside
+// the sender's tree does not block the sender's transfers.
+func (u *TransferUseCase) transferCheck(input TransferInput) movementCheck {
+	return func(ctx context.Context, m movement, from, to *domain.Account) error {
+		if m.Type == domain.EntryTypeFee {
+			return nil
+		}
+		if err := u.checkIntraTreePolicy(ctx, from, to); err != nil {
+			return err
+		}
+		if err := input.checkPreconditions(from, to); err != nil {
+			return err
+		}
+		return u.checkVelocityLimits(ctx, from, input.Amount)
+	}
+}
+
+func (u *TransferUseCase) transfer(ctx context.Context, m movement, check movementCheck) (*TransferOutput, error) {
+	entry, err := u.poster().post(ctx, m, check)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferOutput{
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
+	}, nil
+}
+
+// newFeeTransferOutput reports a group of the transfer leg followed by its fee leg.
+func newFeeTransferOutput(group *domain.JournalGroup) *TransferOutput {
+	out := &TransferOutput{
+		JournalEntryID: group.Entries[0].ID,
+		CreatedAt:      group.CreatedAt,
+	}
+	if len(group.Entries) > 1 && group.Entries[1].Type == domain.EntryTypeFee {
+		out.Fee = group.Entries[1].Amount
+		out.FeeJournalEntryID = &group.Entries[1].ID
+	}
+	return out
+}
+
+type QuoteTransferInput struct {
+	FromAccountID domain.AccountID
+	ToAccountID   domain.AccountID
+	Amount        int64
+}
+
+// TransferQuote is the fee a transfer would be charged under the current fee rules.
+type TransferQuote struct {
+	Amount int64
+	Fee    int64
+	// Total is the amount plus the fee, the sender's balance decrease.
+	Total int64
+	// FeeRuleID, FeeRuleName and CollectorAccountID are set if a fee rule matched.
+	FeeRuleID          *domain.FeeRuleID
+	FeeRuleName        string
+	CollectorAccountID *domain.AccountID
+}
+
+// QuoteTransfer returns the fee Transfer would charge for the input, without moving any points.
+func (u *TransferUseCase) QuoteTransfer(ctx context.Context, input QuoteTransferInput) (*TransferQuote, error) {
 	if input.Amount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
@@ -58,134 +332,534 @@ func (u *TransferUseCase) Transfer(ctx context.Context, input TransferInput) (*T
 	if input.FromAccountID == input.ToAccountID {
 		return nil, domain.ErrSelfTransfer
 	}
-	if strings.TrimSpace(input.IdempotencyKey) == "" {
-		return nil, domain.ErrInvalidIdempotencyKey
+
+	quote := &TransferQuote{Amount: input.Amount, Total: input.Amount}
+	if u.feeRuleRepo == nil {
+		return quote, nil
 	}
-	if len(input.Description) > MaxDescriptionLength {
-		return nil, domain.ErrDescriptionTooLong
+
+	from, err := u.accountRepo.FindAccountByID(ctx, input.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := u.accountRepo.FindAccountByID(ctx, input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil || to == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if from.Asset != to.Asset {
+		return nil, domain.ErrAssetMismatch
 	}
 
-	// 1. Idempotency Check (Quick check before TX)
-	existing, err := u.repo.FindByIdempotencyKey(ctx, input.IdempotencyKey)
+	rules, err := u.feeRuleRepo.ListFeeRules(ctx)
 	if err != nil {
 		return nil, err
 	}
+	rule := domain.SelectFeeRule(rules, from, to)
+	if rule == nil {
+		return quote, nil
+	}
+	quote.Fee = rule.Fee(input.Amount)
+	quote.Total += quote.Fee
+	quote.FeeRuleID = &rule.ID
+	quote.FeeRuleName = rule.Name
+	quote.CollectorAccountID = &rule.CollectorAccountID
+	return quote, nil
+}
 
-	if existing != nil {
-		return &TransferOutput{
-			JournalEntryID: existing.ID,
-			CreatedAt:      existing.Timestamp,
-		}, nil
-	}
-
-	var newEntry *domain.JournalEntry
-
-	// 2. Atomic Transaction with Global Serialization for Hash Chain
-	err = u.tm.RunSerialized(ctx, "journal_entry_chain", func(ctx context.Context) error {
-		return u.tm.Run(ctx, func(ctx context.Context) error {
-			// Re-check idempotency inside TX (double check locking)
-			existing, err := u.repo.FindByIdempotencyKey(ctx, input.IdempotencyKey)
-			if err == nil && existing != nil {
-				newEntry = existing
-				return nil
-			}
+// errSimulationRollback rolls back the transaction of a simulated transfer.
+var errSimulationRollback = errors.New("simulated transfer rolled back")
 
-			// Prevent Deadlock: Lock order must be consistent (e.g., lexical order)
-			firstID, secondID := input.FromAccountID, input.ToAccountID
-			if firstID.String() > secondID.String() {
-				firstID, secondID = secondID, firstID
-			}
+// TransferSimulation is the outcome SimulateTransfer projects for a transfer.
+type TransferSimulation struct {
+	Amount int64
+	Fee    int64
+	// Total is the amount plus the fee, the sender's balance decrease.
+	Total int64
+	// FromBalance and ToBalance are the balances after the transfer.
+	FromBalance          int64
+	FromAvailableBalance int64
+	ToBalance            int64
+	// FeeRuleID, CollectorAccountID and CollectorBalance are set if a fee would be charged.
+	FeeRuleID          *domain.FeeRuleID
+	CollectorAccountID *domain.AccountID
+	CollectorBalance   *int64
+}
 
-			// Helper to load
-			load := func(id domain.AccountID) (*domain.Account, error) {
-				acc, err := u.accountRepo.GetAccountForUpdate(ctx, id)
-				if err != nil {
-					return nil, err
-				}
-				if acc == nil {
-					return nil, domain.ErrAccountNotFound
-				}
-				return acc, nil
-			}
+// SimulateTransfer runs the checks and balance changes of Transfer for the input in a transaction
+// that is always rolled back, and returns the projected balances or the error Transfer would return.
+// Nothing is appended to the journal and the idempotency key is ignored, so a simulation never
+// reports the result of an earlier transfer.
+func (u *TransferUseCase) SimulateTransfer(ctx context.Context, input TransferInput) (*TransferSimulation, error) {
+	m := movement{
+		Type:          domain.EntryTypeTransfer,
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+		Description:   input.Description,
+		Metadata:      input.Metadata,
+	}
+	if err := m.validateAmount(); err != nil {
+		return nil, err
+	}
+	if len(m.Description) > MaxDescriptionLength {
+		return nil, domain.ErrDescriptionTooLong
+	}
+	if err := domain.ValidateMetadata(m.Metadata); err != nil {
+		return nil, err
+	}
 
-			// acquire lock in order
-			acc1, err := load(firstID)
-			if err != nil {
-				return err
-			}
-			acc2, err := load(secondID)
-			if err != nil {
-				return err
-			}
+	quote, err := u.QuoteTransfer(ctx, QuoteTransferInput{
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	legs := []movement{m}
+	ids := []domain.AccountID{m.FromAccountID, m.ToAccountID}
+	if quote.Fee > 0 {
+		legs = append(legs, movement{
+			Type:          domain.EntryTypeFee,
+			FromAccountID: m.FromAccountID,
+			ToAccountID:   *quote.CollectorAccountID,
+			Amount:        quote.Fee,
+		})
+		ids = append(ids, *quote.CollectorAccountID)
+	}
 
-			// Map back to from/to
-			var from, to *domain.Account
-			if firstID == input.FromAccountID {
-				from = acc1
-				to = acc2
-			} else {
-				from = acc2
-				to = acc1
-			}
+	var sim *TransferSimulation
+	err = u.tm.Run(ctx, func(ctx context.Context) error {
+		locked, err := lockAccounts(ctx, u.accountRepo, ids...)
+		if err != nil {
+			return err
+		}
+		// Apply to copies: nothing may be saved, even by a repository that writes through.
+		accounts := make(map[domain.AccountID]*domain.Account, len(locked))
+		for id, acc := range locked {
+			copied := *acc
+			accounts[id] = &copied
+		}
 
-			// Execute Transfer Logic
-			if err := from.Withdraw(input.Amount); err != nil {
-				return err
-			}
-			if err := to.Deposit(input.Amount); err != nil {
+		check := u.transferCheck(input)
+		for i, leg := range legs {
+			if err := applyMovement(ctx, accounts, leg, check); err != nil {
+				// Transfer reports the failing leg when it posts a fee.
+				if len(legs) > 1 {
+					return legError(i, err)
+				}
 				return err
 			}
+		}
 
-			// Create Journal Entry Record
-			// Lock latest entry for hash chain
-			latestEntry, err := u.repo.GetLatestJournalEntry(ctx)
-			prevHash := ""
-			if err == nil && latestEntry != nil {
-				prevHash = latestEntry.Hash
-			}
+		from := accounts[m.FromAccountID]
+		sim = &TransferSimulation{
+			Amount:               quote.Amount,
+			Fee:                  quote.Fee,
+			Total:                quote.Total,
+			FromBalance:          from.Balance,
+			FromAvailableBalance: from.AvailableBalance(),
+			ToBalance:            accounts[m.ToAccountID].Balance,
+		}
+		if quote.Fee > 0 {
+			sim.FeeRuleID = quote.FeeRuleID
+			sim.CollectorAccountID = quote.CollectorAccountID
+			sim.CollectorBalance = &accounts[*quote.CollectorAccountID].Balance
+		}
+		return errSimulationRollback
+	})
+	if !errors.Is(err, errSimulationRollback) {
+		return nil, err
+	}
+	return sim, nil
+}
 
-			id, err := uuid.NewV7()
-			if err != nil {
-				return err
-			}
-			newEntry = &domain.JournalEntry{
-				ID:             domain.JournalEntryID(id),
-				FromAccountID:  from.ID,
-				ToAccountID:    to.ID,
-				Amount:         input.Amount,
-				Description:    input.Description,
-				IdempotencyKey: input.IdempotencyKey,
-				PreviousHash:   prevHash,
-				Timestamp:      time.Now(),
-			}
-			// Compute Hash
-			newEntry.Hash = newEntry.ComputeHash()
+// TransferLeg is one movement of a batch transfer.
+type TransferLeg struct {
+	FromAccountID domain.AccountID
+	ToAccountID   domain.AccountID
+	Amount        int64
+	// Description defaults to the batch description when empty.
+	Description string
+}
 
-			// Save All
-			if err := u.accountRepo.SaveAccount(ctx, from); err != nil {
-				return err
-			}
-			if err := u.accountRepo.SaveAccount(ctx, to); err != nil {
-				return err
-			}
-			if err := u.repo.SaveJournalEntry(ctx, newEntry); err != nil {
-				return err
-			}
+type BatchTransferInput struct {
+	Legs           []TransferLeg
+	Description    string
+	IdempotencyKey string
+}
 
-			return nil
+type BatchTransferOutput struct {
+	GroupID domain.JournalGroupID
+	// JournalEntryIDs are the entries of the legs, in input order.
+	JournalEntryIDs []domain.JournalEntryID
+	CreatedAt       time.Time
+}
+
+// BatchTransfer applies all legs atomically: either every leg succeeds or none is applied.
+// The legs are recorded as consecutive journal entries of one group under a single idempotency key.
+// Replays of the key with other legs or another description fail with an *IdempotencyKeyReusedError.
+func (u *TransferUseCase) BatchTransfer(ctx context.Context, input BatchTransferInput) (*BatchTransferOutput, error) {
+	legs := make([]movement, len(input.Legs))
+	fields := []any{input.Description}
+	for i, leg := range input.Legs {
+		legs[i] = movement{
+			Type:          domain.EntryTypeTransfer,
+			FromAccountID: leg.FromAccountID,
+			ToAccountID:   leg.ToAccountID,
+			Amount:        leg.Amount,
+			Description:   leg.Description,
+		}
+		fields = append(fields, leg.FromAccountID, leg.ToAccountID, leg.Amount, leg.Description)
+	}
+
+	group, err := u.poster().postGroup(ctx, movementGroup{
+		IdempotencyKey: input.IdempotencyKey,
+		Description:    input.Description,
+		Fingerprint:    domain.RequestFingerprint("batch_transfer", fields...),
+		Legs:           legs,
+	}, u.groupCheck(legs))
+	if err != nil {
+		return nil, err
+	}
+
+	return newBatchTransferOutput(group), nil
+}
+
+func newBatchTransferOutput(group *domain.JournalGroup) *BatchTransferOutput {
+	ids := make([]domain.JournalEntryID, len(group.Entries))
+	for i, entry := range group.Entries {
+		ids[i] = entry.ID
+	}
+	return &BatchTransferOutput{
+		GroupID:         group.ID,
+		JournalEntryIDs: ids,
+		CreatedAt:       group.CreatedAt,
+	}
+}
+
+// SplitRecipient receives a share of a split transfer proportional to its weight.
+type SplitRecipient struct {
+	AccountID domain.AccountID
+	Weight    int64
+}
+
+type SplitTransferInput struct {
+	FromAccountID  domain.AccountID
+	Amount         int64
+	Recipients     []SplitRecipient
+	Description    string
+	IdempotencyKey string
+}
+
+// SplitShare is the amount a recipient of a split transfer received.
+type SplitShare struct {
+	AccountID domain.AccountID
+	Amount    int64
+	// JournalEntryID is nil if the share rounded down to zero and nothing was posted.
+	JournalEntryID *domain.JournalEntryID
+}
+
+type SplitTransferOutput struct {
+	GroupID domain.JournalGroupID
+	// Shares are in recipient order.
+	Shares    []SplitShare
+	CreatedAt time.Time
+}
+
+// SplitTransfer splits the amount between the recipients by weight with domain.SplitByWeight
+// and posts one leg per non-zero share atomically, as one journal group.
+// Replays of the key with another sender, amount, recipients or description fail with an *IdempotencyKeyReusedError.
+func (u *TransferUseCase) SplitTransfer(ctx context.Context, input SplitTransferInput) (*SplitTransferOutput, error) {
+	if len(input.Recipients) == 0 || len(input.Recipients) > MaxBatchLegs {
+		return nil, domain.ErrInvalidBatchSize
+	}
+	if input.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if input.Amount > MaxTransferAmount {
+		return nil, domain.ErrAmountTooLarge
+	}
+	weights := make([]int64, len(input.Recipients))
+	seen := make(map[domain.AccountID]bool, len(input.Recipients))
+	fields := []any{input.FromAccountID, input.Amount, input.Description}
+	for i, r := range input.Recipients {
+		if seen[r.AccountID] {
+			return nil, domain.ErrDuplicateSplitRecipient
+		}
+		seen[r.AccountID] = true
+		weights[i] = r.Weight
+		fields = append(fields, r.AccountID, r.Weight)
+	}
+	amounts, err := domain.SplitByWeight(input.Amount, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	var legs []movement
+	for i, r := range input.Recipients {
+		if amounts[i] == 0 {
+			continue
+		}
+		legs = append(legs, movement{
+			Type:          domain.EntryTypeTransfer,
+			FromAccountID: input.FromAccountID,
+			ToAccountID:   r.AccountID,
+			Amount:        amounts[i],
 		})
-	})
+	}
+
+	group, err := u.poster().postGroup(ctx, movementGroup{
+		IdempotencyKey: input.IdempotencyKey,
+		Description:    input.Description,
+		Fingerprint:    domain.RequestFingerprint("split_transfer", fields...),
+		Legs:           legs,
+	}, u.groupCheck(legs))
+	if err != nil {
+		return nil, err
+	}
+	return newSplitTransferOutput(group, input.Recipients), nil
+}
+
+// newSplitTransferOutput reports the shares posted by the group, so that a replay returns what was posted.
+func newSplitTransferOutput(group *domain.JournalGroup, recipients []SplitRecipient) *SplitTransferOutput {
+	entries := make(map[domain.AccountID]*domain.JournalEntry, len(group.Entries))
+	for _, entry := range group.Entries {
+		entries[entry.ToAccountID] = entry
+	}
+	shares := make([]SplitShare, len(recipients))
+	for i, r := range recipients {
+		shares[i] = SplitShare{AccountID: r.AccountID}
+		if entry, ok := entries[r.AccountID]; ok {
+			shares[i].Amount = entry.Amount
+			shares[i].JournalEntryID = &entry.ID
+		}
+	}
+	return &SplitTransferOutput{
+		GroupID:   group.ID,
+		Shares:    shares,
+		CreatedAt: group.CreatedAt,
+	}
+}
+
+type ReverseJournalEntryInput struct {
+	JournalEntryID domain.JournalEntryID
+	// Amount to move back. Nil reverses the full original amount, which fails after a partial reversal.
+	Amount         *int64
+	Description    string
+	IdempotencyKey string
+}
+
+// ReverseJournalEntry posts a reversal moving the original amount, or part of it,
+// from the original recipient back to the original sender.
+// A transfer can be reversed in several parts until its whole amount is reversed, and reversals cannot be reversed.
+func (u *TransferUseCase) ReverseJournalEntry(ctx context.Context, input ReverseJournalEntryInput) (*TransferOutput, error) {
+	original, err := u.repo.FindJournalEntryByID(ctx, input.JournalEntryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, domain.ErrJournalEntryNotFound
+	}
+
+	amount := original.Amount
+	if input.Amount != nil {
+		amount = *input.Amount
+	}
 
+	entry, err := u.poster().post(ctx, movement{
+		Type:           domain.EntryTypeReversal,
+		FromAccountID:  original.ToAccountID,
+		ToAccountID:    original.FromAccountID,
+		Amount:         amount,
+		Description:    input.Description,
+		IdempotencyKey: input.IdempotencyKey,
+		ReversesID:     &original.ID,
+		Fingerprint:    domain.RequestFingerprint("reversal", original.ID, amount, input.Description),
+	}, func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		// Re-read under the journal lock so that concurrent reversals cannot both pass.
+		current, err := u.repo.FindJournalEntryByID(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return domain.ErrJournalEntryNotFound
+		}
+		return current.CheckReversible(amount)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &TransferOutput{
-		JournalEntryID: newEntry.ID,
-		CreatedAt:      newEntry.Timestamp,
+		JournalEntryID: entry.ID,
+		CreatedAt:      entry.Timestamp,
 	}, nil
 }
 
+// AccountVelocityLimits are the limits an account sets itself and the limits in effect after defaults.
+type AccountVelocityLimits struct {
+	Own       domain.VelocityLimits
+	Effective domain.VelocityLimits
+}
+
+// GetVelocityLimits returns the velocity limits of the account.
+func (u *TransferUseCase) GetVelocityLimits(ctx context.Context, accountID domain.AccountID) (*AccountVelocityLimits, error) {
+	acc, err := u.accountRepo.FindAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	own, err := u.ownVelocityLimits(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountVelocityLimits{Own: own, Effective: own.Or(u.defaultLimits)}, nil
+}
+
+// SetVelocityLimits replaces the account's own velocity limits. Unset limits fall back to the defaults.
+func (u *TransferUseCase) SetVelocityLimits(ctx context.Context, accountID domain.AccountID, limits domain.VelocityLimits) (*AccountVelocityLimits, error) {
+	if u.limitRepo == nil {
+		return nil, domain.ErrVelocityLimitsNotConfigured
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	acc, err := u.accountRepo.FindAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err := u.limitRepo.SaveVelocityLimits(ctx, accountID, &limits); err != nil {
+		return nil, err
+	}
+	return &AccountVelocityLimits{Own: limits, Effective: limits.Or(u.defaultLimits)}, nil
+}
+
+func (u *TransferUseCase) ownVelocityLimits(ctx context.Context, accountID domain.AccountID) (domain.VelocityLimits, error) {
+	if u.limitRepo == nil {
+		return domain.VelocityLimits{}, nil
+	}
+	limits, err := u.limitRepo.FindVelocityLimits(ctx, accountID)
+	if err != nil || limits == nil {
+		return domain.VelocityLimits{}, err
+	}
+	return *limits, nil
+}
+
+// checkVelocityLimits rejects sending the amounts, as consecutive transfers, from the locked account
+// if that would break a velocity limit. The sender's row lock keeps concurrent transfers from passing
+// on the same aggregates.
+func (u *TransferUseCase) checkVelocityLimits(ctx context.Context, from *domain.Account, amounts ...int64) error {
+	if u.limitRepo == nil {
+		return nil
+	}
+	own, err := u.ownVelocityLimits(ctx, from.ID)
+	if err != nil {
+		return err
+	}
+	limits := own.Or(u.defaultLimits)
+	if limits.IsZero() {
+		return nil
+	}
+
+	now := time.Now()
+	var day, hour domain.OutgoingStats
+	if limits.MaxOutgoingPerDay != nil {
+		stats, err := u.limitRepo.GetOutgoingTransferStats(ctx, from.ID, now.Add(-domain.VelocityDailyWindow))
+		if err != nil {
+			return err
+		}
+		day = *stats
+	}
+	if limits.MaxTransfersPerHour != nil {
+		stats, err := u.limitRepo.GetOutgoingTransferStats(ctx, from.ID, now.Add(-domain.VelocityHourlyWindow))
+		if err != nil {
+			return err
+		}
+		hour = *stats
+	}
+	for _, amount := range amounts {
+		if err := limits.Check(amount, day, hour); err != nil {
+			return err
+		}
+		day = withTransfer(day, amount, now)
+		hour = withTransfer(hour, amount, now)
+	}
+	return nil
+}
+
+// withTransfer adds a transfer sent at now to the stats.
+func withTransfer(stats domain.OutgoingStats, amount int64, now time.Time) domain.OutgoingStats {
+	stats.Total += amount
+	stats.Count++
+	if stats.Oldest.IsZero() {
+		stats.Oldest = now
+	}
+	return stats
+}
+
+// groupCheck returns the checks BatchTransfer and SplitTransfer run on each leg under the row locks.
+// Legs are saved only with the whole group, so the velocity limits of each sender are checked
+// at its first leg against all of its legs in the group.
+func (u *TransferUseCase) groupCheck(legs []movement) movementCheck {
+	sent := make(map[domain.AccountID][]int64)
+	for _, leg := range legs {
+		sent[leg.FromAccountID] = append(sent[leg.FromAccountID], leg.Amount)
+	}
+	checked := make(map[domain.AccountID]bool)
+	return func(ctx context.Context, _ movement, from, to *domain.Account) error {
+		if err := u.checkIntraTreePolicy(ctx, from, to); err != nil {
+			return err
+		}
+		if checked[from.ID] {
+			return nil
+		}
+		checked[from.ID] = true
+		return u.checkVelocityLimits(ctx, from, sent[from.ID]...)
+	}
+}
+
+// checkIntraTreePolicy rejects transfers between accounts of the same tree that the policy forbids.
+func (u *TransferUseCase) checkIntraTreePolicy(ctx context.Context, from, to *domain.Account) error {
+	if u.intraTreePolicy == IntraTreeTransfersAllowed {
+		return nil
+	}
+	if from.ParentID == nil && to.ParentID == nil {
+		return nil
+	}
+
+	fromRoot, err := accountTreeRoot(ctx, u.accountRepo, from)
+	if err != nil {
+		return err
+	}
+	toRoot, err := accountTreeRoot(ctx, u.accountRepo, to)
+	if err != nil {
+		return err
+	}
+	if fromRoot != toRoot {
+		return nil
+	}
+
+	if u.intraTreePolicy == IntraTreeTransfersParentChild {
+		if (from.ParentID != nil && *from.ParentID == to.ID) || (to.ParentID != nil && *to.ParentID == from.ID) {
+			return nil
+		}
+	}
+	return domain.ErrIntraTreeTransfer
+}
+
+func (u *TransferUseCase) poster() *poster {
+	return &poster{accountRepo: u.accountRepo, repo: u.repo, tm: u.tm, lots: u.lots}
+}
+
 func (u *TransferUseCase) GetJournalEntries(ctx context.Context, accountID domain.AccountID, limit, offset int) ([]*domain.JournalEntry, error) {
 	// Validate and normalize limit/offset
 	if limit <= 0 {
@@ -200,3 +874,12 @@ func (u *TransferUseCase) GetJournalEntries(ctx context.Context, accountID domai
 
 	return u.repo.FindByAccountID(ctx, accountID, limit, offset)
 }
+
+// FindJournalEntries returns the journal entries matching the filter, newest first.
+func (u *TransferUseCase) FindJournalEntries(ctx context.Context, filter domain.JournalEntryFilter, limit, offset int) ([]*domain.JournalEntry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	limit, offset = normalizePage(limit, offset)
+	return u.repo.FindJournalEntries(ctx, filter, limit, offset)
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
@@ -12,6 +14,7 @@ import (
 type mockJournalEntryRepo struct {
 	txs         map[string]*domain.JournalEntry
 	idempotency map[string]*domain.JournalEntry
+	groups      map[string]*domain.JournalGroup
 	lastTx      *domain.JournalEntry
 }
 
@@ -19,6 +22,7 @@ func newMockJournalEntryRepo() *mockJournalEntryRepo {
 	return &mockJournalEntryRepo{
 		txs:         make(map[string]*domain.JournalEntry),
 		idempotency: make(map[string]*domain.JournalEntry),
+		groups:      make(map[string]*domain.JournalGroup),
 	}
 }
 
@@ -26,6 +30,12 @@ func (m *mockJournalEntryRepo) SaveJournalEntry(ctx context.Context, tx *domain.
 	m.txs[tx.ID.String()] = tx
 	m.idempotency[tx.IdempotencyKey] = tx
 	m.lastTx = tx
+	if tx.ReversesID != nil {
+		if original, ok := m.txs[tx.ReversesID.String()]; ok {
+			original.ReversedByIDs = append(original.ReversedByIDs, tx.ID)
+			original.ReversedAmount += tx.Amount
+		}
+	}
 	return nil
 }
 
@@ -67,6 +77,28 @@ func (m *mockJournalEntryRepo) FindByAccountID(ctx context.Context, accountID do
 	return result[offset:end], nil
 }
 
+func (m *mockJournalEntryRepo) FindJournalEntries(ctx context.Context, filter domain.JournalEntryFilter, limit, offset int) ([]*domain.JournalEntry, error) {
+	var result []*domain.JournalEntry
+	for _, tx := range m.txs {
+		if filter.Matches(tx) {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockJournalEntryRepo) SaveJournalGroup(ctx context.Context, group *domain.JournalGroup) error {
+	m.groups[group.IdempotencyKey] = group
+	return nil
+}
+
+func (m *mockJournalEntryRepo) FindJournalGroupByIdempotencyKey(ctx context.Context, key string) (*domain.JournalGroup, error) {
+	if group, ok := m.groups[key]; ok {
+		return group, nil
+	}
+	return nil, nil
+}
+
 // mockTxManager implements domain.TransactionManagerStub
 type mockTxManager struct{}
 
@@ -89,11 +121,11 @@ func TestTransferUseCase_Transfer(t *testing.T) {
 	fromID := domain.AccountID(mustUUID("acc-from"))
 	toID := domain.AccountID(mustUUID("acc-to"))
 
-	fromAcc := domain.NewAccount(fromID, false)
+	fromAcc := domain.NewAccount(fromID, 0)
 	fromAcc.Balance = 1000
 	accRepo.SaveAccount(ctx, fromAcc)
 
-	toAcc := domain.NewAccount(toID, false)
+	toAcc := domain.NewAccount(toID, 0)
 	accRepo.SaveAccount(ctx, toAcc)
 
 	// 1. Success Transfer
@@ -197,3 +229,401 @@ func TestTransferUseCase_GetJournalEntries(t *testing.T) {
 		t.Errorf("expected 1 entry for acc-A, got %d", len(entriesA))
 	}
 }
+
+func TestTransferUseCase_Transfer_AccountStatus(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	tm := &mockTxManager{}
+	uc := NewTransferUseCase(accRepo, txRepo, tm)
+	ctx := context.Background()
+
+	frozenID := domain.AccountID(mustUUID("acc-frozen"))
+	activeID := domain.AccountID(mustUUID("acc-active"))
+	closedID := domain.AccountID(mustUUID("acc-closed"))
+
+	frozen := domain.NewAccount(frozenID, 0)
+	frozen.Balance = 100
+	frozen.Status = domain.AccountStatusFrozen
+	accRepo.SaveAccount(ctx, frozen)
+
+	active := domain.NewAccount(activeID, 0)
+	active.Balance = 100
+	accRepo.SaveAccount(ctx, active)
+
+	closed := domain.NewAccount(closedID, 0)
+	closed.Status = domain.AccountStatusClosed
+	accRepo.SaveAccount(ctx, closed)
+
+	// Debit from frozen account is rejected
+	_, err := uc.Transfer(ctx, TransferInput{
+		FromAccountID:  frozenID,
+		ToAccountID:    activeID,
+		Amount:         10,
+		IdempotencyKey: "status-1",
+	})
+	if err != domain.ErrAccountFrozen {
+		t.Errorf("expected ErrAccountFrozen, got %v", err)
+	}
+
+	// Credit to frozen account is allowed
+	_, err = uc.Transfer(ctx, TransferInput{
+		FromAccountID:  activeID,
+		ToAccountID:    frozenID,
+		Amount:         10,
+		IdempotencyKey: "status-2",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Credit to closed account is rejected
+	_, err = uc.Transfer(ctx, TransferInput{
+		FromAccountID:  activeID,
+		ToAccountID:    closedID,
+		Amount:         10,
+		IdempotencyKey: "status-3",
+	})
+	if err != domain.ErrAccountClosed {
+		t.Errorf("expected ErrAccountClosed, got %v", err)
+	}
+}
+
+func TestTransferUseCase_Transfer_AssetMismatch(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	pointID := domain.AccountID(mustUUID("acc-point"))
+	ticketID := domain.AccountID(mustUUID("acc-ticket"))
+
+	point := domain.NewAccount(pointID, 0)
+	point.Asset = domain.DefaultAssetCode
+	point.Balance = 100
+	accRepo.SaveAccount(ctx, point)
+
+	ticket := domain.NewAccount(ticketID, 0)
+	ticket.Asset = "event-ticket"
+	accRepo.SaveAccount(ctx, ticket)
+
+	_, err := uc.Transfer(ctx, TransferInput{
+		FromAccountID:  pointID,
+		ToAccountID:    ticketID,
+		Amount:         10,
+		IdempotencyKey: "asset-1",
+	})
+	if err != domain.ErrAssetMismatch {
+		t.Errorf("expected ErrAssetMismatch, got %v", err)
+	}
+	if point.Balance != 100 {
+		t.Errorf("balance should not change on error, got %d", point.Balance)
+	}
+}
+
+func TestTransferUseCase_Transfer_Preconditions(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	uc := NewTransferUseCase(accRepo, newMockJournalEntryRepo(), &mockTxManager{})
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("pre-from"))
+	toID := domain.AccountID(mustUUID("pre-to"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 100
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(toID, 0)
+	accRepo.SaveAccount(ctx, to)
+
+	version := from.Version
+	stale := version - 1
+	minBalance := int64(101)
+	tests := []struct {
+		name  string
+		input TransferInput
+	}{
+		{"stale source version", TransferInput{ExpectedFromVersion: &stale}},
+		{"stale destination version", TransferInput{ExpectedToVersion: &stale}},
+		{"balance below minimum", TransferInput{MinFromBalance: &minBalance}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			input.FromAccountID = fromID
+			input.ToAccountID = toID
+			input.Amount = 10
+			input.IdempotencyKey = fmt.Sprintf("pre-fail-%d", i)
+			_, err := uc.Transfer(ctx, input)
+			if !errors.Is(err, domain.ErrPreconditionFailed) {
+				t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+			}
+			if from.Balance != 100 || from.Version != version {
+				t.Errorf("account should not change on error, got balance %d version %d", from.Balance, from.Version)
+			}
+		})
+	}
+
+	minBalance = 100
+	input := TransferInput{
+		FromAccountID:       fromID,
+		ToAccountID:         toID,
+		Amount:              10,
+		IdempotencyKey:      "pre-ok",
+		ExpectedFromVersion: &version,
+		MinFromBalance:      &minBalance,
+	}
+	out, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 90 || from.Version != version+1 {
+		t.Errorf("expected balance 90 version %d, got balance %d version %d", version+1, from.Balance, from.Version)
+	}
+
+	// The version has moved on, but a replay returns the completed transfer.
+	replay, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on replay: %v", err)
+	}
+	if replay.JournalEntryID != out.JournalEntryID {
+		t.Errorf("replay returned a different entry")
+	}
+}
+
+func TestTransferUseCase_SimulateTransfer(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	feeRepo := &mockFeeRuleRepo{}
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{}, WithFeeRules(feeRepo))
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("sim-from"))
+	toID := domain.AccountID(mustUUID("sim-to"))
+	collectorID := domain.AccountID(mustUUID("sim-collector"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 100
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+	accRepo.SaveAccount(ctx, domain.NewAccount(collectorID, 0))
+	if _, err := NewFeeUseCase(accRepo, feeRepo).CreateFeeRule(ctx, CreateFeeRuleInput{
+		Name:               "flat",
+		FlatFee:            5,
+		CollectorAccountID: collectorID,
+	}); err != nil {
+		t.Fatalf("unexpected error creating rule: %v", err)
+	}
+	version := from.Version
+
+	input := TransferInput{FromAccountID: fromID, ToAccountID: toID, Amount: 60, IdempotencyKey: "sim-1"}
+	sim, err := uc.SimulateTransfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sim.Total != 65 || sim.FromBalance != 35 || sim.ToBalance != 60 || sim.CollectorBalance == nil || *sim.CollectorBalance != 5 {
+		t.Errorf("unexpected simulation %+v", sim)
+	}
+	if from.Balance != 100 || from.Version != version || len(txRepo.txs) != 0 {
+		t.Errorf("simulation must not change anything, got balance %d version %d and %d entries", from.Balance, from.Version, len(txRepo.txs))
+	}
+
+	// Transfer returns the same error for the same input.
+	input.Amount = 101
+	_, err = uc.SimulateTransfer(ctx, input)
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Errorf("expected ErrInsufficientBalance, got %v", err)
+	}
+	_, transferErr := uc.Transfer(ctx, input)
+	if err == nil || transferErr == nil || err.Error() != transferErr.Error() {
+		t.Errorf("expected the error of Transfer %v, got %v", transferErr, err)
+	}
+
+	// The key used by the simulation is still free.
+	input.Amount = 60
+	if _, err := uc.Transfer(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from.Balance != 35 {
+		t.Errorf("expected balance 35, got %d", from.Balance)
+	}
+}
+
+func TestTransferUseCase_Transfer_Metadata(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("meta-from"))
+	toID := domain.AccountID(mustUUID("meta-to"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 100
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+
+	for i, orderID := range []string{"123", "456"} {
+		_, err := uc.Transfer(ctx, TransferInput{
+			FromAccountID:  fromID,
+			ToAccountID:    toID,
+			Amount:         10,
+			IdempotencyKey: fmt.Sprintf("meta-%d", i),
+			Metadata:       map[string]string{"order_id": orderID},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	entries, err := uc.FindJournalEntries(ctx, domain.JournalEntryFilter{Metadata: map[string]string{"order_id": "123"}}, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].IdempotencyKey != "meta-0" || !entries[0].ValidateHash() {
+		t.Fatalf("expected the entry of order 123, got %v", entries)
+	}
+
+	_, err = uc.Transfer(ctx, TransferInput{
+		FromAccountID:  fromID,
+		ToAccountID:    toID,
+		Amount:         10,
+		IdempotencyKey: "meta-bad",
+		Metadata:       map[string]string{"Order ID": "789"},
+	})
+	if err != domain.ErrInvalidMetadata {
+		t.Errorf("expected ErrInvalidMetadata, got %v", err)
+	}
+	if _, err := uc.FindJournalEntries(ctx, domain.JournalEntryFilter{}, 10, 0); err != domain.ErrInvalidJournalEntryFilter {
+		t.Errorf("expected ErrInvalidJournalEntryFilter, got %v", err)
+	}
+}
+
+func TestTransferUseCase_Transfer_IdempotencyKeyReused(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{})
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("reuse-from"))
+	toID := domain.AccountID(mustUUID("reuse-to"))
+	otherID := domain.AccountID(mustUUID("reuse-other"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 100
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+	accRepo.SaveAccount(ctx, domain.NewAccount(otherID, 0))
+
+	input := TransferInput{
+		FromAccountID:  fromID,
+		ToAccountID:    toID,
+		Amount:         30,
+		Description:    "rent",
+		IdempotencyKey: "reuse-1",
+		Metadata:       map[string]string{"order_id": "1"},
+	}
+	out, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// An identical replay returns the original result
+	out2, err := uc.Transfer(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if out2.JournalEntryID != out.JournalEntryID {
+		t.Errorf("expected the original entry, got %v", out2.JournalEntryID)
+	}
+
+	changes := map[string]func(in *TransferInput){
+		"amount":      func(in *TransferInput) { in.Amount = 31 },
+		"recipient":   func(in *TransferInput) { in.ToAccountID = otherID },
+		"description": func(in *TransferInput) { in.Description = "deposit" },
+		"metadata":    func(in *TransferInput) { in.Metadata = map[string]string{"order_id": "2"} },
+	}
+	for name, change := range changes {
+		in := input
+		change(&in)
+		_, err := uc.Transfer(ctx, in)
+		var reusedErr *domain.IdempotencyKeyReusedError
+		if !errors.As(err, &reusedErr) || !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			t.Errorf("%s: expected IdempotencyKeyReusedError, got %v", name, err)
+			continue
+		}
+		if reusedErr.Key != "reuse-1" || reusedErr.JournalEntryID != out.JournalEntryID {
+			t.Errorf("%s: unexpected error details %+v", name, reusedErr)
+		}
+	}
+	if from.Balance != 70 {
+		t.Errorf("mismatched replays must not apply, balance %d", from.Balance)
+	}
+
+	// Entries posted before fingerprints were recorded replay as before
+	entry, _ := txRepo.FindByIdempotencyKey(ctx, "reuse-1")
+	entry.RequestFingerprint = ""
+	in := input
+	in.Amount = 31
+	if out3, err := uc.Transfer(ctx, in); err != nil || out3.JournalEntryID != out.JournalEntryID {
+		t.Errorf("expected the original entry for a legacy key, got %v, %v", out3, err)
+	}
+}
+
+func TestTransferUseCase_IdempotencyKeyReused_AcrossOperations(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	assetRepo := newMockAssetRepo()
+	tm := &mockTxManager{}
+	uc := NewTransferUseCase(accRepo, txRepo, tm)
+	issuanceUC := NewIssuanceUseCase(accRepo, txRepo, assetRepo, tm, nil)
+	ctx := context.Background()
+
+	issuerID := domain.AccountID(mustUUID("reuse-issuer"))
+	fromID := domain.AccountID(mustUUID("reuse-from"))
+	toID := domain.AccountID(mustUUID("reuse-to"))
+	for _, id := range []domain.AccountID{issuerID, fromID, toID} {
+		acc := domain.NewAccount(id, 0)
+		acc.Asset = domain.DefaultAssetCode
+		accRepo.SaveAccount(ctx, acc)
+	}
+	if _, err := NewAssetUseCase(assetRepo, accRepo, tm).SetAssetIssuer(ctx, domain.DefaultAssetCode, issuerID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A key first used by a mint is not a transfer's, even with the same accounts and amount
+	minted, err := issuanceUC.Mint(ctx, MintInput{Asset: domain.DefaultAssetCode, ToAccountID: fromID, Amount: 100, IdempotencyKey: "shared-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = uc.Transfer(ctx, TransferInput{FromAccountID: issuerID, ToAccountID: fromID, Amount: 100, IdempotencyKey: "shared-1"})
+	var reusedErr *domain.IdempotencyKeyReusedError
+	if !errors.As(err, &reusedErr) || reusedErr.JournalEntryID != minted.JournalEntryID {
+		t.Errorf("expected IdempotencyKeyReusedError for the mint entry, got %v", err)
+	}
+	if _, err := issuanceUC.Burn(ctx, BurnInput{Asset: domain.DefaultAssetCode, FromAccountID: fromID, Amount: 100, IdempotencyKey: "shared-1"}); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused for a burn, got %v", err)
+	}
+
+	batch := BatchTransferInput{
+		Legs:           []TransferLeg{{FromAccountID: fromID, ToAccountID: toID, Amount: 10}},
+		IdempotencyKey: "batch-reuse",
+	}
+	if _, err := uc.BatchTransfer(ctx, batch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.BatchTransfer(ctx, batch); err != nil {
+		t.Errorf("unexpected error on retry: %v", err)
+	}
+	batch.Legs = []TransferLeg{{FromAccountID: fromID, ToAccountID: toID, Amount: 20}}
+	if _, err := uc.BatchTransfer(ctx, batch); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused for other legs, got %v", err)
+	}
+	split := SplitTransferInput{
+		FromAccountID:  fromID,
+		Amount:         10,
+		Recipients:     []SplitRecipient{{AccountID: toID, Weight: 1}},
+		IdempotencyKey: "batch-reuse",
+	}
+	if _, err := uc.SplitTransfer(ctx, split); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused for a split, got %v", err)
+	}
+
+	from, _ := accRepo.FindAccountByID(ctx, fromID)
+	if from.Balance != 90 {
+		t.Errorf("rejected replays must not apply, balance %d", from.Balance)
+	}
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/traP-jp/plutus/system/cornucopia/internal/domain"
+)
+
+// mockVelocityLimitRepo aggregates the entries saved in a mockJournalEntryRepo.
+type mockVelocityLimitRepo struct {
+	limits  map[domain.AccountID]*domain.VelocityLimits
+	journal *mockJournalEntryRepo
+}
+
+func (m *mockVelocityLimitRepo) FindVelocityLimits(ctx context.Context, accountID domain.AccountID) (*domain.VelocityLimits, error) {
+	return m.limits[accountID], nil
+}
+
+func (m *mockVelocityLimitRepo) SaveVelocityLimits(ctx context.Context, accountID domain.AccountID, limits *domain.VelocityLimits) error {
+	m.limits[accountID] = limits
+	return nil
+}
+
+func (m *mockVelocityLimitRepo) GetOutgoingTransferStats(ctx context.Context, accountID domain.AccountID, since time.Time) (*domain.OutgoingStats, error) {
+	var stats domain.OutgoingStats
+	for _, entry := range m.journal.txs {
+		if entry.FromAccountID != accountID || entry.Timestamp.Before(since) {
+			continue
+		}
+		switch entry.Type {
+		case domain.EntryTypeTransfer, domain.EntryTypeEscrow:
+			stats.Count++
+		case domain.EntryTypeFee:
+		default:
+			continue
+		}
+		stats.Total += entry.Amount
+		if stats.Oldest.IsZero() || entry.Timestamp.Before(stats.Oldest) {
+			stats.Oldest = entry.Timestamp
+		}
+	}
+	return &stats, nil
+}
+
+func TestTransferUseCase_Transfer_VelocityLimits(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	limitRepo := &mockVelocityLimitRepo{limits: make(map[domain.AccountID]*domain.VelocityLimits), journal: txRepo}
+	perDay := int64(250)
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{},
+		WithVelocityLimits(limitRepo, domain.VelocityLimits{MaxOutgoingPerDay: &perDay}))
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("from"))
+	toID := domain.AccountID(mustUUID("to"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+
+	perHour := int64(2)
+	if _, err := uc.SetVelocityLimits(ctx, fromID, domain.VelocityLimits{MaxTransfersPerHour: &perHour}); err != nil {
+		t.Fatalf("unexpected error setting limits: %v", err)
+	}
+	limits, err := uc.GetVelocityLimits(ctx, fromID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits.Own.MaxOutgoingPerDay != nil || *limits.Effective.MaxOutgoingPerDay != 250 || *limits.Effective.MaxTransfersPerHour != 2 {
+		t.Errorf("unexpected limits %+v", limits)
+	}
+
+	transfer := func(key string, amount int64) error {
+		_, err := uc.Transfer(ctx, TransferInput{FromAccountID: fromID, ToAccountID: toID, Amount: amount, IdempotencyKey: key})
+		return err
+	}
+	if err := transfer("v-1", 200); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The account default of 250 a day applies
+	err = transfer("v-2", 60)
+	var limitErr *domain.VelocityLimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitOutgoingPerDay {
+		t.Fatalf("expected the daily limit to be hit, got %v", err)
+	}
+	first := txRepo.idempotency["v-1"]
+	if !limitErr.ResetsAt.Equal(first.Timestamp.Add(24 * time.Hour)) {
+		t.Errorf("expected reset 24h after the first transfer, got %v", limitErr.ResetsAt)
+	}
+	if from.Balance != 800 {
+		t.Errorf("rejected transfer must not apply, balance %d", from.Balance)
+	}
+
+	if err := transfer("v-3", 40); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = transfer("v-4", 1)
+	if !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitTransfersPerHour {
+		t.Errorf("expected the hourly count limit to be hit, got %v", err)
+	}
+
+	// Replays are not counted again
+	if err := transfer("v-1", 200); err != nil {
+		t.Errorf("unexpected error on retry: %v", err)
+	}
+}
+
+func TestTransferUseCase_BatchTransfer_VelocityLimits(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	limitRepo := &mockVelocityLimitRepo{limits: make(map[domain.AccountID]*domain.VelocityLimits), journal: txRepo}
+	perTransfer, perDay := int64(100), int64(250)
+	uc := NewTransferUseCase(accRepo, txRepo, &mockTxManager{},
+		WithVelocityLimits(limitRepo, domain.VelocityLimits{MaxPerTransfer: &perTransfer, MaxOutgoingPerDay: &perDay}))
+	ctx := context.Background()
+
+	fromID := domain.AccountID(mustUUID("from"))
+	toID := domain.AccountID(mustUUID("to"))
+	otherID := domain.AccountID(mustUUID("other"))
+	from := domain.NewAccount(fromID, 0)
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	accRepo.SaveAccount(ctx, domain.NewAccount(toID, 0))
+	accRepo.SaveAccount(ctx, domain.NewAccount(otherID, 0))
+
+	batch := func(key string, amounts ...int64) error {
+		legs := make([]TransferLeg, len(amounts))
+		for i, amount := range amounts {
+			legs[i] = TransferLeg{FromAccountID: fromID, ToAccountID: toID, Amount: amount}
+		}
+		_, err := uc.BatchTransfer(ctx, BatchTransferInput{Legs: legs, IdempotencyKey: key})
+		return err
+	}
+	var limitErr *domain.VelocityLimitError
+	if err := batch("vb-1", 150); !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitPerTransfer {
+		t.Errorf("expected the per-transfer limit to be hit, got %v", err)
+	}
+	// The legs add up against the daily limit
+	if err := batch("vb-2", 100, 100, 100); !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitOutgoingPerDay {
+		t.Errorf("expected the daily limit to be hit, got %v", err)
+	}
+	if err := batch("vb-3", 100, 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := uc.SplitTransfer(ctx, SplitTransferInput{
+		FromAccountID:  fromID,
+		Amount:         100,
+		Recipients:     []SplitRecipient{{AccountID: toID, Weight: 1}, {AccountID: otherID, Weight: 1}},
+		IdempotencyKey: "vs-1",
+	})
+	if !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitOutgoingPerDay {
+		t.Errorf("expected the daily limit to be hit, got %v", err)
+	}
+	if from.Balance != 800 {
+		t.Errorf("rejected batches must not apply, balance %d", from.Balance)
+	}
+}
+
+func TestVelocityLimits_CaptureAndEscrow(t *testing.T) {
+	accRepo := newMockAccountRepo()
+	txRepo := newMockJournalEntryRepo()
+	limitRepo := &mockVelocityLimitRepo{limits: make(map[domain.AccountID]*domain.VelocityLimits), journal: txRepo}
+	perTransfer, perDay := int64(200), int64(300)
+	transferUC := NewTransferUseCase(accRepo, txRepo, &mockTxManager{},
+		WithVelocityLimits(limitRepo, domain.VelocityLimits{MaxPerTransfer: &perTransfer, MaxOutgoingPerDay: &perDay}))
+	holdUC := NewHoldUseCase(accRepo, txRepo, newMockHoldRepo(), &mockTxManager{}, nil, WithCaptureVelocityLimits(transferUC))
+	escrowUC := NewEscrowUseCase(accRepo, txRepo, newMockAssetRepo(), newMockEscrowRepo(), &mockTxManager{}, nil, WithEscrowVelocityLimits(transferUC))
+	ctx := context.Background()
+
+	from := domain.NewAccount(domain.AccountID(mustUUID("from")), 0)
+	from.Asset = domain.DefaultAssetCode
+	from.Balance = 1000
+	accRepo.SaveAccount(ctx, from)
+	to := domain.NewAccount(domain.AccountID(mustUUID("to")), 0)
+	to.Asset = domain.DefaultAssetCode
+	accRepo.SaveAccount(ctx, to)
+
+	// A capture is a transfer of the sender's
+	hold, err := holdUC.Authorize(ctx, AuthorizeInput{AccountID: from.ID, ToAccountID: to.ID, Amount: 250, IdempotencyKey: "vh-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var limitErr *domain.VelocityLimitError
+	if _, err := holdUC.Capture(ctx, CaptureInput{HoldID: hold.ID}); !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitPerTransfer {
+		t.Errorf("expected the per-transfer limit to be hit, got %v", err)
+	}
+	partial := int64(150)
+	if _, err := holdUC.Capture(ctx, CaptureInput{HoldID: hold.ID, Amount: &partial}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// So is funding an escrow, and it counts against the daily limit
+	escrow := func(key string, amount int64) error {
+		_, err := escrowUC.CreateEscrow(ctx, CreateEscrowInput{
+			PayerAccountID: from.ID, BeneficiaryAccountID: to.ID, Amount: amount,
+			Deadline: time.Now().Add(time.Hour), IdempotencyKey: key,
+		})
+		return err
+	}
+	if err := escrow("ve-1", 200); !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitOutgoingPerDay {
+		t.Errorf("expected the daily limit to be hit, got %v", err)
+	}
+	if err := escrow("ve-2", 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = transferUC.Transfer(ctx, TransferInput{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100, IdempotencyKey: "vt-1"})
+	if !errors.As(err, &limitErr) || limitErr.Limit != domain.VelocityLimitOutgoingPerDay {
+		t.Errorf("expected the daily limit to be hit, got %v", err)
+	}
+	if from.Balance != 750 {
+		t.Errorf("expected balance 750, got %d", from.Balance)
+	}
+}
+
+func TestTransferUseCase_SetVelocityLimits_NotConfigured(t *testing.T) {
+	uc := NewTransferUseCase(newMockAccountRepo(), newMockJournalEntryRepo(), &mockTxManager{})
+	perHour := int64(1)
+	_, err := uc.SetVelocityLimits(context.Background(), domain.AccountID(mustUUID("from")), domain.VelocityLimits{MaxTransfersPerHour: &perHour})
+	if !errors.Is(err, domain.ErrVelocityLimitsNotConfigured) {
+		t.Errorf("expected ErrVelocityLimitsNotConfigured, got %v", err)
+	}
+}